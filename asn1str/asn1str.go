@@ -0,0 +1,179 @@
+// Package asn1str marshals and parses the ASN.1 BMPString and
+// UniversalString types. encoding/asn1 parses a BMPString into a string but
+// cannot marshal one, and does not support UniversalString at all.
+//
+// A BMPString is big-endian UCS-2 and a UniversalString is big-endian
+// UTF-32. They are found in X.509 certificates, for example in
+// DirectoryString values, and in PKCS#12 files. The functions in this
+// package operate on asn1.RawValue so that they can be used with structures
+// that declare these fields as asn1.RawValue:
+//
+//	var name struct {
+//		Value asn1.RawValue
+//	}
+//	if _, err := asn1.Unmarshal(der, &name); err != nil {
+//		return err
+//	}
+//	s, err := asn1str.ParseBMPString(name.Value)
+//
+// BMPString is limited to the Basic Multilingual Plane, but many encoders
+// write characters outside of it as UTF-16 surrogate pairs, which
+// encoding/asn1 rejects. ParseBMPString and BMPString accept pairs and
+// ParseBMPString reports an unpaired surrogate as an error, while the Strict
+// variants follow the standard and reject every surrogate.
+package asn1str
+
+import (
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// TagUniversalString is the ASN.1 tag of UniversalString, which
+// encoding/asn1 does not define.
+const TagUniversalString = 28
+
+var (
+	errSurrogate    = errors.New("asn1str: BMPString contains a surrogate")
+	errUnpaired     = errors.New("asn1str: BMPString contains an unpaired surrogate")
+	errOutsideBMP   = errors.New("asn1str: character outside the BMP cannot be encoded as UCS-2")
+	errInvalidUTF32 = errors.New("asn1str: UniversalString contains an invalid code point")
+)
+
+// checkTag returns an error if v is not a primitive universal value with the
+// given tag.
+func checkTag(v asn1.RawValue, tag int, name string) error {
+	if v.Class != asn1.ClassUniversal || v.Tag != tag || v.IsCompound {
+		return fmt.Errorf("asn1str: expected %s, got class %d tag %d (compound: %t)",
+			name, v.Class, v.Tag, v.IsCompound)
+	}
+	return nil
+}
+
+// rawValue returns a primitive universal value with the given tag and
+// contents.
+func rawValue(tag int, b []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassUniversal, Tag: tag, Bytes: b}
+}
+
+// decodeBMP returns the code units of the BMPString contents b.
+func decodeBMP(b []byte) ([]uint16, error) {
+	if len(b)%2 != 0 {
+		return nil, fmt.Errorf("asn1str: BMPString has odd length %d", len(b))
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+	return u, nil
+}
+
+// ParseBMPString returns the UTF-8 encoding of the BMPString v. Surrogate
+// pairs are decoded as UTF-16 and an unpaired surrogate is an error.
+func ParseBMPString(v asn1.RawValue) (string, error) {
+	if err := checkTag(v, asn1.TagBMPString, "BMPString"); err != nil {
+		return "", err
+	}
+	u, err := decodeBMP(v.Bytes)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < len(u); {
+		_, n := utfconv.DecodeRuneUTF16(u[i:])
+		if n == 1 && utf16.IsSurrogate(rune(u[i])) {
+			return "", errUnpaired
+		}
+		i += n
+	}
+	return utfconv.UTF16ToString(u), nil
+}
+
+// ParseBMPStringStrict is like ParseBMPString but decodes v as UCS-2, as
+// the standard requires, and returns an error if it contains any surrogate.
+func ParseBMPStringStrict(v asn1.RawValue) (string, error) {
+	if err := checkTag(v, asn1.TagBMPString, "BMPString"); err != nil {
+		return "", err
+	}
+	u, err := decodeBMP(v.Bytes)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range u {
+		if utf16.IsSurrogate(rune(c)) {
+			return "", errSurrogate
+		}
+	}
+	return utfconv.UTF16ToString(u), nil
+}
+
+// BMPString returns s as a BMPString value. Characters outside of the Basic
+// Multilingual Plane are encoded as surrogate pairs and invalid UTF-8 is
+// encoded as U+FFFD.
+func BMPString(s string) asn1.RawValue {
+	u := utfconv.StringToUTF16(s)
+	b := make([]byte, 0, 2*len(u))
+	for _, c := range u {
+		b = binary.BigEndian.AppendUint16(b, c)
+	}
+	return rawValue(asn1.TagBMPString, b)
+}
+
+// BMPStringStrict returns s as a UCS-2 BMPString value. It returns an error
+// if s contains a character outside of the Basic Multilingual Plane.
+func BMPStringStrict(s string) (asn1.RawValue, error) {
+	for _, r := range s {
+		if r > 0xFFFF {
+			return asn1.RawValue{}, errOutsideBMP
+		}
+	}
+	return BMPString(s), nil
+}
+
+// ParseUniversalString returns the UTF-8 encoding of the UniversalString v.
+// It returns an error if v contains a surrogate or a value greater than
+// U+10FFFF.
+func ParseUniversalString(v asn1.RawValue) (string, error) {
+	if err := checkTag(v, TagUniversalString, "UniversalString"); err != nil {
+		return "", err
+	}
+	b := v.Bytes
+	if len(b)%4 != 0 {
+		return "", fmt.Errorf("asn1str: UniversalString length %d is not a multiple of 4", len(b))
+	}
+	p := make([]byte, 0, len(b)/4)
+	for i := 0; i < len(b); i += 4 {
+		c := binary.BigEndian.Uint32(b[i:])
+		if c > utf8.MaxRune || !utf8.ValidRune(rune(c)) {
+			return "", errInvalidUTF32
+		}
+		p = utf8.AppendRune(p, rune(c))
+	}
+	return string(p), nil
+}
+
+// UniversalString returns s as a UniversalString value. Invalid UTF-8 is
+// encoded as U+FFFD.
+func UniversalString(s string) asn1.RawValue {
+	b := make([]byte, 0, 4*utf8.RuneCountInString(s))
+	for _, r := range s {
+		b = binary.BigEndian.AppendUint32(b, uint32(r))
+	}
+	return rawValue(TagUniversalString, b)
+}
+
+// MarshalBMPString returns the DER encoding of s as a BMPString. See
+// BMPString.
+func MarshalBMPString(s string) ([]byte, error) {
+	return asn1.Marshal(BMPString(s))
+}
+
+// MarshalUniversalString returns the DER encoding of s as a
+// UniversalString.
+func MarshalUniversalString(s string) ([]byte, error) {
+	return asn1.Marshal(UniversalString(s))
+}
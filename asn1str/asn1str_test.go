@@ -0,0 +1,137 @@
+package asn1str
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"testing"
+)
+
+func TestBMPString(t *testing.T) {
+	tests := []struct {
+		s   string
+		der []byte
+	}{
+		{"", []byte{0x1e, 0x00}},
+		{"ab", []byte{0x1e, 0x04, 0x00, 'a', 0x00, 'b'}},
+		{"é日", []byte{0x1e, 0x04, 0x00, 0xe9, 0x65, 0xe5}},
+		{"\U0001F600", []byte{0x1e, 0x04, 0xd8, 0x3d, 0xde, 0x00}},
+	}
+	for _, x := range tests {
+		der, err := MarshalBMPString(x.s)
+		if err != nil || !bytes.Equal(der, x.der) {
+			t.Errorf("MarshalBMPString(%q) = %x, %v; want: %x, <nil>", x.s, der, err, x.der)
+		}
+		var v asn1.RawValue
+		if _, err := asn1.Unmarshal(x.der, &v); err != nil {
+			t.Fatal(err)
+		}
+		if s, err := ParseBMPString(v); err != nil || s != x.s {
+			t.Errorf("ParseBMPString(%x) = %q, %v; want: %q, <nil>", x.der, s, err, x.s)
+		}
+	}
+	// encoding/asn1 decodes BMPStrings into a string.
+	var s string
+	if _, err := asn1.Unmarshal(tests[2].der, &s); err != nil || s != tests[2].s {
+		t.Errorf("asn1.Unmarshal(%x) = %q, %v; want: %q, <nil>", tests[2].der, s, err, tests[2].s)
+	}
+}
+
+func TestBMPStringStrict(t *testing.T) {
+	if v, err := BMPStringStrict("é日"); err != nil || !bytes.Equal(v.Bytes, BMPString("é日").Bytes) {
+		t.Errorf("BMPStringStrict(%q) = %x, %v", "é日", v.Bytes, err)
+	}
+	if _, err := BMPStringStrict("a\U0001F600"); err == nil {
+		t.Errorf("BMPStringStrict(%q): expected error", "a\U0001F600")
+	}
+	pair := BMPString("\U0001F600")
+	if s, err := ParseBMPStringStrict(pair); err == nil {
+		t.Errorf("ParseBMPStringStrict(%x) = %q; expected error", pair.Bytes, s)
+	}
+	v := BMPString("\uFFFDz")
+	if s, err := ParseBMPStringStrict(v); err != nil || s != "\uFFFDz" {
+		t.Errorf("ParseBMPStringStrict(%x) = %q, %v; want: %q, <nil>", v.Bytes, s, err, "\uFFFDz")
+	}
+}
+
+func TestBMPStringErrors(t *testing.T) {
+	tests := []asn1.RawValue{
+		rawValue(asn1.TagBMPString, []byte{0x00, 'a', 0x00}),       // odd length
+		rawValue(asn1.TagBMPString, []byte{0xd8, 0x3d}),            // unpaired high
+		rawValue(asn1.TagBMPString, []byte{0xde, 0x00, 0x00, 'a'}), // unpaired low
+		rawValue(asn1.TagBMPString, []byte{0xd8, 0x3d, 0x00, 'a'}), // high followed by BMP
+		rawValue(asn1.TagUTF8String, []byte{0x00, 'a'}),            // wrong tag
+		{Class: asn1.ClassContextSpecific, Tag: asn1.TagBMPString}, // wrong class
+		{Class: asn1.ClassUniversal, Tag: asn1.TagBMPString, IsCompound: true},
+	}
+	for _, v := range tests {
+		if s, err := ParseBMPString(v); err == nil {
+			t.Errorf("ParseBMPString(%+v) = %q; expected error", v, s)
+		}
+		if s, err := ParseBMPStringStrict(v); err == nil {
+			t.Errorf("ParseBMPStringStrict(%+v) = %q; expected error", v, s)
+		}
+	}
+}
+
+func TestUniversalString(t *testing.T) {
+	tests := []struct {
+		s   string
+		der []byte
+	}{
+		{"", []byte{0x1c, 0x00}},
+		{"a", []byte{0x1c, 0x04, 0, 0, 0, 'a'}},
+		{"日\U0001F600", []byte{0x1c, 0x08, 0, 0, 0x65, 0xe5, 0, 0x01, 0xf6, 0x00}},
+	}
+	for _, x := range tests {
+		der, err := MarshalUniversalString(x.s)
+		if err != nil || !bytes.Equal(der, x.der) {
+			t.Errorf("MarshalUniversalString(%q) = %x, %v; want: %x, <nil>", x.s, der, err, x.der)
+		}
+		var v asn1.RawValue
+		if _, err := asn1.Unmarshal(x.der, &v); err != nil {
+			t.Fatal(err)
+		}
+		if s, err := ParseUniversalString(v); err != nil || s != x.s {
+			t.Errorf("ParseUniversalString(%x) = %q, %v; want: %q, <nil>", x.der, s, err, x.s)
+		}
+	}
+	if v := UniversalString("a\xff"); !bytes.Equal(v.Bytes, []byte{0, 0, 0, 'a', 0, 0, 0xff, 0xfd}) {
+		t.Errorf("UniversalString(%q) = %x", "a\xff", v.Bytes)
+	}
+
+	errTests := []asn1.RawValue{
+		rawValue(TagUniversalString, []byte{0, 0, 'a'}),
+		rawValue(TagUniversalString, []byte{0, 0, 0xd8, 0x00}),
+		rawValue(TagUniversalString, []byte{0, 0x11, 0, 0}),
+		rawValue(TagUniversalString, []byte{0xff, 0xff, 0xff, 0xff}),
+		rawValue(asn1.TagBMPString, []byte{0, 0, 0, 'a'}),
+	}
+	for _, v := range errTests {
+		if s, err := ParseUniversalString(v); err == nil {
+			t.Errorf("ParseUniversalString(%+v) = %q; expected error", v, s)
+		}
+	}
+}
+
+// Test a BMPString in a structure.
+func TestStruct(t *testing.T) {
+	type attribute struct {
+		ID    asn1.ObjectIdentifier
+		Value asn1.RawValue
+	}
+	in := attribute{
+		ID:    asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20},
+		Value: BMPString("ключ \U0001F511"),
+	}
+	der, err := asn1.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out attribute
+	if _, err := asn1.Unmarshal(der, &out); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := ParseBMPString(out.Value); err != nil || s != "ключ \U0001F511" {
+		t.Errorf("ParseBMPString = %q, %v; want: %q, <nil>", s, err, "ключ \U0001F511")
+	}
+}
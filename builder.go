@@ -0,0 +1,192 @@
+package utfconv
+
+import "unicode/utf8"
+
+// A UTF16Builder is used to efficiently build a UTF-16 slice from UTF-8
+// text, runes and UTF-16 code units, such as a string passed to a Windows
+// API. Invalid UTF-8 and invalid runes are written as U+FFFD, as with
+// StringToUTF16. The zero value is ready to use.
+//
+// Bytes written with Write, WriteString and WriteByte are decoded as one
+// stream, so a UTF-8 sequence may be split across calls. An incomplete
+// sequence is written as U+FFFD once it is followed by a call to WriteRune,
+// WriteUTF16, UTF16, UTF16Z or String.
+//
+// Slices returned by UTF16 and UTF16Z are not modified by later writes.
+type UTF16Builder struct {
+	buf   []uint16
+	pend  [utf8.UTFMax]byte // incomplete UTF-8 sequence
+	npend int
+}
+
+// Len returns the number of accumulated code units, not including an
+// incomplete UTF-8 sequence.
+func (b *UTF16Builder) Len() int { return len(b.buf) }
+
+// Cap returns the capacity of the builder's underlying slice in code units.
+func (b *UTF16Builder) Cap() int { return cap(b.buf) }
+
+// Reset resets the builder to be empty.
+func (b *UTF16Builder) Reset() {
+	b.buf = nil
+	b.npend = 0
+}
+
+// UTF16 returns the accumulated code units. The result shares the builder's
+// underlying array.
+func (b *UTF16Builder) UTF16() []uint16 {
+	b.flush()
+	return b.buf
+}
+
+// UTF16Z returns the accumulated code units followed by a terminating NUL,
+// as expected by Windows APIs. The NUL is not part of the builder's
+// contents. The result shares the builder's underlying array, which is
+// reallocated by the next write.
+func (b *UTF16Builder) UTF16Z() []uint16 {
+	b.flush()
+	n := len(b.buf)
+	z := append(b.buf, 0)
+	b.buf = z[:n:n]
+	return z
+}
+
+// String returns the accumulated code units converted to UTF-8.
+func (b *UTF16Builder) String() string { return UTF16ToString(b.UTF16()) }
+
+// flush writes the pending incomplete UTF-8 sequence, if any, as U+FFFD
+// for each byte.
+func (b *UTF16Builder) flush() {
+	for ; b.npend > 0; b.npend-- {
+		b.buf = append(b.buf, runeError)
+	}
+}
+
+// complete continues the pending incomplete UTF-8 sequence with the bytes
+// of p and returns the number of bytes of p consumed. The pending sequence
+// is left incomplete only if all of p is consumed.
+func (b *UTF16Builder) complete(p []byte) int {
+	i := 0
+	for b.npend > 0 && i < len(p) {
+		k := copy(b.pend[b.npend:], p[i:])
+		q := b.pend[:b.npend+k]
+		if !utf8.FullRune(q) {
+			b.npend += k
+			return len(p)
+		}
+		r, n := utf8.DecodeRune(q)
+		b.buf = AppendRuneUTF16(b.buf, r)
+		if n >= b.npend {
+			i += n - b.npend
+			b.npend = 0
+		} else {
+			// invalid sequence: decode the rest of the pending bytes
+			b.npend = copy(b.pend[:], b.pend[n:b.npend])
+		}
+	}
+	return i
+}
+
+func (b *UTF16Builder) grow(n int) {
+	a := make([]uint16, len(b.buf), 2*cap(b.buf)+n)
+	copy(a, b.buf)
+	b.buf = a
+}
+
+// Grow grows the builder's capacity, if necessary, to guarantee space for
+// another n code units. If n is negative, Grow panics.
+func (b *UTF16Builder) Grow(n int) {
+	if n < 0 {
+		panic("utfconv.UTF16Builder.Grow: negative count")
+	}
+	if cap(b.buf)-len(b.buf) < n {
+		b.grow(n)
+	}
+}
+
+// Write appends the UTF-8 encoded text p to b. It returns len(p) and a nil
+// error.
+func (b *UTF16Builder) Write(p []byte) (int, error) {
+	m := len(p)
+	if b.npend > 0 {
+		p = p[b.complete(p):]
+	}
+	b.Grow(UTF16EncodedLen(p))
+	for i := 0; i < len(p); {
+		if c := p[i]; c < runeSelf {
+			b.buf = append(b.buf, uint16(c))
+			i++
+			continue
+		}
+		if !utf8.FullRune(p[i:]) {
+			b.npend = copy(b.pend[:], p[i:])
+			break
+		}
+		r, n := utf8.DecodeRune(p[i:])
+		b.buf = AppendRuneUTF16(b.buf, r)
+		i += n
+	}
+	return m, nil
+}
+
+// WriteString appends the string s to b. It returns len(s) and a nil error.
+func (b *UTF16Builder) WriteString(s string) (int, error) {
+	m := len(s)
+	if b.npend > 0 {
+		var head [utf8.UTFMax]byte
+		s = s[b.complete(head[:copy(head[:], s)]):]
+	}
+	n, ascii := encodedLenString(s)
+	b.Grow(n)
+	if ascii {
+		for i := 0; i < len(s); i++ {
+			b.buf = append(b.buf, uint16(s[i]))
+		}
+		return m, nil
+	}
+	for i := 0; i < len(s); {
+		if c := s[i]; c < runeSelf {
+			b.buf = append(b.buf, uint16(c))
+			i++
+			continue
+		}
+		if !utf8.FullRuneInString(s[i:]) {
+			b.npend = copy(b.pend[:], s[i:])
+			break
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		b.buf = AppendRuneUTF16(b.buf, r)
+		i += n
+	}
+	return m, nil
+}
+
+// WriteByte appends the byte c of UTF-8 encoded text to b. A byte that
+// starts or continues a multi-byte sequence is held until the sequence is
+// complete. It always returns nil.
+func (b *UTF16Builder) WriteByte(c byte) error {
+	if c < runeSelf && b.npend == 0 {
+		b.buf = append(b.buf, uint16(c))
+		return nil
+	}
+	p := [1]byte{c}
+	b.Write(p[:])
+	return nil
+}
+
+// WriteRune appends the UTF-16 encoding of r to b. It returns the number of
+// code units written and a nil error.
+func (b *UTF16Builder) WriteRune(r rune) (int, error) {
+	b.flush()
+	n := len(b.buf)
+	b.buf = AppendRuneUTF16(b.buf, r)
+	return len(b.buf) - n, nil
+}
+
+// WriteUTF16 appends the code units of s to b. It returns len(s) and a nil
+// error.
+func (b *UTF16Builder) WriteUTF16(s []uint16) (int, error) {
+	b.flush()
+	b.buf = append(b.buf, s...)
+	return len(s), nil
+}
@@ -0,0 +1,178 @@
+package utfconv
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+var (
+	_ io.Writer       = (*UTF16Builder)(nil)
+	_ io.StringWriter = (*UTF16Builder)(nil)
+	_ io.ByteWriter   = (*UTF16Builder)(nil)
+	_ fmt.Stringer    = (*UTF16Builder)(nil)
+)
+
+func TestUTF16Builder(t *testing.T) {
+	var b UTF16Builder
+	if b.Len() != 0 || b.UTF16() != nil {
+		t.Fatalf("zero UTF16Builder: Len() = %d, UTF16() = %v", b.Len(), b.UTF16())
+	}
+	b.WriteString("abc ")
+	b.WriteString("日本\xff ")
+	b.Write([]byte("héllo\xed\xa0\x80 "))
+	b.WriteByte('x')
+	b.WriteByte(0xe9)
+	if n, _ := b.WriteRune(0x1F600); n != 2 {
+		t.Errorf("WriteRune(%U) = %d; want: 2", 0x1F600, n)
+	}
+	if n, _ := b.WriteRune(0xD800); n != 1 {
+		t.Errorf("WriteRune(%U) = %d; want: 1", 0xD800, n)
+	}
+	b.WriteRune(-1)
+	b.WriteUTF16([]uint16{' ', 0xDC00})
+
+	want := utf16.Encode([]rune("abc 日本\uFFFD héllo\uFFFD\uFFFD\uFFFD x\uFFFD\U0001F600\uFFFD\uFFFD "))
+	want = append(want, 0xDC00)
+	if got := b.UTF16(); !reflect.DeepEqual(got, want) {
+		t.Errorf("UTF16() = %v; want: %v", got, want)
+	}
+	if b.Len() != len(want) {
+		t.Errorf("Len() = %d; want: %d", b.Len(), len(want))
+	}
+	if s, exp := b.String(), UTF16ToString(want); s != exp {
+		t.Errorf("String() = %q; want: %q", s, exp)
+	}
+
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("Len() after Reset = %d; want: 0", b.Len())
+	}
+}
+
+// Text split into bytes or chunks at any offset must be written like the
+// whole text.
+func TestUTF16BuilderSplitUTF8(t *testing.T) {
+	for _, s := range []string{
+		"abc",
+		"日本語",
+		"a\U0001F600b\U0001F601",
+		"\xe6\x97a\xf0\x9f\x98",
+		"\xff\xe6\x97\xe6\x97\xa5\xed\xa0\x80",
+		"\xf0\x9f\x98\x80\x80\xf4\x90\x80\x80",
+		"x\xe6",
+	} {
+		want := StringToUTF16(s)
+
+		var b UTF16Builder
+		for i := 0; i < len(s); i++ {
+			b.WriteByte(s[i])
+		}
+		if got := b.UTF16(); !reflect.DeepEqual(got, want) {
+			t.Errorf("WriteByte(%q...) = %v; want: %v", s, got, want)
+		}
+		for i := 0; i <= len(s); i++ {
+			b.Reset()
+			b.Write([]byte(s[:i]))
+			b.Write([]byte(s[i:]))
+			if got := b.UTF16(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Write(%q) + Write(%q) = %v; want: %v", s[:i], s[i:], got, want)
+			}
+			b.Reset()
+			b.WriteString(s[:i])
+			b.WriteString(s[i:])
+			if got := b.String(); got != UTF16ToString(want) {
+				t.Errorf("WriteString(%q) + WriteString(%q) = %q; want: %q",
+					s[:i], s[i:], got, UTF16ToString(want))
+			}
+		}
+	}
+
+	// an incomplete sequence is written before runes and code units
+	var b UTF16Builder
+	b.WriteByte(0xe6)
+	b.WriteByte(0x97)
+	b.WriteRune('a')
+	b.WriteByte(0xf0)
+	b.WriteUTF16([]uint16{'b'})
+	b.WriteByte(0xe6)
+	if b.Len() != 5 {
+		t.Errorf("Len() = %d; want: 5", b.Len())
+	}
+	want := []uint16{runeError, runeError, 'a', runeError, 'b', runeError}
+	if got := b.UTF16Z(); !reflect.DeepEqual(got, append(want, 0)) {
+		t.Errorf("UTF16Z() = %v; want: %v", got, append(want, 0))
+	}
+	// a continuation byte written after the contents were read is invalid
+	b.WriteByte(0x97)
+	b.WriteByte(0xa5)
+	want = append(want, runeError, runeError)
+	if got := b.UTF16(); !reflect.DeepEqual(got, want) {
+		t.Errorf("UTF16() = %v; want: %v", got, want)
+	}
+}
+
+func TestUTF16BuilderUTF16Z(t *testing.T) {
+	var b UTF16Builder
+	if z := b.UTF16Z(); !reflect.DeepEqual(z, []uint16{0}) {
+		t.Errorf("UTF16Z() = %v; want: %v", z, []uint16{0})
+	}
+	b.Grow(16)
+	b.WriteString("ab")
+	z := b.UTF16Z()
+	if !reflect.DeepEqual(z, []uint16{'a', 'b', 0}) {
+		t.Errorf("UTF16Z() = %v; want: %v", z, []uint16{'a', 'b', 0})
+	}
+	if b.Len() != 2 {
+		t.Errorf("Len() after UTF16Z = %d; want: 2", b.Len())
+	}
+	// later writes must not overwrite the NUL
+	b.WriteString("cd")
+	if !reflect.DeepEqual(z, []uint16{'a', 'b', 0}) {
+		t.Errorf("UTF16Z() result modified by write: %v", z)
+	}
+	if got := b.UTF16(); !reflect.DeepEqual(got, []uint16{'a', 'b', 'c', 'd'}) {
+		t.Errorf("UTF16() = %v; want: %v", got, []uint16{'a', 'b', 'c', 'd'})
+	}
+}
+
+func TestUTF16BuilderGrow(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 1000} {
+		var b UTF16Builder
+		b.Grow(n)
+		if b.Cap() < n {
+			t.Errorf("Grow(%d): Cap() = %d", n, b.Cap())
+		}
+		allocs := testing.AllocsPerRun(10, func() {
+			b.Reset()
+			b.Grow(n)
+			for i := 0; i < n; i++ {
+				b.WriteByte('a')
+			}
+		})
+		if allocs > 1 {
+			t.Errorf("Grow(%d): %.0f allocations writing %[1]d units", n, allocs)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Grow(-1): expected panic")
+		}
+	}()
+	var b UTF16Builder
+	b.Grow(-1)
+}
+
+func BenchmarkUTF16Builder_SixtyFourUnicode(b *testing.B) {
+	var u UTF16Builder
+	for i := 0; i < b.N; i++ {
+		u.Reset()
+		u.WriteString(SixtyFourUnicodeChars)
+		u.WriteRune('x')
+		u.WriteString(SixtyFourUnicodeChars)
+		_ = u.UTF16Z()
+	}
+}
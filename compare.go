@@ -0,0 +1,113 @@
+package utfconv
+
+import "unicode/utf8"
+
+// EqualString reports whether UTF-16 slice u and string s are equal, that is
+// whether UTF16ToString(u) == s, without allocating. Unpaired surrogates in
+// u are treated as U+FFFD. Invalid UTF-8 in s never compares equal.
+func EqualString(u []uint16, s string) bool {
+	// every code unit encodes to between 1 and 3 bytes
+	if len(u) > len(s) || len(s) > 3*len(u) {
+		return false
+	}
+	return CompareString(u, s) == 0
+}
+
+// CompareString returns an integer comparing UTF-16 slice u and string s
+// lexicographically by their UTF-8 encodings, it is the equivalent of
+// strings.Compare(UTF16ToString(u), s) without allocating. For valid text
+// this is code point order. Unpaired surrogates in u are treated as U+FFFD.
+func CompareString(u []uint16, s string) int {
+	i := 0
+	n := len(u)
+	if len(s) < n {
+		n = len(s)
+	}
+	// ASCII fast path
+	for ; i < n; i++ {
+		c := u[i]
+		if c >= runeSelf || s[i] >= runeSelf {
+			goto Slow
+		}
+		if byte(c) != s[i] {
+			if byte(c) < s[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(u) == len(s):
+		return 0
+	case len(u) < len(s):
+		return -1
+	}
+	return 1
+
+Slow:
+	var buf [utf8.UTFMax]byte
+	j := i
+	for i < len(u) && j < len(s) {
+		if c := u[i]; c < runeSelf {
+			if byte(c) != s[j] {
+				if byte(c) < s[j] {
+					return -1
+				}
+				return 1
+			}
+			i++
+			j++
+			continue
+		}
+		// fast path for equal BMP runes
+		if c := u[i]; c < surr1 || surr3 <= c {
+			if c <= rune2Max {
+				if j+1 < len(s) && s[j] == t2|byte(c>>6) && s[j+1] == tx|byte(c)&maskx {
+					i++
+					j += 2
+					continue
+				}
+			} else if j+2 < len(s) && s[j] == t3|byte(c>>12) &&
+				s[j+1] == tx|byte(c>>6)&maskx && s[j+2] == tx|byte(c)&maskx {
+				i++
+				j += 3
+				continue
+			}
+		}
+		r, size := DecodeRuneUTF16(u[i:])
+		r2, size2 := utf8.DecodeRuneInString(s[j:])
+		if r2 != utf8.RuneError || size2 != 1 {
+			// UTF-8 byte order is code point order
+			if r != r2 {
+				if r < r2 {
+					return -1
+				}
+				return 1
+			}
+			i += size
+			j += size2
+			continue
+		}
+		// invalid UTF-8, compare the bytes of r
+		i += size
+		for _, b := range buf[:utf8.EncodeRune(buf[:], r)] {
+			if j == len(s) {
+				return 1
+			}
+			if b != s[j] {
+				if b < s[j] {
+					return -1
+				}
+				return 1
+			}
+			j++
+		}
+	}
+	switch {
+	case i < len(u):
+		return 1
+	case j < len(s):
+		return -1
+	}
+	return 0
+}
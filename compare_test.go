@@ -0,0 +1,82 @@
+package utfconv
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestCompareString(t *testing.T) {
+	all := append(append([]string{""}, testStrings...), invalidSequenceTests...)
+	for _, a := range all {
+		u := utf16.Encode([]rune(a))
+		for _, s := range all {
+			exp := strings.Compare(UTF16ToString(u), s)
+			if c := CompareString(u, s); c != exp {
+				t.Errorf("CompareString(%q, %q) = %d; want: %d", a, s, c, exp)
+			}
+			if eq := EqualString(u, s); eq != (exp == 0) {
+				t.Errorf("EqualString(%q, %q) = %t; want: %t", a, s, eq, exp == 0)
+			}
+		}
+	}
+}
+
+func TestCompareStringSurrogates(t *testing.T) {
+	tests := []struct {
+		u   []uint16
+		s   string
+		exp int
+	}{
+		{[]uint16{0xd800}, "\uFFFD", 0},
+		{[]uint16{'a', 0xdc00, 'b'}, "a\uFFFDb", 0},
+		{[]uint16{0xd83d, 0xde00}, "\U0001F600", 0},
+		{[]uint16{0xd83d, 0xde00}, "\uFFFF", 1},
+		{[]uint16{0xd83d}, "\U0001F600", -1},
+		{[]uint16{0xd800}, "\xff", -1},
+		{[]uint16{'a', 'b'}, "a", 1},
+		{[]uint16{'a'}, "ab", -1},
+		{[]uint16{0x65e5}, "日本", -1},
+	}
+	for _, x := range tests {
+		if c := CompareString(x.u, x.s); c != x.exp {
+			t.Errorf("CompareString(%#x, %q) = %d; want: %d", x.u, x.s, c, x.exp)
+		}
+	}
+}
+
+func TestEqualStringAllocs(t *testing.T) {
+	u := SixtyFourUnicodeCharsUTF16
+	n := testing.AllocsPerRun(100, func() {
+		if !EqualString(u, SixtyFourUnicodeChars) {
+			t.Fatal("EqualString: strings are not equal")
+		}
+	})
+	if n != 0 {
+		t.Errorf("EqualString: %v allocations; want: 0", n)
+	}
+}
+
+func BenchmarkEqualString_SixtyFourASCII(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		EqualString(SixtyFourASCIICharsUTF16, SixtyFourASCIIChars)
+	}
+}
+
+func BenchmarkEqualString_Base_SixtyFourASCII(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = UTF16ToString(SixtyFourASCIICharsUTF16) == SixtyFourASCIIChars
+	}
+}
+
+func BenchmarkEqualString_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		EqualString(SixtyFourUnicodeCharsUTF16, SixtyFourUnicodeChars)
+	}
+}
+
+func BenchmarkEqualString_Base_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = UTF16ToString(SixtyFourUnicodeCharsUTF16) == SixtyFourUnicodeChars
+	}
+}
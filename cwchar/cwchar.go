@@ -0,0 +1,128 @@
+package cwchar
+
+/*
+#include <stdlib.h>
+#include <wchar.h>
+*/
+import "C"
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// WCharSize must be the size of wchar_t of the C compiler, one of these
+// array lengths is negative otherwise.
+var (
+	_ [WCharSize - C.sizeof_wchar_t]struct{}
+	_ [C.sizeof_wchar_t - WCharSize]struct{}
+)
+
+// malloc returns a C buffer for n elements of the given size. Like
+// C.CString it panics if the allocation fails.
+func malloc(n, size int) unsafe.Pointer {
+	p := C.malloc(C.size_t(n * size))
+	if p == nil {
+		panic("cwchar: malloc failed")
+	}
+	return p
+}
+
+// Free releases a string returned by WString or Char16String. It does
+// nothing if p is nil.
+func Free(p unsafe.Pointer) {
+	C.free(p)
+}
+
+// WString returns a C wchar_t copy of s with a terminating NUL, allocated
+// with malloc. The caller must release it with Free. If s contains a NUL
+// byte, it returns utfconv.ErrNUL.
+func WString(s string) (unsafe.Pointer, error) {
+	if WCharSize == 2 {
+		return Char16String(s)
+	}
+	if strings.IndexByte(s, 0) != -1 {
+		return nil, utfconv.ErrNUL
+	}
+	// Converting to []rune encodes invalid UTF-8 as U+FFFD, like
+	// StringToUTF16, so both encodings of s have the same characters.
+	r := []rune(s)
+	p := malloc(len(r)+1, 4)
+	a := unsafe.Slice((*rune)(p), len(r)+1)
+	a[copy(a, r)] = 0
+	return p, nil
+}
+
+// Char16String returns a C char16_t copy of s with a terminating NUL,
+// allocated with malloc. The caller must release it with Free. If s
+// contains a NUL byte, it returns utfconv.ErrNUL.
+func Char16String(s string) (unsafe.Pointer, error) {
+	u, err := utfconv.StringToUTF16Z(s)
+	if err != nil {
+		return nil, err
+	}
+	p := malloc(len(u), 2)
+	copy(unsafe.Slice((*uint16)(p), len(u)), u)
+	return p, nil
+}
+
+// GoWString returns the UTF-8 encoding of the NUL-terminated wchar_t string
+// at p. It returns "" if p is nil.
+func GoWString(p unsafe.Pointer) string {
+	return GoWStringN(p, math.MaxInt)
+}
+
+// GoWStringN returns the UTF-8 encoding of the NUL-terminated wchar_t
+// string at p, reading at most maxLen characters, which makes it safe to use
+// with buffers that may not be terminated. It returns "" if p is nil or
+// maxLen is not positive.
+func GoWStringN(p unsafe.Pointer, maxLen int) string {
+	if WCharSize == 2 {
+		return GoChar16StringN(p, maxLen)
+	}
+	if p == nil || maxLen <= 0 {
+		return ""
+	}
+	n := 0
+	size := 0
+	for n < maxLen {
+		r := *(*rune)(unsafe.Add(p, 4*n))
+		if r == 0 {
+			break
+		}
+		if l := utf8.RuneLen(r); l > 0 {
+			size += l
+		} else {
+			size += 3 // U+FFFD
+		}
+		n++
+	}
+	if n == 0 {
+		return ""
+	}
+	b := make([]byte, 0, size)
+	for _, r := range unsafe.Slice((*rune)(p), n) {
+		// AppendRune encodes surrogates and out of range values as
+		// U+FFFD.
+		b = utf8.AppendRune(b, r)
+	}
+	return string(b)
+}
+
+// GoChar16String returns the UTF-8 encoding of the NUL-terminated char16_t
+// string at p. It returns "" if p is nil.
+func GoChar16String(p unsafe.Pointer) string {
+	return GoChar16StringN(p, math.MaxInt)
+}
+
+// GoChar16StringN returns the UTF-8 encoding of the NUL-terminated char16_t
+// string at p, reading at most maxLen code units, which makes it safe to use
+// with buffers that may not be terminated. It returns "" if p is nil or
+// maxLen is not positive.
+func GoChar16StringN(p unsafe.Pointer, maxLen int) string {
+	return utfconv.UTF16PtrToString((*uint16)(p), maxLen)
+}
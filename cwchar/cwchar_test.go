@@ -0,0 +1,141 @@
+//go:build cgo
+
+package cwchar
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/charlievieth/utfconv"
+	"github.com/charlievieth/utfconv/cwchar/internal/shim"
+)
+
+var stringTests = []string{
+	"",
+	"a",
+	"abc",
+	"日本語",
+	"\U0001F600",
+	shim.Literal,
+}
+
+func TestWString(t *testing.T) {
+	for _, s := range stringTests {
+		p, err := WString(s)
+		if err != nil {
+			t.Fatalf("WString(%q): %v", s, err)
+		}
+		exp := len([]rune(s))
+		if WCharSize == 2 {
+			exp = utfconv.UTF16EncodedLenString(s)
+		}
+		if n := shim.WideLen(p); n != exp {
+			t.Errorf("wcslen(WString(%q)) = %d; want: %d", s, n, exp)
+		}
+		if got := GoWString(p); got != s {
+			t.Errorf("GoWString(WString(%q)) = %q; want: %q", s, got, s)
+		}
+		Free(p)
+	}
+	p, err := WString(shim.Literal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Free(p)
+	if !shim.WideEqual(p) {
+		t.Errorf("WString(%q) is not equal to the C literal", shim.Literal)
+	}
+	if s := GoWString(shim.WideLiteral()); s != shim.Literal {
+		t.Errorf("GoWString(C literal) = %q; want: %q", s, shim.Literal)
+	}
+}
+
+func TestChar16String(t *testing.T) {
+	for _, s := range stringTests {
+		p, err := Char16String(s)
+		if err != nil {
+			t.Fatalf("Char16String(%q): %v", s, err)
+		}
+		if n, exp := shim.Char16Len(p), utfconv.UTF16EncodedLenString(s); n != exp {
+			t.Errorf("len(Char16String(%q)) = %d; want: %d", s, n, exp)
+		}
+		if got := GoChar16String(p); got != s {
+			t.Errorf("GoChar16String(Char16String(%q)) = %q; want: %q", s, got, s)
+		}
+		Free(p)
+	}
+	p, err := Char16String(shim.Literal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Free(p)
+	if !shim.Char16Equal(p) {
+		t.Errorf("Char16String(%q) is not equal to the C literal", shim.Literal)
+	}
+	if s := GoChar16String(shim.Char16Literal()); s != shim.Literal {
+		t.Errorf("GoChar16String(C literal) = %q; want: %q", s, shim.Literal)
+	}
+}
+
+func TestNUL(t *testing.T) {
+	if p, err := WString("a\x00b"); err != utfconv.ErrNUL || p != nil {
+		t.Errorf("WString: got %v, %v; want: <nil>, %v", p, err, utfconv.ErrNUL)
+	}
+	if p, err := Char16String("a\x00b"); err != utfconv.ErrNUL || p != nil {
+		t.Errorf("Char16String: got %v, %v; want: <nil>, %v", p, err, utfconv.ErrNUL)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	const s = "a\xffb"
+	p, err := WString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Free(p)
+	if got := GoWString(p); got != "a\uFFFDb" {
+		t.Errorf("GoWString(WString(%q)) = %q; want: %q", s, got, "a\uFFFDb")
+	}
+
+	if WCharSize == 4 {
+		w := []rune{'a', 0xD800, 0x110000, -1, 'b', 0}
+		exp := "a\uFFFD\uFFFD\uFFFDb"
+		if got := GoWString(unsafe.Pointer(&w[0])); got != exp {
+			t.Errorf("GoWString(%v) = %q; want: %q", w, got, exp)
+		}
+	}
+	u := []uint16{'a', 0xD800, 'b', 0}
+	if got := GoChar16String(unsafe.Pointer(&u[0])); got != "a\uFFFDb" {
+		t.Errorf("GoChar16String(%v) = %q; want: %q", u, got, "a\uFFFDb")
+	}
+}
+
+func TestBounded(t *testing.T) {
+	tests := []struct {
+		maxLen int
+		exp    string
+	}{
+		{-1, ""},
+		{0, ""},
+		{2, "ab"},
+		{3, "abc"},
+	}
+	for _, x := range tests {
+		if s := GoWStringN(shim.WideUnterminated(), x.maxLen); s != x.exp {
+			t.Errorf("GoWStringN(%d) = %q; want: %q", x.maxLen, s, x.exp)
+		}
+		if s := GoChar16StringN(shim.Char16Unterminated(), x.maxLen); s != x.exp {
+			t.Errorf("GoChar16StringN(%d) = %q; want: %q", x.maxLen, s, x.exp)
+		}
+	}
+	if s := GoWStringN(shim.WideLiteral(), 5); s != "abc 日" {
+		t.Errorf("GoWStringN(C literal, 5) = %q; want: %q", s, "abc 日")
+	}
+	if s := GoWString(nil); s != "" {
+		t.Errorf("GoWString(nil) = %q; want: %q", s, "")
+	}
+	if s := GoChar16String(nil); s != "" {
+		t.Errorf("GoChar16String(nil) = %q; want: %q", s, "")
+	}
+	Free(nil)
+}
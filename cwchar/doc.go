@@ -0,0 +1,17 @@
+// Package cwchar converts between Go strings and the wide character strings
+// used by C libraries: wchar_t strings, which are UTF-32 on Linux and other
+// Unix systems and UTF-16 on Windows, and char16_t strings, which are UTF-16
+// and are used by ICU and by SQLWCHAR in ODBC.
+//
+// Strings returned by WString and Char16String are NUL-terminated and
+// allocated in the C heap with malloc, as with C.CString, and must be
+// released with Free. Pointers are passed as unsafe.Pointer, since C types
+// cannot be shared between packages; convert them to and from the C types of
+// the calling package, for example (*C.wchar_t)(p).
+//
+// As with the rest of utfconv, invalid UTF-8 is encoded as one U+FFFD per
+// byte, and unpaired surrogates and invalid UTF-32 code points are decoded
+// as U+FFFD.
+//
+// This package requires cgo.
+package cwchar
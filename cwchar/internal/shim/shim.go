@@ -0,0 +1,76 @@
+//go:build cgo
+
+// Package shim provides C strings and functions used to test package
+// cwchar against the C compiler's own wide character encodings.
+package shim
+
+/*
+#include <stdlib.h>
+#include <string.h>
+#include <uchar.h>
+#include <wchar.h>
+
+// The literals are written with universal character names so that the
+// result does not depend on the source character set of the compiler.
+static const wchar_t wide[] = L"abc \u65E5\u672C\u8A9E \U0001F600";
+static const char16_t c16[] = u"abc \u65E5\u672C\u8A9E \U0001F600";
+
+// Unterminated buffers, followed by data that must not be read.
+static const struct { wchar_t s[3]; wchar_t x[2]; } wideUnterm = {{'a', 'b', 'c'}, {'x', 0}};
+static const struct { char16_t s[3]; char16_t x[2]; } c16Unterm = {{'a', 'b', 'c'}, {'x', 0}};
+
+static const void *wideLiteral(void) { return wide; }
+static const void *char16Literal(void) { return c16; }
+static const void *wideUnterminated(void) { return wideUnterm.s; }
+static const void *char16Unterminated(void) { return c16Unterm.s; }
+
+static size_t char16Len(const void *p) {
+	const char16_t *s = p;
+	size_t n = 0;
+	while (s[n] != 0) {
+		n++;
+	}
+	return n;
+}
+
+static int wideEqual(const void *p) {
+	return wcslen(p) == wcslen(wide) && memcmp(p, wide, sizeof(wide)) == 0;
+}
+
+static int char16Equal(const void *p) {
+	return char16Len(p) == char16Len(c16) && memcmp(p, c16, sizeof(c16)) == 0;
+}
+*/
+import "C"
+
+import "unsafe"
+
+// Literal is the Go string of the C literals returned by WideLiteral and
+// Char16Literal.
+const Literal = "abc 日本語 \U0001F600"
+
+// WideLiteral returns a wchar_t string literal compiled by the C compiler.
+func WideLiteral() unsafe.Pointer { return unsafe.Pointer(C.wideLiteral()) }
+
+// Char16Literal returns a char16_t string literal compiled by the C
+// compiler.
+func Char16Literal() unsafe.Pointer { return unsafe.Pointer(C.char16Literal()) }
+
+// WideUnterminated returns a wchar_t buffer holding "abc" without a NUL.
+func WideUnterminated() unsafe.Pointer { return unsafe.Pointer(C.wideUnterminated()) }
+
+// Char16Unterminated returns a char16_t buffer holding "abc" without a NUL.
+func Char16Unterminated() unsafe.Pointer { return unsafe.Pointer(C.char16Unterminated()) }
+
+// WideLen returns the wcslen of the wchar_t string at p.
+func WideLen(p unsafe.Pointer) int { return int(C.wcslen((*C.wchar_t)(p))) }
+
+// Char16Len returns the length of the char16_t string at p.
+func Char16Len(p unsafe.Pointer) int { return int(C.char16Len(p)) }
+
+// WideEqual reports whether the wchar_t string at p equals WideLiteral.
+func WideEqual(p unsafe.Pointer) bool { return C.wideEqual(p) != 0 }
+
+// Char16Equal reports whether the char16_t string at p equals
+// Char16Literal.
+func Char16Equal(p unsafe.Pointer) bool { return C.char16Equal(p) != 0 }
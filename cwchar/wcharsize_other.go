@@ -0,0 +1,7 @@
+//go:build !windows
+
+package cwchar
+
+// WCharSize is the size in bytes of the C wchar_t type: 4 on Linux and
+// other Unix systems and 2 on Windows.
+const WCharSize int = 4
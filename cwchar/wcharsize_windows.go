@@ -0,0 +1,5 @@
+package cwchar
+
+// WCharSize is the size in bytes of the C wchar_t type: 4 on Linux and
+// other Unix systems and 2 on Windows.
+const WCharSize int = 2
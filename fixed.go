@@ -0,0 +1,61 @@
+package utfconv
+
+import "unsafe"
+
+// The functions in this file read and write strings in fixed size UTF-16
+// fields of binary structures, such as the [260]uint16 cFileName field of
+// WIN32_FIND_DATAW. Pass the field as a slice, for example fd.FileName[:].
+// The element type may be any type with underlying type uint16, such as a
+// WCHAR type declared by a binding.
+
+// asUTF16 returns a as a []uint16 sharing its underlying array.
+func asUTF16[S ~[]E, E ~uint16](a S) []uint16 {
+	return unsafe.Slice((*uint16)(unsafe.Pointer(unsafe.SliceData(a))), len(a))
+}
+
+// FixedToString returns the UTF-8 encoding of the NUL-terminated string in
+// the fixed size field a. If a contains no NUL, all of a is converted.
+func FixedToString[S ~[]E, E ~uint16](a S) string {
+	return UTF16ZToString(asUTF16(a))
+}
+
+// PaddedToString returns the UTF-8 encoding of the fixed size field a with
+// the trailing code units equal to pad removed. Use a pad of ' ' for space
+// padded fields or 0 for fields that are NUL padded but may contain NULs.
+func PaddedToString[S ~[]E, E ~uint16](a S, pad E) string {
+	n := len(a)
+	for n > 0 && a[n-1] == pad {
+		n--
+	}
+	return UTF16ToString(asUTF16(a[:n]))
+}
+
+// StringToFixed writes the UTF-16 encoding of s to the fixed size field a
+// followed by a terminating NUL and fills the rest of a with NULs. If s does
+// not fit it is truncated, without splitting a surrogate pair, so that the
+// NUL fits. It returns the number of code units of s written, not including
+// the NUL, and whether s was truncated. If a is empty nothing is written.
+func StringToFixed[S ~[]E, E ~uint16](a S, s string) (n int, truncated bool) {
+	if len(a) == 0 {
+		return 0, s != ""
+	}
+	return writeFixed(asUTF16(a), s, len(a)-1, 0)
+}
+
+// StringToPadded writes the UTF-16 encoding of s to the fixed size field a
+// and fills the rest of a with pad. If s does not fit it is truncated,
+// without splitting a surrogate pair. It returns the number of code units
+// of s written and whether s was truncated.
+func StringToPadded[S ~[]E, E ~uint16](a S, s string, pad E) (n int, truncated bool) {
+	return writeFixed(asUTF16(a), s, len(a), uint16(pad))
+}
+
+func writeFixed(a []uint16, s string, max int, pad uint16) (int, bool) {
+	u := StringToUTF16(s)
+	t := TruncateUTF16Slice(u, max)
+	n := copy(a, t)
+	for i := n; i < len(a); i++ {
+		a[i] = pad
+	}
+	return n, len(t) < len(u)
+}
@@ -0,0 +1,137 @@
+package utfconv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+type wchar uint16
+
+func TestFixedToString(t *testing.T) {
+	tests := []struct {
+		in  []uint16
+		exp string
+	}{
+		{nil, ""},
+		{[]uint16{0, 'a'}, ""},
+		{[]uint16{'a', 'b', 0, 'c', 0}, "ab"},
+		{[]uint16{'a', 'b', 'c'}, "abc"},
+		{[]uint16{0xD83D, 0xDE00, 0, 0}, "\U0001F600"},
+		{[]uint16{'a', 0xD83D}, "a\uFFFD"},
+	}
+	for _, x := range tests {
+		if s := FixedToString(x.in); s != x.exp {
+			t.Errorf("FixedToString(%v) = %q; want: %q", x.in, s, x.exp)
+		}
+	}
+	w := [4]wchar{'w', 'c', 0, 'x'}
+	if s := FixedToString(w[:]); s != "wc" {
+		t.Errorf("FixedToString(%v) = %q; want: %q", w, s, "wc")
+	}
+}
+
+func TestPaddedToString(t *testing.T) {
+	tests := []struct {
+		in  []uint16
+		pad uint16
+		exp string
+	}{
+		{nil, ' ', ""},
+		{[]uint16{' ', ' '}, ' ', ""},
+		{[]uint16{'a', ' ', 'b', ' ', ' '}, ' ', "a b"},
+		{[]uint16{'a', 0, 'b', 0, 0}, 0, "a\x00b"},
+		{[]uint16{'a', 'b'}, ' ', "ab"},
+	}
+	for _, x := range tests {
+		if s := PaddedToString(x.in, x.pad); s != x.exp {
+			t.Errorf("PaddedToString(%v, %#x) = %q; want: %q", x.in, x.pad, s, x.exp)
+		}
+	}
+}
+
+func TestStringToFixed(t *testing.T) {
+	tests := []struct {
+		s         string
+		size      int
+		exp       []uint16
+		n         int
+		truncated bool
+	}{
+		{"", 0, []uint16{}, 0, false},
+		{"a", 0, []uint16{}, 0, true},
+		{"", 3, []uint16{0, 0, 0}, 0, false},
+		{"ab", 3, []uint16{'a', 'b', 0}, 2, false},
+		{"abc", 3, []uint16{'a', 'b', 0}, 2, true},
+		{"a", 4, []uint16{'a', 0, 0, 0}, 1, false},
+		{"a\U0001F600", 3, []uint16{'a', 0, 0}, 1, true},
+		{"a\U0001F600", 4, []uint16{'a', 0xD83D, 0xDE00, 0}, 3, false},
+	}
+	for _, x := range tests {
+		a := make([]uint16, x.size)
+		for i := range a {
+			a[i] = 0xFFFF
+		}
+		n, truncated := StringToFixed(a, x.s)
+		if n != x.n || truncated != x.truncated || !reflect.DeepEqual(a, x.exp) {
+			t.Errorf("StringToFixed([%d], %q) = %d, %t, %v; want: %d, %t, %v",
+				x.size, x.s, n, truncated, a, x.n, x.truncated, x.exp)
+		}
+	}
+}
+
+func TestStringToPadded(t *testing.T) {
+	tests := []struct {
+		s         string
+		size      int
+		exp       []uint16
+		n         int
+		truncated bool
+	}{
+		{"", 2, []uint16{' ', ' '}, 0, false},
+		{"ab", 2, []uint16{'a', 'b'}, 2, false},
+		{"abc", 2, []uint16{'a', 'b'}, 2, true},
+		{"a", 3, []uint16{'a', ' ', ' '}, 1, false},
+		{"a\U0001F600", 2, []uint16{'a', ' '}, 1, true},
+	}
+	for _, x := range tests {
+		a := make([]uint16, x.size)
+		n, truncated := StringToPadded(a, x.s, ' ')
+		if n != x.n || truncated != x.truncated || !reflect.DeepEqual(a, x.exp) {
+			t.Errorf("StringToPadded([%d], %q) = %d, %t, %v; want: %d, %t, %v",
+				x.size, x.s, n, truncated, a, x.n, x.truncated, x.exp)
+		}
+	}
+}
+
+// Test reading and writing the fields of a structure decoded with
+// encoding/binary.
+func TestFixedBinary(t *testing.T) {
+	type record struct {
+		Size uint32
+		Name [8]wchar
+		Tag  [4]uint16
+	}
+	var r record
+	r.Size = 1
+	if _, truncated := StringToFixed(r.Name[:], "日本語.text"); !truncated {
+		t.Error("StringToFixed: expected truncation")
+	}
+	StringToPadded(r.Tag[:], "ab", ' ')
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, &r); err != nil {
+		t.Fatal(err)
+	}
+	var r2 record
+	if err := binary.Read(&buf, binary.LittleEndian, &r2); err != nil {
+		t.Fatal(err)
+	}
+	if s := FixedToString(r2.Name[:]); s != "日本語.tex" {
+		t.Errorf("FixedToString(Name) = %q; want: %q", s, "日本語.tex")
+	}
+	if s := PaddedToString(r2.Tag[:], ' '); s != "ab" {
+		t.Errorf("PaddedToString(Tag) = %q; want: %q", s, "ab")
+	}
+}
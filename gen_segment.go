@@ -0,0 +1,147 @@
+//go:build ignore
+
+// This program generates the property tables used by the grapheme cluster,
+// word and sentence iterators from the UCD files in internal/ucd. Run it
+// with go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charlievieth/utfconv/internal/ucd"
+)
+
+var ucdDir = flag.String("ucd", "internal/ucd", "directory containing the UCD files")
+
+type table struct {
+	name   string   // variable name
+	output string   // output file
+	prefix string   // prefix of the property constants
+	files  []string // UCD files
+	doc    string
+}
+
+var tables = []table{
+	{
+		name:   "graphemeRanges",
+		output: "grapheme_tables.go",
+		prefix: "gb",
+		files:  []string{"GraphemeBreakProperty.txt", "emoji-data.txt"},
+		doc: "the Grapheme_Cluster_Break property values of\n" +
+			"Unicode 15.0.0 and the Extended_Pictographic code points sorted by\n" +
+			"code point. Hangul syllables (LV and LVT) are omitted.",
+	},
+	{
+		name:   "wordRanges",
+		output: "word_tables.go",
+		prefix: "wb",
+		files:  []string{"WordBreakProperty.txt"},
+		doc:    "the Word_Break property values of Unicode 15.0.0\nsorted by code point.",
+	},
+	{
+		name:   "sentenceRanges",
+		output: "sentence_tables.go",
+		prefix: "sb",
+		files:  []string{"SentenceBreakProperty.txt"},
+		doc:    "the Sentence_Break property values of Unicode\n15.0.0 sorted by code point.",
+	},
+}
+
+type rng struct {
+	lo, hi rune
+	prop   string
+}
+
+const (
+	sBase  = 0xAC00
+	sCount = 11172
+	tCount = 28
+)
+
+func parse(name string, ranges []rng) []rng {
+	err := ucd.Parse(filepath.Join(*ucdDir, name), func(f []string) error {
+		if len(f) < 2 {
+			return fmt.Errorf("expected 2 fields: %q", f)
+		}
+		if name == "emoji-data.txt" && f[1] != "Extended_Pictographic" {
+			return nil
+		}
+		lo, hi, err := ucd.Range(f[0])
+		if err != nil {
+			return err
+		}
+		if name == "GraphemeBreakProperty.txt" && (f[1] == "LV" || f[1] == "LVT") {
+			// Hangul syllables are computed by graphemeProp
+			for r := lo; r <= hi; r++ {
+				lv := r >= sBase && r < sBase+sCount && (r-sBase)%tCount == 0
+				if r < sBase || r >= sBase+sCount || lv != (f[1] == "LV") {
+					return fmt.Errorf("unexpected Hangul syllable: %U %s", r, f[1])
+				}
+			}
+			return nil
+		}
+		ranges = append(ranges, rng{lo, hi, f[1]})
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return ranges
+}
+
+func generate(t table) {
+	var ranges []rng
+	for _, name := range t.files {
+		ranges = parse(name, ranges)
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+
+	var merged []rng
+	for _, r := range ranges {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if r.lo <= last.hi {
+				log.Fatalf("%s: overlapping ranges: %U..%U %s and %U..%U %s", t.name,
+					last.lo, last.hi, last.prop, r.lo, r.hi, r.prop)
+			}
+			if r.lo == last.hi+1 && r.prop == last.prop {
+				last.hi = r.hi
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_segment.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package utfconv\n\n")
+	fmt.Fprintf(&buf, "// %s are %s\n", t.name, strings.ReplaceAll(t.doc, "\n", "\n// "))
+	fmt.Fprintf(&buf, "var %s = [...]propRange{\n", t.name)
+	for _, r := range merged {
+		fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, %s%s},\n", r.lo, r.hi,
+			t.prefix, strings.ReplaceAll(r.prop, "_", ""))
+	}
+	buf.WriteString("}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(t.output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func main() {
+	flag.Parse()
+	for _, t := range tables {
+		generate(t)
+	}
+}
@@ -0,0 +1,93 @@
+//go:build ignore
+
+// This program generates the table of wide code points used by Width from
+// the UCD files in internal/ucd. Run it with go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/charlievieth/utfconv/internal/ucd"
+)
+
+var ucdDir = flag.String("ucd", "internal/ucd", "directory containing the UCD files")
+
+// defaultWide are the ranges whose unassigned code points have an
+// East_Asian_Width of W, see UAX #11.
+var defaultWide = [][2]rune{
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xF900, 0xFAFF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+func main() {
+	flag.Parse()
+	wide := make(map[rune]bool)
+	for _, r := range defaultWide {
+		for c := r[0]; c <= r[1]; c++ {
+			wide[c] = true
+		}
+	}
+	parse := func(name string, f func(lo, hi rune, prop string)) {
+		err := ucd.Parse(filepath.Join(*ucdDir, name), func(fields []string) error {
+			if len(fields) < 2 {
+				return fmt.Errorf("expected 2 fields: %q", fields)
+			}
+			lo, hi, err := ucd.Range(fields[0])
+			if err != nil {
+				return err
+			}
+			f(lo, hi, fields[1])
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	parse("EastAsianWidth.txt", func(lo, hi rune, prop string) {
+		for r := lo; r <= hi; r++ {
+			wide[r] = prop == "W" || prop == "F"
+		}
+	})
+	parse("emoji-data.txt", func(lo, hi rune, prop string) {
+		if prop == "Emoji_Presentation" {
+			for r := lo; r <= hi; r++ {
+				wide[r] = true
+			}
+		}
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_width.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package utfconv\n\n")
+	buf.WriteString("// wideRanges are the code points of Unicode 15.0.0 with an East_Asian_Width\n")
+	buf.WriteString("// of Wide or Fullwidth or with Emoji_Presentation, sorted by code point.\n")
+	buf.WriteString("// The property of each range is its width.\n")
+	buf.WriteString("var wideRanges = [...]propRange{\n")
+	for r := rune(0); r <= 0x10FFFF; r++ {
+		if !wide[r] {
+			continue
+		}
+		lo := r
+		for wide[r+1] {
+			r++
+		}
+		fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, 2},\n", lo, r)
+	}
+	buf.WriteString("}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("width_tables.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
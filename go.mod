@@ -0,0 +1,3 @@
+module github.com/charlievieth/utfconv
+
+go 1.20
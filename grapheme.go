@@ -0,0 +1,151 @@
+package utfconv
+
+// Grapheme_Cluster_Break property values and Extended_Pictographic.
+const (
+	gbOther breakProperty = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+	gbExtendedPictographic
+)
+
+// graphemeProp returns the grapheme cluster break property of r.
+func graphemeProp(r rune) breakProperty {
+	if r < runeSelf {
+		switch {
+		case r == '\r':
+			return gbCR
+		case r == '\n':
+			return gbLF
+		case r < ' ' || r == 0x7F:
+			return gbControl
+		}
+		return gbOther
+	}
+	const (
+		sBase  = 0xAC00
+		sCount = 11172
+		tCount = 28
+	)
+	if sBase <= r && r < sBase+sCount {
+		if (r-sBase)%tCount == 0 {
+			return gbLV
+		}
+		return gbLVT
+	}
+	return lookupProp(graphemeRanges[:], r)
+}
+
+// graphemeState holds the context needed to apply the grapheme cluster
+// boundary rules of UAX #29 to the next code point.
+type graphemeState struct {
+	prev breakProperty
+	pict bool // text ends with ExtPict Extend*
+	zwj  bool // text ends with ExtPict Extend* ZWJ
+	ri   bool // text ends with an odd number of regional indicators
+}
+
+// step reports whether there is a grapheme cluster boundary between the
+// text seen so far and a code point with property p, and adds it to the
+// state. The result of the first call is meaningless.
+func (st *graphemeState) step(p breakProperty) bool {
+	prev, zwj, ri := st.prev, st.zwj, st.ri
+	st.prev = p
+	st.zwj = p == gbZWJ && st.pict
+	st.pict = p == gbExtendedPictographic || (p == gbExtend && st.pict)
+	st.ri = p == gbRegionalIndicator && !ri
+
+	switch {
+	case prev == gbCR && p == gbLF: // GB3
+		return false
+	case prev == gbControl || prev == gbCR || prev == gbLF: // GB4
+		return true
+	case p == gbControl || p == gbCR || p == gbLF: // GB5
+		return true
+	case prev == gbL && (p == gbL || p == gbV || p == gbLV || p == gbLVT): // GB6
+		return false
+	case (prev == gbLV || prev == gbV) && (p == gbV || p == gbT): // GB7
+		return false
+	case (prev == gbLVT || prev == gbT) && p == gbT: // GB8
+		return false
+	case p == gbExtend || p == gbZWJ: // GB9
+		return false
+	case p == gbSpacingMark: // GB9a
+		return false
+	case prev == gbPrepend: // GB9b
+		return false
+	case zwj && p == gbExtendedPictographic: // GB11
+		return false
+	case ri && p == gbRegionalIndicator: // GB12, GB13
+		return false
+	}
+	return true // GB999
+}
+
+// A GraphemeIterator iterates over the extended grapheme clusters of a string
+// or a UTF-16 slice, as defined by Unicode Standard Annex #29. Invalid UTF-8
+// and unpaired surrogates are treated as U+FFFD, which is counted as one
+// UTF-16 code unit and three bytes, as with StringToUTF16 and UTF16ToString.
+//
+//	it := NewGraphemeIterator(s)
+//	for it.Next() {
+//		g := it.Grapheme()
+//		fmt.Println(s[g.Start:g.End], g.StartUTF16, g.EndUTF16)
+//	}
+type GraphemeIterator struct {
+	segmenter
+	st graphemeState
+}
+
+// NewGraphemeIterator returns a GraphemeIterator over the string s.
+func NewGraphemeIterator(s string) *GraphemeIterator {
+	it := new(GraphemeIterator)
+	it.init(s, nil, false, it.breakBefore)
+	return it
+}
+
+// NewGraphemeIteratorUTF16 returns a GraphemeIterator over the UTF-16
+// slice s.
+func NewGraphemeIteratorUTF16(s []uint16) *GraphemeIterator {
+	it := new(GraphemeIterator)
+	it.init("", s, true, it.breakBefore)
+	return it
+}
+
+func (it *GraphemeIterator) breakBefore(r rune, _ int) bool {
+	return it.st.step(graphemeProp(r))
+}
+
+// Next advances the iterator to the next grapheme cluster and reports
+// whether there is one.
+func (it *GraphemeIterator) Next() bool { return it.next() }
+
+// Grapheme returns the position of the current grapheme cluster.
+func (it *GraphemeIterator) Grapheme() Segment { return it.seg }
+
+// GraphemeCount returns the number of extended grapheme clusters in s.
+func GraphemeCount(s string) int {
+	n := 0
+	for it := NewGraphemeIterator(s); it.Next(); n++ {
+	}
+	return n
+}
+
+// GraphemeCountUTF16 returns the number of extended grapheme clusters in
+// the UTF-16 slice s.
+func GraphemeCountUTF16(s []uint16) int {
+	n := 0
+	for it := NewGraphemeIteratorUTF16(s); it.Next(); n++ {
+	}
+	return n
+}
@@ -0,0 +1,91 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func graphemes(it *GraphemeIterator) []Segment {
+	var a []Segment
+	for it.Next() {
+		a = append(a, it.Grapheme())
+	}
+	return a
+}
+
+func TestGraphemeBreakTest(t *testing.T) {
+	testBreakFile(t, "internal/ucd/GraphemeBreakTest.txt",
+		func(s string) []Segment { return graphemes(NewGraphemeIterator(s)) },
+		func(u []uint16) []Segment { return graphemes(NewGraphemeIteratorUTF16(u)) })
+}
+
+func TestGraphemeIteratorInvalid(t *testing.T) {
+	exp := []Segment{{0, 1, 0, 1}, {1, 2, 1, 2}, {2, 3, 2, 3}}
+	if got := graphemes(NewGraphemeIterator("a\xffb")); !reflect.DeepEqual(got, exp) {
+		t.Errorf("NewGraphemeIterator(%q) = %v; want: %v", "a\xffb", got, exp)
+	}
+	u := []uint16{'a', 0xd800, 'b', 0xdc00, 0x0301}
+	exp = []Segment{{0, 1, 0, 1}, {1, 4, 1, 2}, {4, 5, 2, 3}, {5, 10, 3, 5}}
+	if got := graphemes(NewGraphemeIteratorUTF16(u)); !reflect.DeepEqual(got, exp) {
+		t.Errorf("NewGraphemeIteratorUTF16(%#x) = %v; want: %v", u, got, exp)
+	}
+	for _, s := range append(testStrings, invalidSequenceTests...) {
+		a := graphemes(NewGraphemeIterator(s))
+		b := graphemes(NewGraphemeIteratorUTF16(StringToUTF16(s)))
+		// byte offsets differ for invalid UTF-8, compare UTF-16 offsets
+		for i := range a {
+			a[i].Start, a[i].End = 0, 0
+		}
+		for i := range b {
+			b[i].Start, b[i].End = 0, 0
+		}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("%q: string and UTF-16 graphemes differ: %v, %v", s, a, b)
+		}
+	}
+}
+
+func TestGraphemeCount(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"a\r\nb", 3},
+		{"e\u0301", 1},
+		{"\U0001F1FA\U0001F1F8\U0001F1EB\U0001F1F7", 2},
+		{"\U0001F1FA\U0001F1F8\U0001F1EB", 2},
+		{"\U0001F468\u200D\U0001F469\u200D\U0001F467", 1},
+		{"\U0001F44D\U0001F3FD", 1},
+		{"한", 1},
+		{"한글", 2},
+		{"\u0915\u094D\u0937", 2}, // conjuncts are joined from Unicode 15.1 (GB9c)
+	}
+	for _, x := range tests {
+		if n := GraphemeCount(x.s); n != x.exp {
+			t.Errorf("GraphemeCount(%q) = %d; want: %d", x.s, n, x.exp)
+		}
+		if n := GraphemeCountUTF16(StringToUTF16(x.s)); n != x.exp {
+			t.Errorf("GraphemeCountUTF16(%q) = %d; want: %d", x.s, n, x.exp)
+		}
+	}
+}
+
+func BenchmarkGraphemeCount_SixtyFourASCII(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		GraphemeCount(SixtyFourASCIIChars)
+	}
+}
+
+func BenchmarkGraphemeCount_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		GraphemeCount(SixtyFourUnicodeChars)
+	}
+}
+
+func BenchmarkGraphemeCountUTF16_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		GraphemeCountUTF16(SixtyFourUnicodeCharsUTF16)
+	}
+}
@@ -0,0 +1,69 @@
+package utfconv
+
+import (
+	"encoding/binary"
+	"hash"
+	"unicode/utf8"
+)
+
+// JavaHashCode returns the value of Java's String.hashCode for s, which is
+// computed over the UTF-16 code units of s. Invalid UTF-8 is treated as
+// U+FFFD, as with StringToUTF16.
+func JavaHashCode(s string) int32 {
+	var h int32
+	var units [2]uint16
+	for i := 0; i < len(s); {
+		if c := s[i]; c < runeSelf {
+			h = 31*h + int32(c)
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		for _, u := range AppendRuneUTF16(units[:0], r) {
+			h = 31*h + int32(u)
+		}
+		i += size
+	}
+	return h
+}
+
+// JavaHashCodeUTF16 returns the value of Java's String.hashCode for the
+// UTF-16 code units of s.
+func JavaHashCodeUTF16(s []uint16) int32 {
+	var h int32
+	for _, c := range s {
+		h = 31*h + int32(c)
+	}
+	return h
+}
+
+// HashUTF16 writes the UTF-16 encoding of s, in the given byte order, to h
+// without allocating the []uint16 returned by StringToUTF16. Use
+// binary.LittleEndian to match the bytes of .NET's Encoding.Unicode and
+// binary.BigEndian to match Java's StandardCharsets.UTF_16BE.
+func HashUTF16(s string, h hash.Hash, order binary.ByteOrder) {
+	var buf [512]byte
+	var units [2]uint16
+	n := 0
+	for i := 0; i < len(s); {
+		if n > len(buf)-4 {
+			h.Write(buf[:n])
+			n = 0
+		}
+		if c := s[i]; c < runeSelf {
+			order.PutUint16(buf[n:], uint16(c))
+			n += 2
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		for _, u := range AppendRuneUTF16(units[:0], r) {
+			order.PutUint16(buf[n:], u)
+			n += 2
+		}
+		i += size
+	}
+	if n > 0 {
+		h.Write(buf[:n])
+	}
+}
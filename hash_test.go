@@ -0,0 +1,55 @@
+package utfconv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestJavaHashCode(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp int32
+	}{
+		{"", 0},
+		{"a", 97},
+		{"hello", 99162322},
+		{"Aa", 2112},
+		{"BB", 2112},
+		{"polygenelubricants", -2147483648},
+		{"日本語", 25921943},
+		{"\U0001F600", 1772899},
+	}
+	for _, x := range tests {
+		if h := JavaHashCode(x.s); h != x.exp {
+			t.Errorf("JavaHashCode(%q) = %d; want: %d", x.s, h, x.exp)
+		}
+	}
+	for _, s := range append(testStrings, invalidSequenceTests...) {
+		exp := JavaHashCodeUTF16(StringToUTF16(s))
+		if h := JavaHashCode(s); h != exp {
+			t.Errorf("JavaHashCode(%q) = %d; want: %d", s, h, exp)
+		}
+	}
+}
+
+func TestHashUTF16(t *testing.T) {
+	long := strings.Repeat("a\U0001F600日", 200)
+	for _, s := range append(testStrings, long, "") {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			u := StringToUTF16(s)
+			b := make([]byte, 2*len(u))
+			for i, c := range u {
+				order.PutUint16(b[2*i:], c)
+			}
+			exp := sha256.Sum256(b)
+			h := sha256.New()
+			HashUTF16(s, h, order)
+			if sum := h.Sum(nil); !bytes.Equal(sum, exp[:]) {
+				t.Errorf("HashUTF16(%q, %s) = %x; want: %x", s, order, sum, exp)
+			}
+		}
+	}
+}
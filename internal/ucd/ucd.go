@@ -0,0 +1,149 @@
+// Package ucd parses the Unicode Character Database files committed in this
+// directory. It is used by the table generators of the utfconv packages and
+// by tests that check them against the Unicode conformance data.
+package ucd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the Unicode Character Database of the files in
+// this directory.
+const Version = "15.0.0"
+
+// Parse calls f with the fields of each line of the semicolon separated data
+// file at path. Comments and blank lines are skipped and each field is
+// trimmed of surrounding space.
+func Parse(path string, f func(fields []string) error) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	s := bufio.NewScanner(fh)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if err := f(fields); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+	}
+	return s.Err()
+}
+
+// Rune parses a hexadecimal code point such as "00DF".
+func Rune(s string) (rune, error) {
+	r, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || r > 0x10FFFF {
+		return 0, fmt.Errorf("ucd: invalid code point: %q", s)
+	}
+	return rune(r), nil
+}
+
+// Runes parses a space separated list of hexadecimal code points such as
+// "0053 0073".
+func Runes(s string) ([]rune, error) {
+	var a []rune
+	for _, f := range strings.Fields(s) {
+		r, err := Rune(f)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, r)
+	}
+	return a, nil
+}
+
+// Range parses a code point or a range of code points such as "0041..005A".
+func Range(s string) (lo, hi rune, err error) {
+	first, last, ok := strings.Cut(s, "..")
+	if lo, err = Rune(first); err != nil {
+		return 0, 0, err
+	}
+	if !ok {
+		return lo, lo, nil
+	}
+	if hi, err = Rune(last); err != nil {
+		return 0, 0, err
+	}
+	if hi < lo {
+		return 0, 0, fmt.Errorf("ucd: invalid range: %q", s)
+	}
+	return lo, hi, nil
+}
+
+// A BreakTest is a test case of the boundary tests of Unicode Standard Annex
+// #29, such as GraphemeBreakTest.txt. Segments is the text of the case split
+// at each break opportunity.
+type BreakTest struct {
+	Segments [][]rune
+	Comment  string
+}
+
+// String returns the text of the test case.
+func (t *BreakTest) String() string {
+	var s []rune
+	for _, seg := range t.Segments {
+		s = append(s, seg...)
+	}
+	return string(s)
+}
+
+// ParseBreakTest parses the test cases of the boundary test file at path.
+// Each line is a sequence of hexadecimal code points separated by '÷' where
+// there is a break opportunity and '×' where there is not.
+func ParseBreakTest(path string) ([]BreakTest, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	var tests []BreakTest
+	s := bufio.NewScanner(fh)
+	for n := 1; s.Scan(); n++ {
+		line, comment, _ := strings.Cut(s.Text(), "#")
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+		if len(f) < 3 || len(f)%2 == 0 || f[0] != "÷" || f[len(f)-1] != "÷" {
+			return nil, fmt.Errorf("%s:%d: invalid test case: %q", path, n, line)
+		}
+		var t BreakTest
+		var seg []rune
+		for i := 1; i < len(f); i += 2 {
+			r, err := Rune(f[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+			}
+			seg = append(seg, r)
+			switch f[i+1] {
+			case "÷":
+				t.Segments = append(t.Segments, seg)
+				seg = nil
+			case "×":
+			default:
+				return nil, fmt.Errorf("%s:%d: invalid test case: %q", path, n, line)
+			}
+		}
+		t.Comment = strings.TrimSpace(comment)
+		tests = append(tests, t)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
@@ -0,0 +1,112 @@
+package ucd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		s      string
+		lo, hi rune
+		err    bool
+	}{
+		{"0041", 0x41, 0x41, false},
+		{"0041..005A", 0x41, 0x5a, false},
+		{"10FFFF", 0x10ffff, 0x10ffff, false},
+		{"110000", 0, 0, true},
+		{"005A..0041", 0, 0, true},
+		{"XYZ", 0, 0, true},
+	}
+	for _, x := range tests {
+		lo, hi, err := Range(x.s)
+		if lo != x.lo || hi != x.hi || (err != nil) != x.err {
+			t.Errorf("Range(%q) = %#x, %#x, %v; want: %#x, %#x, %t", x.s, lo, hi, err, x.lo, x.hi, x.err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	var lines [][]string
+	err := Parse("SpecialCasing.txt", func(fields []string) error {
+		lines = append(lines, fields)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) == 0 {
+		t.Fatal("Parse: no lines")
+	}
+	exp := []string{"00DF", "00DF", "0053 0073", "0053 0053", ""}
+	if !reflect.DeepEqual(lines[0], exp) {
+		t.Errorf("Parse: first line = %q; want: %q", lines[0], exp)
+	}
+	r, err := Runes(lines[0][2])
+	if err != nil || !reflect.DeepEqual(r, []rune{'S', 's'}) {
+		t.Errorf("Runes(%q) = %q, %v; want: %q", lines[0][2], r, err, "Ss")
+	}
+}
+
+func TestParseBreakTest(t *testing.T) {
+	tests, err := ParseBreakTest("GraphemeBreakTest.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) != 602 {
+		t.Errorf("ParseBreakTest: %d tests; want: %d", len(tests), 602)
+	}
+	x := tests[1]
+	exp := [][]rune{{0x20, 0x308}, {0x20}}
+	if !reflect.DeepEqual(x.Segments, exp) {
+		t.Errorf("ParseBreakTest: Segments = %x; want: %x", x.Segments, exp)
+	}
+	if s := x.String(); s != " \u0308 " {
+		t.Errorf("BreakTest.String() = %q; want: %q", s, " \u0308 ")
+	}
+}
+
+// versionRE matches the version numbers of a file header, such as the
+// "15.0.0" of a file name or URL and the "version 15.0" of a description.
+var versionRE = regexp.MustCompile(`\b\d+\.\d+\.\d+\b|[Vv]ersion \d+\.\d+`)
+
+// Test that the header of each data file names Version and no other
+// version, so that the files cannot be updated one at a time.
+func TestVersion(t *testing.T) {
+	files, err := filepath.Glob("*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no data files")
+	}
+	for _, name := range files {
+		fh, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var versions []string
+		s := bufio.NewScanner(fh)
+		for s.Scan() && strings.HasPrefix(s.Text(), "#") {
+			versions = append(versions, versionRE.FindAllString(s.Text(), -1)...)
+		}
+		fh.Close()
+		if err := s.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) == 0 {
+			t.Errorf("%s: header has no version; want: %s", name, Version)
+		}
+		for _, v := range versions {
+			v = strings.TrimPrefix(strings.TrimPrefix(v, "version "), "Version ")
+			if v != Version && v != strings.TrimSuffix(Version, ".0") {
+				t.Errorf("%s: header has version %s; want: %s", name, v, Version)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package utfconv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The functions in this file implement the framing of the readUTF and
+// writeUTF methods of Java's DataInput and DataOutput: a 2-byte big-endian
+// length followed by the Modified UTF-8 encoding of a string. Modified UTF-8
+// encodes each UTF-16 code unit separately, so a supplementary character is
+// encoded as two 3-byte surrogates, and encodes NUL as the two bytes
+// 0xC0 0x80.
+
+var (
+	// ErrJavaUTFTooLong is returned by WriteJavaUTF when the Modified UTF-8
+	// encoding of a string is longer than 65535 bytes.
+	ErrJavaUTFTooLong = errors.New("utfconv: encoded string too long for Java UTF")
+
+	// ErrJavaUTFMalformed is returned by ReadJavaUTF for invalid Modified
+	// UTF-8 input.
+	ErrJavaUTFMalformed = errors.New("utfconv: malformed Java UTF input")
+)
+
+// ReadJavaUTF reads a string written by Java's DataOutput.writeUTF from r.
+// Like DataInput.readUTF, it accepts any byte sequence that the JDK
+// accepts, including NUL bytes and overlong 2 and 3 byte sequences, and
+// returns an error that wraps ErrJavaUTFMalformed for any other. Unpaired
+// surrogates are decoded as U+FFFD.
+//
+// If r ends before the length, ReadJavaUTF returns io.EOF; if it ends
+// within the length or the string it returns io.ErrUnexpectedEOF.
+func ReadJavaUTF(r io.Reader) (string, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return "", err
+	}
+	b := make([]byte, binary.BigEndian.Uint16(hdr[:]))
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return decodeJavaUTF(b)
+}
+
+func decodeJavaUTF(b []byte) (string, error) {
+	i := 0
+	for i < len(b) && b[i] < runeSelf {
+		i++
+	}
+	if i == len(b) {
+		return string(b), nil
+	}
+	u := make([]uint16, i, len(b))
+	for j := range u {
+		u[j] = uint16(b[j])
+	}
+	for i < len(b) {
+		c := b[i]
+		switch c >> 4 {
+		case 0, 1, 2, 3, 4, 5, 6, 7:
+			u = append(u, uint16(c))
+			i++
+		case 12, 13:
+			if len(b)-i < 2 {
+				return "", fmt.Errorf("%w: partial character at end", ErrJavaUTFMalformed)
+			}
+			c2 := b[i+1]
+			if c2&0xC0 != 0x80 {
+				return "", fmt.Errorf("%w around byte %d", ErrJavaUTFMalformed, i+1)
+			}
+			u = append(u, uint16(c&0x1F)<<6|uint16(c2&0x3F))
+			i += 2
+		case 14:
+			if len(b)-i < 3 {
+				return "", fmt.Errorf("%w: partial character at end", ErrJavaUTFMalformed)
+			}
+			c2, c3 := b[i+1], b[i+2]
+			if c2&0xC0 != 0x80 || c3&0xC0 != 0x80 {
+				return "", fmt.Errorf("%w around byte %d", ErrJavaUTFMalformed, i+1)
+			}
+			u = append(u, uint16(c&0x0F)<<12|uint16(c2&0x3F)<<6|uint16(c3&0x3F))
+			i += 3
+		default:
+			return "", fmt.Errorf("%w around byte %d", ErrJavaUTFMalformed, i)
+		}
+	}
+	return UTF16ToString(u), nil
+}
+
+// WriteJavaUTF writes s to w in the format read by Java's
+// DataInput.readUTF. Invalid UTF-8 is encoded as U+FFFD. If the Modified
+// UTF-8 encoding of s is longer than 65535 bytes, it writes nothing and
+// returns an error that wraps ErrJavaUTFTooLong.
+func WriteJavaUTF(w io.Writer, s string) error {
+	b, err := appendJavaUTF(nil, s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func appendJavaUTF(b []byte, s string) ([]byte, error) {
+	u := StringToUTF16(s)
+	n := 0
+	for _, c := range u {
+		switch {
+		case c != 0 && c < runeSelf:
+			n++
+		case c < 0x800:
+			n += 2
+		default:
+			n += 3
+		}
+	}
+	if n > 1<<16-1 {
+		return b, fmt.Errorf("%w: %d bytes", ErrJavaUTFTooLong, n)
+	}
+	b = binary.BigEndian.AppendUint16(b, uint16(n))
+	for _, c := range u {
+		switch {
+		case c != 0 && c < runeSelf:
+			b = append(b, byte(c))
+		case c < 0x800:
+			b = append(b, 0xC0|byte(c>>6), 0x80|byte(c&0x3F))
+		default:
+			b = append(b, 0xE0|byte(c>>12), 0x80|byte(c>>6&0x3F), 0x80|byte(c&0x3F))
+		}
+	}
+	return b, nil
+}
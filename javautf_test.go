@@ -0,0 +1,143 @@
+package utfconv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestJavaUTF(t *testing.T) {
+	tests := []struct {
+		s   string
+		enc []byte
+	}{
+		{"", []byte{0, 0}},
+		{"A", []byte{0, 1, 'A'}},
+		{"a\x00b", []byte{0, 4, 'a', 0xC0, 0x80, 'b'}},
+		{"é", []byte{0, 2, 0xC3, 0xA9}},
+		{"\u07FF\u0800", []byte{0, 5, 0xDF, 0xBF, 0xE0, 0xA0, 0x80}},
+		{"日本", []byte{0, 6, 0xE6, 0x97, 0xA5, 0xE6, 0x9C, 0xAC}},
+		{"\U0001F600", []byte{0, 6, 0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80}},
+		{"\uFFFF", []byte{0, 3, 0xEF, 0xBF, 0xBF}},
+	}
+	for _, x := range tests {
+		var buf bytes.Buffer
+		if err := WriteJavaUTF(&buf, x.s); err != nil || !bytes.Equal(buf.Bytes(), x.enc) {
+			t.Errorf("WriteJavaUTF(%q) = %x, %v; want: %x, <nil>", x.s, buf.Bytes(), err, x.enc)
+		}
+		r := bytes.NewReader(append(x.enc, 'x'))
+		if s, err := ReadJavaUTF(r); err != nil || s != x.s {
+			t.Errorf("ReadJavaUTF(%x) = %q, %v; want: %q, <nil>", x.enc, s, err, x.s)
+		}
+		if r.Len() != 1 {
+			t.Errorf("ReadJavaUTF(%x): %d bytes unread; want: 1", x.enc, r.Len())
+		}
+	}
+}
+
+func TestReadJavaUTF(t *testing.T) {
+	tests := []struct {
+		in  []byte
+		exp string
+	}{
+		{[]byte{0, 3, 'a', 0, 'b'}, "a\x00b"},            // NUL byte
+		{[]byte{0, 2, 0xC1, 0x81}, "A"},                  // overlong
+		{[]byte{0, 3, 0xE0, 0x81, 0x81}, "A"},            // overlong
+		{[]byte{0, 4, 0xED, 0xA0, 0xBD, 'a'}, "\uFFFDa"}, // unpaired high surrogate
+		{[]byte{0, 3, 0xED, 0xB8, 0x80}, "\uFFFD"},       // unpaired low surrogate
+		{[]byte{0, 7, 'a', 0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80}, "a\U0001F600"},
+	}
+	for _, x := range tests {
+		if s, err := ReadJavaUTF(bytes.NewReader(x.in)); err != nil || s != x.exp {
+			t.Errorf("ReadJavaUTF(%x) = %q, %v; want: %q, <nil>", x.in, s, err, x.exp)
+		}
+	}
+
+	malformed := [][]byte{
+		{0, 1, 0x80},
+		{0, 1, 0xBF},
+		{0, 4, 0xF0, 0x9F, 0x98, 0x80},
+		{0, 1, 0xFF},
+		{0, 1, 0xC3},             // partial
+		{0, 2, 0xE6, 0x97},       // partial
+		{0, 2, 0xC3, 'a'},        // bad continuation
+		{0, 3, 0xE6, 0x97, 0xC0}, // bad continuation
+	}
+	for _, in := range malformed {
+		if s, err := ReadJavaUTF(bytes.NewReader(in)); !errors.Is(err, ErrJavaUTFMalformed) {
+			t.Errorf("ReadJavaUTF(%x) = %q, %v; want: %v", in, s, err, ErrJavaUTFMalformed)
+		}
+	}
+
+	eofTests := []struct {
+		in  []byte
+		err error
+	}{
+		{nil, io.EOF},
+		{[]byte{0}, io.ErrUnexpectedEOF},
+		{[]byte{0, 1}, io.ErrUnexpectedEOF},
+		{[]byte{0, 3, 'a', 'b'}, io.ErrUnexpectedEOF},
+	}
+	for _, x := range eofTests {
+		if _, err := ReadJavaUTF(bytes.NewReader(x.in)); err != x.err {
+			t.Errorf("ReadJavaUTF(%x): err = %v; want: %v", x.in, err, x.err)
+		}
+	}
+}
+
+func TestJavaUTFLimit(t *testing.T) {
+	tests := []struct {
+		s  string
+		ok bool
+	}{
+		{strings.Repeat("a", 1<<16-1), true},
+		{strings.Repeat("a", 1<<16), false},
+		{strings.Repeat("日", (1<<16-1)/3), true},
+		{strings.Repeat("日", (1<<16-1)/3) + "a", false},
+		{strings.Repeat("\x00", 1<<15), false},
+		{strings.Repeat("\x00", 1<<15-1) + "a", true},
+	}
+	for _, x := range tests {
+		var buf bytes.Buffer
+		err := WriteJavaUTF(&buf, x.s)
+		if x.ok {
+			if err != nil {
+				t.Errorf("WriteJavaUTF(len %d): %v", len(x.s), err)
+				continue
+			}
+			if s, err := ReadJavaUTF(&buf); err != nil || s != x.s {
+				t.Errorf("ReadJavaUTF(WriteJavaUTF(len %d)): %v", len(x.s), err)
+			}
+		} else if !errors.Is(err, ErrJavaUTFTooLong) || buf.Len() != 0 {
+			t.Errorf("WriteJavaUTF(len %d) = %d bytes, %v; want: 0 bytes, %v",
+				len(x.s), buf.Len(), err, ErrJavaUTFTooLong)
+		}
+	}
+}
+
+func BenchmarkReadJavaUTF_SixtyFourUnicode(b *testing.B) {
+	var buf bytes.Buffer
+	if err := WriteJavaUTF(&buf, SixtyFourUnicodeChars); err != nil {
+		b.Fatal(err)
+	}
+	enc := buf.Bytes()
+	r := bytes.NewReader(enc)
+	b.SetBytes(int64(len(enc)))
+	for i := 0; i < b.N; i++ {
+		r.Reset(enc)
+		if _, err := ReadJavaUTF(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteJavaUTF_SixtyFourUnicode(b *testing.B) {
+	b.SetBytes(int64(len(SixtyFourUnicodeChars)))
+	for i := 0; i < b.N; i++ {
+		if err := WriteJavaUTF(io.Discard, SixtyFourUnicodeChars); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
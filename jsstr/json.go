@@ -0,0 +1,202 @@
+package jsstr
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// JSON strings produced by JavaScript may contain unpaired surrogates as
+// \uXXXX escapes, which encoding/json decodes as U+FFFD. The functions in
+// this file decode and encode JSON strings without losing them.
+
+var errQuote = errors.New("jsstr: JSON string is not quoted")
+
+const hex = "0123456789abcdef"
+
+// QuoteUTF16 returns the JSON string literal of the code units s. Unpaired
+// surrogates are escaped as \uXXXX, as are control characters, U+2028 and
+// U+2029. Other characters are written as UTF-8. HTML characters are not
+// escaped.
+func QuoteUTF16(s []uint16) string {
+	return string(appendQuote(make([]byte, 0, len(s)+2), s))
+}
+
+// Quote returns the JSON string literal of s, whose WTF-8 encoded unpaired
+// surrogates are escaped as \uXXXX. See QuoteUTF16.
+func Quote(s string) string {
+	return string(appendQuote(make([]byte, 0, len(s)+2), toUnits(s)))
+}
+
+func appendQuote(b []byte, s []uint16) []byte {
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b = append(b, '\\', byte(c))
+		case c == '\n':
+			b = append(b, '\\', 'n')
+		case c == '\r':
+			b = append(b, '\\', 'r')
+		case c == '\t':
+			b = append(b, '\\', 't')
+		case c < 0x20, c == 0x2028, c == 0x2029:
+			b = appendEscape(b, c)
+		case c < utf8.RuneSelf:
+			b = append(b, byte(c))
+		case utf16.IsSurrogate(rune(c)):
+			if r, n := utfconv.DecodeRuneUTF16(s[i:]); n == 2 {
+				b = utf8.AppendRune(b, r)
+				i++
+			} else {
+				b = appendEscape(b, c)
+			}
+		default:
+			b = utf8.AppendRune(b, rune(c))
+		}
+	}
+	return append(b, '"')
+}
+
+// appendEscape appends the \uXXXX escape of c to b.
+func appendEscape(b []byte, c uint16) []byte {
+	return append(b, '\\', 'u', hex[c>>12], hex[c>>8&0xF], hex[c>>4&0xF], hex[c&0xF])
+}
+
+// UnquoteUTF16 returns the code units of the JSON string literal q. Escaped
+// surrogates are decoded exactly, whether or not they are paired. Invalid
+// UTF-8 is decoded as one U+FFFD per byte, as with encoding/json. It returns
+// an error if q is not a valid JSON string literal.
+func UnquoteUTF16(q string) ([]uint16, error) {
+	if len(q) < 2 || q[0] != '"' || q[len(q)-1] != '"' {
+		return nil, errQuote
+	}
+	q = q[1 : len(q)-1]
+	a := make([]uint16, 0, len(q))
+	for i := 0; i < len(q); {
+		c := q[i]
+		switch {
+		case c == '\\':
+			if i+1 == len(q) {
+				return nil, fmt.Errorf("jsstr: invalid escape at offset %d in JSON string", i+1)
+			}
+			switch q[i+1] {
+			case '"', '\\', '/':
+				a = append(a, uint16(q[i+1]))
+			case 'b':
+				a = append(a, '\b')
+			case 'f':
+				a = append(a, '\f')
+			case 'n':
+				a = append(a, '\n')
+			case 'r':
+				a = append(a, '\r')
+			case 't':
+				a = append(a, '\t')
+			case 'u':
+				u, ok := unhex(q[i+2:])
+				if !ok {
+					return nil, fmt.Errorf("jsstr: invalid \\u escape at offset %d in JSON string", i+1)
+				}
+				a = append(a, u)
+				i += 4
+			default:
+				return nil, fmt.Errorf("jsstr: invalid escape at offset %d in JSON string", i+1)
+			}
+			i += 2
+		case c == '"' || c < 0x20:
+			return nil, fmt.Errorf("jsstr: invalid character %q at offset %d in JSON string", c, i+1)
+		case c < utf8.RuneSelf:
+			a = append(a, uint16(c))
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(q[i:])
+			a = utfconv.AppendRuneUTF16(a, r)
+			i += size
+		}
+	}
+	return a, nil
+}
+
+// unhex decodes the 4 hexadecimal digits at the start of s.
+func unhex(s string) (uint16, bool) {
+	if len(s) < 4 {
+		return 0, false
+	}
+	var u uint16
+	for i := 0; i < 4; i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c -= 'a' - 10
+		case 'A' <= c && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, false
+		}
+		u = u<<4 | uint16(c)
+	}
+	return u, true
+}
+
+// Unquote returns the JSON string literal q decoded as WTF-8: unpaired
+// surrogates are encoded as WTF-8 so that Quote restores them. See
+// UnquoteUTF16.
+func Unquote(q string) (string, error) {
+	a, err := UnquoteUTF16(q)
+	if err != nil {
+		return "", err
+	}
+	return fromUnits(a), nil
+}
+
+// fromUnits returns the WTF-8 encoding of the code units a.
+func fromUnits(a []uint16) string {
+	b := make([]byte, 0, utfconv.UTF8EncodedLen(a))
+	for i := 0; i < len(a); i++ {
+		c := a[i]
+		switch {
+		case c < utf8.RuneSelf:
+			b = append(b, byte(c))
+		case utf16.IsSurrogate(rune(c)):
+			if r, n := utfconv.DecodeRuneUTF16(a[i:]); n == 2 {
+				b = utf8.AppendRune(b, r)
+				i++
+			} else {
+				b = appendSurrogate(b, c)
+			}
+		default:
+			b = utf8.AppendRune(b, rune(c))
+		}
+	}
+	return string(b)
+}
+
+// A UTF16String is a string of UTF-16 code units that is encoded as a JSON
+// string without replacing unpaired surrogates.
+type UTF16String []uint16
+
+// MarshalJSON implements json.Marshaler. See QuoteUTF16.
+func (s UTF16String) MarshalJSON() ([]byte, error) {
+	return appendQuote(make([]byte, 0, len(s)+2), s), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See UnquoteUTF16. As with
+// encoding/json, null leaves s unchanged.
+func (s *UTF16String) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	a, err := UnquoteUTF16(string(b))
+	if err != nil {
+		return err
+	}
+	*s = a
+	return nil
+}
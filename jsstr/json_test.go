@@ -0,0 +1,134 @@
+package jsstr
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		s []uint16
+		q string
+	}{
+		{[]uint16{}, `""`},
+		{[]uint16{'a', '"', '\\', '/'}, `"a\"\\/"`},
+		{[]uint16{'\n', '\r', '\t', '\b', 0, 0x1F}, `"\n\r\t\u0008\u0000\u001f"`},
+		{[]uint16{'<', '&', 0x2028, 0x2029}, `"<&\u2028\u2029"`},
+		{[]uint16{0x65E5, 0xD83D, 0xDE00}, "\"日\U0001F600\""},
+		{[]uint16{'a', 0xD83D}, `"a\ud83d"`},
+		{[]uint16{0xDE00, 'a'}, `"\ude00a"`},
+		{[]uint16{0xDE00, 0xD83D}, `"\ude00\ud83d"`},
+		{[]uint16{0xFFFD}, "\"\uFFFD\""},
+	}
+	for _, x := range tests {
+		if q := QuoteUTF16(x.s); q != x.q {
+			t.Errorf("QuoteUTF16(%v) = %s; want: %s", x.s, q, x.q)
+		}
+		if s, err := UnquoteUTF16(x.q); err != nil || !reflect.DeepEqual(s, x.s) {
+			t.Errorf("UnquoteUTF16(%s) = %v, %v; want: %v, <nil>", x.q, s, err, x.s)
+		}
+	}
+}
+
+func TestQuoteWTF8(t *testing.T) {
+	for _, s := range testStrings {
+		q := Quote(s)
+		u, err := Unquote(q)
+		if err != nil {
+			t.Errorf("Unquote(%s): %v", q, err)
+			continue
+		}
+		if !reflect.DeepEqual(toUnits(u), toUnits(s)) {
+			t.Errorf("Unquote(Quote(%q)) = %q", s, u)
+		}
+		// Strings without unpaired surrogates are decoded by encoding/json.
+		if hasSurrogates(s) {
+			continue
+		}
+		var js string
+		if err := json.Unmarshal([]byte(q), &js); err != nil {
+			t.Errorf("json.Unmarshal(%s): %v", q, err)
+		} else if want := fromUnits(toUnits(s)); js != want {
+			t.Errorf("json.Unmarshal(%s) = %q; want: %q", q, js, want)
+		}
+	}
+	if s, err := Unquote(`"a😀\ud83d"`); err != nil || s != "a\U0001F600\xed\xa0\xbd" {
+		t.Errorf(`Unquote("a😀\ud83d") = %q, %v`, s, err)
+	}
+}
+
+func TestUnquoteUTF16(t *testing.T) {
+	tests := []struct {
+		q string
+		s []uint16
+	}{
+		{`"éÉ\/\b\f"`, []uint16{0xE9, 0xC9, '/', '\b', '\f'}},
+		{"\"a\xffb\"", []uint16{'a', 0xFFFD, 'b'}},
+		{"\"\xed\xa0\xbd\"", []uint16{0xFFFD, 0xFFFD, 0xFFFD}},
+	}
+	for _, x := range tests {
+		if s, err := UnquoteUTF16(x.q); err != nil || !reflect.DeepEqual(s, x.s) {
+			t.Errorf("UnquoteUTF16(%q) = %v, %v; want: %v, <nil>", x.q, s, err, x.s)
+		}
+	}
+
+	errTests := []string{
+		``,
+		`"`,
+		`abc`,
+		`"abc`,
+		`'abc'`,
+		`"a"b"`,
+		`"\"`,
+		`"\x"`,
+		`"\u12"`,
+		`"\u12g4"`,
+		"\"a\nb\"",
+		"\"\x00\"",
+	}
+	for _, q := range errTests {
+		if s, err := UnquoteUTF16(q); err == nil {
+			t.Errorf("UnquoteUTF16(%q) = %v; expected error", q, s)
+		}
+		var js string
+		if err := json.Unmarshal([]byte(q), &js); err == nil {
+			t.Errorf("json.Unmarshal(%q) = %q; expected error", q, js)
+		}
+	}
+}
+
+func TestUTF16String(t *testing.T) {
+	type message struct {
+		Text UTF16String  `json:"text"`
+		Ptr  *UTF16String `json:"ptr"`
+	}
+	in := `{"text":"a\ud83dA 😀 \u2028<","ptr":null}`
+	var m message
+	if err := json.Unmarshal([]byte(in), &m); err != nil {
+		t.Fatal(err)
+	}
+	exp := UTF16String{'a', 0xD83D, 'A', ' ', 0xD83D, 0xDE00, ' ', 0x2028, '<'}
+	if !reflect.DeepEqual(m.Text, exp) || m.Ptr != nil {
+		t.Errorf("json.Unmarshal(%s) = %+v; want: %v", in, m, exp)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := "{\"text\":\"a\\ud83dA \U0001F600 \\u2028\\u003c\",\"ptr\":null}"
+	if string(b) != out {
+		t.Errorf("json.Marshal(%+v) = %s; want: %s", m, b, out)
+	}
+
+	s := UTF16String{'x'}
+	if err := json.Unmarshal([]byte("null"), &s); err != nil || !reflect.DeepEqual(s, UTF16String{'x'}) {
+		t.Errorf("json.Unmarshal(null) = %v, %v; want: [120], <nil>", s, err)
+	}
+	if err := json.Unmarshal([]byte("123"), &s); err == nil {
+		t.Error("json.Unmarshal(123): expected error")
+	}
+	if b, err := json.Marshal(UTF16String(nil)); err != nil || string(b) != `""` {
+		t.Errorf(`json.Marshal(UTF16String(nil)) = %s, %v; want: "", <nil>`, b, err)
+	}
+}
@@ -0,0 +1,285 @@
+// Package jsstr implements JavaScript string operations on Go strings.
+//
+// JavaScript strings are sequences of UTF-16 code units and all indexes and
+// lengths are measured in code units. The functions in this package accept
+// and return UTF-16 indexes without converting the string to a []uint16.
+//
+// JavaScript strings may contain unpaired surrogates, for example when a
+// string is sliced between the two code units of a surrogate pair. Go
+// strings cannot hold unpaired surrogates as valid UTF-8, so this package
+// represents them using WTF-8: the three byte generalized UTF-8 encoding of
+// the surrogate code point. All functions in this package treat a WTF-8
+// surrogate as a single code unit. Invalid UTF-8 is treated as one U+FFFD
+// code unit per byte, as with utfconv.StringToUTF16.
+package jsstr
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// next returns the first code point of s and its length in bytes. WTF-8
+// encoded surrogates are returned as their surrogate code point.
+func next(s string) (rune, int) {
+	if len(s) >= 3 && s[0] == 0xED && 0xA0 <= s[1] && s[1] <= 0xBF && 0x80 <= s[2] && s[2] <= 0xBF {
+		return rune(s[0]&0x0F)<<12 | rune(s[1]&0x3F)<<6 | rune(s[2]&0x3F), 3
+	}
+	return utf8.DecodeRuneInString(s)
+}
+
+// hasSurrogates reports whether s contains a WTF-8 encoded surrogate.
+func hasSurrogates(s string) bool {
+	for {
+		i := strings.IndexByte(s, 0xED)
+		if i < 0 || i+1 >= len(s) {
+			return false
+		}
+		if 0xA0 <= s[i+1] && s[i+1] <= 0xBF {
+			return true
+		}
+		s = s[i+1:]
+	}
+}
+
+// appendSurrogate appends the WTF-8 encoding of surrogate u to b.
+func appendSurrogate(b []byte, u uint16) []byte {
+	return append(b, 0xE0|byte(u>>12), 0x80|byte(u>>6)&0x3F, 0x80|byte(u)&0x3F)
+}
+
+// Length returns the length of s in UTF-16 code units, the equivalent of
+// String.prototype.length.
+func Length(s string) int {
+	if !hasSurrogates(s) {
+		return utfconv.UTF16EncodedLenString(s)
+	}
+	n := 0
+	for len(s) > 0 {
+		r, size := next(s)
+		if utfconv.RuneLenUTF16(r) == 2 {
+			n++
+		}
+		n++
+		s = s[size:]
+	}
+	return n
+}
+
+// seek returns the byte index of code unit n of s. If n falls between the
+// code units of a surrogate pair, the index of the encoded pair is returned
+// and mid is true. If n is past the end of s, len(s) is returned.
+func seek(s string, n int) (i int, mid bool) {
+	for i < len(s) && n > 0 {
+		if s[i] < utf8.RuneSelf {
+			i++
+			n--
+			continue
+		}
+		r, size := next(s[i:])
+		if utfconv.RuneLenUTF16(r) == 2 {
+			if n == 1 {
+				return i, true
+			}
+			n--
+		}
+		i += size
+		n--
+	}
+	return i, false
+}
+
+// CharCodeAt returns the UTF-16 code unit at index i of s, the equivalent of
+// String.prototype.charCodeAt. If i is out of range ok is false, where
+// JavaScript returns NaN.
+func CharCodeAt(s string, i int) (u uint16, ok bool) {
+	b, mid := seek(s, i)
+	if i < 0 || b == len(s) {
+		return 0, false
+	}
+	r, _ := next(s[b:])
+	if utfconv.RuneLenUTF16(r) == 2 {
+		hi, lo := utf16.EncodeRune(r)
+		if mid {
+			return uint16(lo), true
+		}
+		return uint16(hi), true
+	}
+	return uint16(r), true
+}
+
+// CodePointAt returns the code point that starts at UTF-16 index i of s, the
+// equivalent of String.prototype.codePointAt. If i is the second code unit
+// of a surrogate pair, or an unpaired surrogate, the code unit is returned.
+// If i is out of range ok is false, where JavaScript returns undefined.
+func CodePointAt(s string, i int) (r rune, ok bool) {
+	b, mid := seek(s, i)
+	if i < 0 || b == len(s) {
+		return 0, false
+	}
+	r, size := next(s[b:])
+	switch {
+	case mid:
+		_, lo := utf16.EncodeRune(r)
+		return lo, true
+	case utf16.IsSurrogate(r):
+		// a WTF-8 high surrogate followed by a low surrogate forms a pair
+		r2, _ := next(s[b+size:])
+		if p := utf16.DecodeRune(r, r2); p != utf8.RuneError {
+			return p, true
+		}
+	}
+	return r, true
+}
+
+// relIndex converts the relative index used by String.prototype.slice to an
+// absolute index in [0, length].
+func relIndex(i, length int) int {
+	if i < 0 {
+		i += length
+		if i < 0 {
+			i = 0
+		}
+	} else if i > length {
+		i = length
+	}
+	return i
+}
+
+// Slice returns the code units of s in [start, end), the equivalent of
+// String.prototype.slice. Negative indexes count back from the end of s.
+// Slicing between the code units of a surrogate pair produces an unpaired,
+// WTF-8 encoded, surrogate.
+func Slice(s string, start, end int) string {
+	length := Length(s)
+	start = relIndex(start, length)
+	end = relIndex(end, length)
+	if start >= end {
+		return ""
+	}
+	return slice(s, start, end)
+}
+
+// SliceFrom returns the code units of s from start to the end of s, the
+// equivalent of calling String.prototype.slice with a single argument.
+func SliceFrom(s string, start int) string {
+	length := Length(s)
+	if start = relIndex(start, length); start >= length {
+		return ""
+	}
+	return slice(s, start, length)
+}
+
+// Substring returns the code units of s between start and end, the
+// equivalent of String.prototype.substring. Negative indexes are treated as
+// zero and the indexes are swapped if start is greater than end.
+func Substring(s string, start, end int) string {
+	length := Length(s)
+	clamp := func(i int) int {
+		if i < 0 {
+			return 0
+		}
+		if i > length {
+			return length
+		}
+		return i
+	}
+	start, end = clamp(start), clamp(end)
+	if start > end {
+		start, end = end, start
+	}
+	if start == end {
+		return ""
+	}
+	return slice(s, start, end)
+}
+
+// slice returns the code units [start, end) of s, where
+// 0 <= start < end <= Length(s).
+func slice(s string, start, end int) string {
+	bs, smid := seek(s, start)
+	be, emid := seek(s, end)
+	if !smid && !emid {
+		return s[bs:be]
+	}
+	b := make([]byte, 0, be-bs+6)
+	if smid {
+		r, size := next(s[bs:])
+		_, lo := utf16.EncodeRune(r)
+		b = appendSurrogate(b, uint16(lo))
+		bs += size
+	}
+	if bs < be {
+		b = append(b, s[bs:be]...)
+	}
+	if emid {
+		r, _ := next(s[be:])
+		hi, _ := utf16.EncodeRune(r)
+		b = appendSurrogate(b, uint16(hi))
+	}
+	return string(b)
+}
+
+// IndexOf returns the UTF-16 index of the first occurrence of search in s at
+// or after UTF-16 index from, or -1 if there is none. It is the equivalent of
+// String.prototype.indexOf, including matching unpaired surrogates in search
+// against either half of a surrogate pair in s.
+func IndexOf(s, search string, from int) int {
+	length := Length(s)
+	if from < 0 {
+		from = 0
+	} else if from > length {
+		from = length
+	}
+	if search == "" {
+		return from
+	}
+	if hasSurrogates(s) || hasSurrogates(search) ||
+		!utf8.ValidString(s) || !utf8.ValidString(search) {
+		return indexUnits(toUnits(s), toUnits(search), from)
+	}
+	i, mid := seek(s, from)
+	if mid {
+		// search starts with a complete code point so cannot match the
+		// second half of a surrogate pair
+		_, size := next(s[i:])
+		i += size
+	}
+	j := strings.Index(s[i:], search)
+	if j < 0 {
+		return -1
+	}
+	return utfconv.UTF16Index(s, i+j)
+}
+
+// toUnits returns the UTF-16 code units of s.
+func toUnits(s string) []uint16 {
+	if !hasSurrogates(s) {
+		return utfconv.StringToUTF16(s)
+	}
+	a := make([]uint16, 0, len(s))
+	for len(s) > 0 {
+		r, size := next(s)
+		if utf16.IsSurrogate(r) {
+			a = append(a, uint16(r))
+		} else {
+			a = utfconv.AppendRuneUTF16(a, r)
+		}
+		s = s[size:]
+	}
+	return a
+}
+
+func indexUnits(s, search []uint16, from int) int {
+Loop:
+	for i := from; i+len(search) <= len(s); i++ {
+		for j, u := range search {
+			if s[i+j] != u {
+				continue Loop
+			}
+		}
+		return i
+	}
+	return -1
+}
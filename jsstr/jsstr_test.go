@@ -0,0 +1,188 @@
+package jsstr
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+var testStrings = []string{
+	"",
+	"abc",
+	"日本語",
+	"a\U0001F600b",
+	"\U0001F600\U0001F601",
+	"x\xffy",
+	"\xed\xa0\xbd",             // unpaired high surrogate
+	"a\xed\xb8\x80b",           // unpaired low surrogate
+	"\xed\xa0\xbd\xed\xb8\x80", // surrogate pair encoded as two surrogates
+}
+
+// units returns the code units of s as JavaScript would see them.
+func units(s string) []uint16 {
+	var a []uint16
+	for len(s) > 0 {
+		r, size := next(s)
+		a = append(a, utf16.Encode([]rune{r})...)
+		if 0xd800 <= r && r < 0xe000 {
+			a[len(a)-1] = uint16(r)
+		}
+		s = s[size:]
+	}
+	return a
+}
+
+func TestLength(t *testing.T) {
+	for _, s := range testStrings {
+		if n, exp := Length(s), len(units(s)); n != exp {
+			t.Errorf("Length(%q) = %d; want: %d", s, n, exp)
+		}
+	}
+}
+
+func TestCharCodeAt(t *testing.T) {
+	for _, s := range testStrings {
+		u := units(s)
+		for i := -1; i <= len(u); i++ {
+			c, ok := CharCodeAt(s, i)
+			if i < 0 || i >= len(u) {
+				if ok {
+					t.Errorf("CharCodeAt(%q, %d) = %d, true; want: false", s, i, c)
+				}
+				continue
+			}
+			if !ok || c != u[i] {
+				t.Errorf("CharCodeAt(%q, %d) = %#x, %t; want: %#x", s, i, c, ok, u[i])
+			}
+		}
+	}
+}
+
+func TestCodePointAt(t *testing.T) {
+	tests := []struct {
+		s  string
+		i  int
+		r  rune
+		ok bool
+	}{
+		{"abc", 1, 'b', true},
+		{"abc", 3, 0, false},
+		{"abc", -1, 0, false},
+		{"a\U0001F600b", 1, 0x1F600, true},
+		{"a\U0001F600b", 2, 0xDE00, true},
+		{"a\U0001F600b", 3, 'b', true},
+		{"\xed\xa0\xbd", 0, 0xD83D, true},
+		{"\xed\xa0\xbd\xed\xb8\x80", 0, 0x1F600, true},
+		{"\xed\xa0\xbd\xed\xb8\x80", 1, 0xDE00, true},
+	}
+	for _, x := range tests {
+		r, ok := CodePointAt(x.s, x.i)
+		if r != x.r || ok != x.ok {
+			t.Errorf("CodePointAt(%q, %d) = %#x, %t; want: %#x, %t", x.s, x.i, r, ok, x.r, x.ok)
+		}
+	}
+}
+
+// jsSlice is a reference implementation of String.prototype.slice.
+func jsSlice(u []uint16, start, end int) []uint16 {
+	start, end = relIndex(start, len(u)), relIndex(end, len(u))
+	if start >= end {
+		return []uint16{}
+	}
+	return u[start:end]
+}
+
+func equalUnits(a, b []uint16) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+func TestSlice(t *testing.T) {
+	for _, s := range testStrings {
+		u := units(s)
+		for start := -len(u) - 1; start <= len(u)+1; start++ {
+			for end := -len(u) - 1; end <= len(u)+1; end++ {
+				got := units(Slice(s, start, end))
+				exp := jsSlice(u, start, end)
+				if !equalUnits(got, exp) {
+					t.Errorf("Slice(%q, %d, %d) = %#x; want: %#x", s, start, end, got, exp)
+				}
+			}
+			got := units(SliceFrom(s, start))
+			exp := jsSlice(u, start, len(u))
+			if !equalUnits(got, exp) {
+				t.Errorf("SliceFrom(%q, %d) = %#x; want: %#x", s, start, got, exp)
+			}
+		}
+	}
+}
+
+func TestSliceSurrogates(t *testing.T) {
+	const s = "a\U0001F600b"
+	if x := Slice(s, 0, 2); x != "a\xed\xa0\xbd" {
+		t.Errorf("Slice(%q, 0, 2) = %q; want: %q", s, x, "a\xed\xa0\xbd")
+	}
+	if x := Slice(s, 2, 4); x != "\xed\xb8\x80b" {
+		t.Errorf("Slice(%q, 2, 4) = %q; want: %q", s, x, "\xed\xb8\x80b")
+	}
+	// joining the halves forms a surrogate pair again
+	x := Slice(s, 0, 2) + Slice(s, 2, 4)
+	if n := Length(x); n != 4 {
+		t.Errorf("Length(%q) = %d; want: 4", x, n)
+	}
+	if r, _ := CodePointAt(x, 1); r != 0x1F600 {
+		t.Errorf("CodePointAt(%q, 1) = %#x; want: %#x", x, r, 0x1F600)
+	}
+	if n := IndexOf(x, Slice(s, 1, 3), 0); n != 1 {
+		t.Errorf("IndexOf(%q, %q, 0) = %d; want: 1", x, Slice(s, 1, 3), n)
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	tests := []struct {
+		s          string
+		start, end int
+		out        string
+	}{
+		{"abcdef", 1, 3, "bc"},
+		{"abcdef", 3, 1, "bc"},
+		{"abcdef", -5, 2, "ab"},
+		{"abcdef", 4, 100, "ef"},
+		{"abcdef", 2, 2, ""},
+		{"日本語", 1, 2, "本"},
+		{"a\U0001F600b", 3, 0, "a\U0001F600"},
+	}
+	for _, x := range tests {
+		if s := Substring(x.s, x.start, x.end); s != x.out {
+			t.Errorf("Substring(%q, %d, %d) = %q; want: %q", x.s, x.start, x.end, s, x.out)
+		}
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	tests := []struct {
+		s, search string
+		from, out int
+	}{
+		{"abcabc", "bc", 0, 1},
+		{"abcabc", "bc", 2, 4},
+		{"abcabc", "bc", 5, -1},
+		{"abcabc", "", 3, 3},
+		{"abcabc", "", 10, 6},
+		{"abcabc", "a", -5, 0},
+		{"\U0001F600x\U0001F600", "\U0001F600", 0, 0},
+		{"\U0001F600x\U0001F600", "\U0001F600", 1, 3},
+		{"\U0001F600x\U0001F600", "x", 1, 2},
+		{"日本語", "語", 0, 2},
+		// unpaired surrogates match half of a pair
+		{"a\U0001F600", "\xed\xa0\xbd", 0, 1},
+		{"a\U0001F600", "\xed\xb8\x80", 0, 2},
+		{"a\U0001F600", "\xed\xb8\x80", 3, -1},
+		// invalid UTF-8 is U+FFFD
+		{"a\xffb", "\uFFFDb", 0, 1},
+	}
+	for _, x := range tests {
+		if n := IndexOf(x.s, x.search, x.from); n != x.out {
+			t.Errorf("IndexOf(%q, %q, %d) = %d; want: %d", x.s, x.search, x.from, n, x.out)
+		}
+	}
+}
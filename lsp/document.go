@@ -0,0 +1,354 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// A Document is an editable document that maintains an index of its lines
+// and their byte and UTF-16 lengths. Lines are stored in a
+// balanced tree (an implicit treap) so that applying an edit and converting
+// between offsets and positions take O(log n) time in the number of lines,
+// plus time proportional to the size of the lines touched.
+//
+// A Document is not safe for concurrent use.
+type Document struct {
+	root *node
+	enc  PositionEncodingKind
+	seed uint64
+}
+
+// A node is a single line, including its line terminator, and the totals of
+// the subtree rooted at it.
+type node struct {
+	left, right *node
+	prio        uint64
+	text        string
+	units       int // UTF-16 code units in text
+
+	// subtree totals
+	count    int
+	sumBytes int
+	sumUnits int
+}
+
+func (n *node) update() {
+	n.count = 1
+	n.sumBytes = len(n.text)
+	n.sumUnits = n.units
+	if l := n.left; l != nil {
+		n.count += l.count
+		n.sumBytes += l.sumBytes
+		n.sumUnits += l.sumUnits
+	}
+	if r := n.right; r != nil {
+		n.count += r.count
+		n.sumBytes += r.sumBytes
+		n.sumUnits += r.sumUnits
+	}
+}
+
+func (n *node) size() int {
+	if n == nil {
+		return 0
+	}
+	return n.count
+}
+
+// merge joins a and b, all lines of a precede those of b.
+func merge(a, b *node) *node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.prio > b.prio {
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = merge(a, b.left)
+	b.update()
+	return b
+}
+
+// split splits n into its first k lines and the remainder.
+func split(n *node, k int) (*node, *node) {
+	if n == nil {
+		return nil, nil
+	}
+	if k <= n.left.size() {
+		l, r := split(n.left, k)
+		n.left = r
+		n.update()
+		return l, n
+	}
+	l, r := split(n.right, k-n.left.size()-1)
+	n.right = l
+	n.update()
+	return n, r
+}
+
+// NewDocument returns a Document with the given content that counts
+// characters using enc. An empty enc is treated as UTF16.
+func NewDocument(content string, enc PositionEncodingKind) (*Document, error) {
+	enc, err := checkEncoding(enc)
+	if err != nil {
+		return nil, err
+	}
+	d := &Document{enc: enc, seed: 0x9E3779B97F4A7C15}
+	d.root = d.build(content)
+	return d, nil
+}
+
+// rand returns the next treap priority (xorshift64*).
+func (d *Document) rand() uint64 {
+	d.seed ^= d.seed >> 12
+	d.seed ^= d.seed << 25
+	d.seed ^= d.seed >> 27
+	return d.seed * 2685821657736338717
+}
+
+func (d *Document) newNode(text string) *node {
+	n := &node{
+		prio:  d.rand(),
+		text:  text,
+		units: utfconv.UTF16EncodedLenString(text),
+	}
+	n.update()
+	return n
+}
+
+// build returns a tree of the lines of s.
+func (d *Document) build(s string) *node {
+	var root *node
+	starts := lineStarts(s)
+	for i, start := range starts {
+		end := len(s)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		root = merge(root, d.newNode(s[start:end]))
+	}
+	return root
+}
+
+// Encoding returns the position encoding used by d.
+func (d *Document) Encoding() PositionEncodingKind { return d.enc }
+
+// Len returns the length of the document in bytes.
+func (d *Document) Len() int { return d.root.sumBytes }
+
+// LenUTF16 returns the length of the document in UTF-16 code units.
+func (d *Document) LenUTF16() int { return d.root.sumUnits }
+
+// LineCount returns the number of lines in the document. A document that
+// ends with a line terminator has an empty final line.
+func (d *Document) LineCount() int { return d.root.count }
+
+// Content returns the document content.
+func (d *Document) Content() string {
+	var b strings.Builder
+	b.Grow(d.Len())
+	var walk func(n *node)
+	walk = func(n *node) {
+		if n != nil {
+			walk(n.left)
+			b.WriteString(n.text)
+			walk(n.right)
+		}
+	}
+	walk(d.root)
+	return b.String()
+}
+
+// lineAt returns line k and the byte and UTF-16 lengths of all lines
+// preceding it.
+func (d *Document) lineAt(k int) (n *node, bytes, units int) {
+	n = d.root
+	for {
+		ls := n.left.size()
+		if k < ls {
+			n = n.left
+			continue
+		}
+		if l := n.left; l != nil {
+			bytes += l.sumBytes
+			units += l.sumUnits
+		}
+		if k == ls {
+			return n, bytes, units
+		}
+		k -= ls + 1
+		bytes += len(n.text)
+		units += n.units
+		n = n.right
+	}
+}
+
+// find returns the index of the line that contains the position off, where
+// key returns the subtree total (sum) and line length (self) of a node in the
+// unit off is measured in. The position at the end of the document belongs
+// to the last line. The total of the preceding lines is also returned.
+func (d *Document) find(off int, key func(n *node) (sum, self int)) (k, before int) {
+	if sum, _ := key(d.root); off >= sum {
+		last, _, _ := d.lineAt(d.root.count - 1)
+		_, self := key(last)
+		return d.root.count - 1, sum - self
+	}
+	n := d.root
+	for {
+		var ls int
+		if n.left != nil {
+			ls, _ = key(n.left)
+		}
+		_, self := key(n)
+		switch {
+		case off < ls:
+			n = n.left
+		case off < ls+self:
+			return k + n.left.size(), before + ls
+		default:
+			off -= ls + self
+			before += ls + self
+			k += n.left.size() + 1
+			n = n.right
+		}
+	}
+}
+
+func byteKey(n *node) (int, int) { return n.sumBytes, len(n.text) }
+func unitKey(n *node) (int, int) { return n.sumUnits, n.units }
+
+// Line returns the content of line k excluding the line terminator.
+func (d *Document) Line(k int) (string, error) {
+	if k < 0 || k >= d.LineCount() {
+		return "", fmt.Errorf("lsp: line %d out of range [0, %d)", k, d.LineCount())
+	}
+	n, _, _ := d.lineAt(k)
+	return trimEOL(n.text), nil
+}
+
+// UTF16Index returns the number of UTF-16 code units required to encode the
+// document content preceding byte offset off, see utfconv.UTF16Index.
+func (d *Document) UTF16Index(off int) (int, error) {
+	if off < 0 || off > d.Len() {
+		return -1, fmt.Errorf("lsp: offset %d out of range [0, %d]", off, d.Len())
+	}
+	k, start := d.find(off, byteKey)
+	n, _, units := d.lineAt(k)
+	return units + utfconv.UTF16Index(n.text, off-start), nil
+}
+
+// ByteIndexUTF16 returns the byte offset of UTF-16 code unit u of the
+// document content, see utfconv.ByteIndexUTF16.
+func (d *Document) ByteIndexUTF16(u int) (int, error) {
+	if u < 0 || u > d.LenUTF16() {
+		return -1, fmt.Errorf("lsp: UTF-16 offset %d out of range [0, %d]", u, d.LenUTF16())
+	}
+	k, units := d.find(u, unitKey)
+	n, start, _ := d.lineAt(k)
+	return start + utfconv.ByteIndexUTF16(n.text, u-units), nil
+}
+
+// OffsetPosition returns the Position of byte offset off.
+func (d *Document) OffsetPosition(off int) (Position, error) {
+	if off < 0 || off > d.Len() {
+		return Position{}, fmt.Errorf("lsp: offset %d out of range [0, %d]", off, d.Len())
+	}
+	k, start := d.find(off, byteKey)
+	n, _, _ := d.lineAt(k)
+	line := trimEOL(n.text)
+	if off-start > len(line) {
+		// offset within the line terminator
+		off = start + len(line)
+	}
+	char := charIndex(line[:off-start], d.enc)
+	return Position{Line: uint32(k), Character: uint32(char)}, nil
+}
+
+// PositionOffset returns the byte offset of Position p, see
+// Mapper.PositionOffset.
+func (d *Document) PositionOffset(p Position) (int, error) {
+	if int64(p.Line) >= int64(d.LineCount()) {
+		return -1, fmt.Errorf("lsp: line %d out of range [0, %d)", p.Line, d.LineCount())
+	}
+	n, start, _ := d.lineAt(int(p.Line))
+	return start + byteIndex(trimEOL(n.text), int(p.Character), d.enc), nil
+}
+
+// OffsetRange returns the Range of the byte offsets [start, end).
+func (d *Document) OffsetRange(start, end int) (Range, error) {
+	if start > end {
+		return Range{}, fmt.Errorf("lsp: invalid range: start %d > end %d", start, end)
+	}
+	s, err := d.OffsetPosition(start)
+	if err != nil {
+		return Range{}, err
+	}
+	e, err := d.OffsetPosition(end)
+	if err != nil {
+		return Range{}, err
+	}
+	return Range{Start: s, End: e}, nil
+}
+
+// RangeOffsets returns the byte offsets of Range r.
+func (d *Document) RangeOffsets(r Range) (start, end int, err error) {
+	if start, err = d.PositionOffset(r.Start); err != nil {
+		return -1, -1, err
+	}
+	if end, err = d.PositionOffset(r.End); err != nil {
+		return -1, -1, err
+	}
+	if start > end {
+		return -1, -1, fmt.Errorf("lsp: invalid range: start %d > end %d", start, end)
+	}
+	return start, end, nil
+}
+
+// Apply replaces the text in Range r with text.
+func (d *Document) Apply(r Range, text string) error {
+	start, end, err := d.RangeOffsets(r)
+	if err != nil {
+		return err
+	}
+	return d.ApplyOffsets(start, end, text)
+}
+
+// ApplyOffsets replaces the text between the byte offsets [start, end) with
+// text. Only the lines that contain start and end are rebuilt.
+func (d *Document) ApplyOffsets(start, end int, text string) error {
+	if start < 0 || end > d.Len() || start > end {
+		return fmt.Errorf("lsp: invalid edit range [%d, %d) of document length %d",
+			start, end, d.Len())
+	}
+	first, firstStart := d.find(start, byteKey)
+	firstLine, _, _ := d.lineAt(first)
+	prefix := firstLine.text[:start-firstStart]
+	if first > 0 {
+		// The previous line may end with a "\r" that joins a "\n" at the
+		// start of the edited text to form a single line terminator.
+		if prev, _, _ := d.lineAt(first - 1); strings.HasSuffix(prev.text, "\r") {
+			first--
+			prefix = prev.text + prefix
+		}
+	}
+	last, lastStart := d.find(end, byteKey)
+	lastLine, _, _ := d.lineAt(last)
+	suffix := lastLine.text[end-lastStart:]
+
+	left, rest := split(d.root, first)
+	_, right := split(rest, last-first+1)
+	mid := d.build(prefix + text + suffix)
+	if right != nil {
+		// The rebuilt lines end with the line terminator of the last line
+		// edited, drop the empty line that build adds after it.
+		mid, _ = split(mid, mid.count-1)
+	}
+	d.root = merge(merge(left, mid), right)
+	return nil
+}
@@ -0,0 +1,148 @@
+package lsp
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// checkDocument compares every conversion of d with a Mapper built from the
+// expected content.
+func checkDocument(t *testing.T, d *Document, content string) {
+	t.Helper()
+	if s := d.Content(); s != content {
+		t.Fatalf("Content() = %q; want: %q", s, content)
+	}
+	m, err := NewMapper(content, d.Encoding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.LineCount() != m.LineCount() {
+		t.Fatalf("%q: LineCount() = %d; want: %d", content, d.LineCount(), m.LineCount())
+	}
+	for k := 0; k < m.LineCount(); k++ {
+		_, want := m.line(k)
+		if line, err := d.Line(k); err != nil || line != want {
+			t.Fatalf("%q: Line(%d) = %q, %v; want: %q", content, k, line, err, want)
+		}
+	}
+	if n := utfconv.UTF16EncodedLenString(content); d.LenUTF16() != n {
+		t.Fatalf("%q: LenUTF16() = %d; want: %d", content, d.LenUTF16(), n)
+	}
+	for off := 0; off <= len(content); off++ {
+		p1, _ := m.OffsetPosition(off)
+		p2, err := d.OffsetPosition(off)
+		if err != nil || p1 != p2 {
+			t.Fatalf("%q: OffsetPosition(%d) = %v, %v; want: %v", content, off, p2, err, p1)
+		}
+		o1, _ := m.PositionOffset(p1)
+		o2, err := d.PositionOffset(p1)
+		if err != nil || o1 != o2 {
+			t.Fatalf("%q: PositionOffset(%v) = %d, %v; want: %d", content, p1, o2, err, o1)
+		}
+		u, err := d.UTF16Index(off)
+		if want := utfconv.UTF16Index(content, off); err != nil || u != want {
+			t.Fatalf("%q: UTF16Index(%d) = %d, %v; want: %d", content, off, u, err, want)
+		}
+	}
+	for u := 0; u <= d.LenUTF16(); u++ {
+		i, err := d.ByteIndexUTF16(u)
+		if want := utfconv.ByteIndexUTF16(content, u); err != nil || i != want {
+			t.Fatalf("%q: ByteIndexUTF16(%d) = %d, %v; want: %d", content, u, i, err, want)
+		}
+	}
+}
+
+func TestDocument(t *testing.T) {
+	for _, enc := range []PositionEncodingKind{UTF8, UTF16, UTF32} {
+		d, err := NewDocument(testDoc, enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkDocument(t, d, testDoc)
+		for _, k := range []int{-1, d.LineCount()} {
+			if _, err := d.Line(k); err == nil {
+				t.Errorf("Line(%d): expected error", k)
+			}
+		}
+	}
+}
+
+func TestDocumentApply(t *testing.T) {
+	tests := []struct {
+		in   string
+		r    Range
+		text string
+		out  string
+	}{
+		{"", Range{}, "abc", "abc"},
+		{"abc\ndef", Range{Position{0, 1}, Position{1, 1}}, "X", "aXef"},
+		{"abc\ndef", Range{Position{0, 3}, Position{0, 3}}, "\n\n", "abc\n\n\ndef"},
+		{"a\U00010400b\n", Range{Position{0, 1}, Position{0, 3}}, "", "ab\n"},
+		// join "\r" and "\n" into a single line terminator
+		{"a\rb\nc", Range{Position{1, 0}, Position{1, 1}}, "", "a\r\nc"},
+		{"a\r\nb", Range{Position{0, 1}, Position{0, 1}}, "\r", "a\r\r\nb"},
+	}
+	for _, x := range tests {
+		d, _ := NewDocument(x.in, UTF16)
+		if err := d.Apply(x.r, x.text); err != nil {
+			t.Fatal(err)
+		}
+		checkDocument(t, d, x.out)
+	}
+
+	d, _ := NewDocument("a\r\nb", UTF8)
+	if err := d.ApplyOffsets(2, 2, "x"); err != nil {
+		t.Fatal(err)
+	}
+	checkDocument(t, d, "a\rx\nb")
+	if err := d.ApplyOffsets(2, 3, ""); err != nil {
+		t.Fatal(err)
+	}
+	checkDocument(t, d, "a\r\nb")
+	if err := d.ApplyOffsets(3, 100, ""); err == nil {
+		t.Error("ApplyOffsets: expected error for invalid range")
+	}
+}
+
+func TestDocumentRandomEdits(t *testing.T) {
+	pieces := []string{
+		"a", "bc", "\n", "\r", "\r\n", "日本", "\U00010400", "\xff", "\xed\xa0\x80",
+	}
+	rr := rand.New(rand.NewSource(1))
+	randText := func() string {
+		var b strings.Builder
+		for n := rr.Intn(4); n > 0; n-- {
+			b.WriteString(pieces[rr.Intn(len(pieces))])
+		}
+		return b.String()
+	}
+	for _, enc := range []PositionEncodingKind{UTF8, UTF16, UTF32} {
+		content := ""
+		d, _ := NewDocument(content, enc)
+		for i := 0; i < 500; i++ {
+			start := rr.Intn(len(content) + 1)
+			end := start + rr.Intn(len(content)-start+1)
+			text := randText()
+			if err := d.ApplyOffsets(start, end, text); err != nil {
+				t.Fatal(err)
+			}
+			content = content[:start] + text + content[end:]
+			checkDocument(t, d, content)
+		}
+	}
+}
+
+func BenchmarkDocumentApply(b *testing.B) {
+	content := strings.Repeat("func main() { println(\"日本語\") }\n", 100000)
+	d, _ := NewDocument(content, UTF16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		off := (i * 7919) % (d.Len() - 1)
+		if err := d.ApplyOffsets(off, off+1, "x\n"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
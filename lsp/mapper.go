@@ -0,0 +1,253 @@
+// Package lsp converts between byte offsets and Language Server Protocol
+// positions in any of the position encodings defined by the protocol.
+package lsp
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// A PositionEncodingKind describes how the Character field of a Position is
+// counted, it corresponds to the LSP PositionEncodingKind type.
+type PositionEncodingKind string
+
+const (
+	// UTF8 counts characters in bytes.
+	UTF8 PositionEncodingKind = "utf-8"
+	// UTF16 counts characters in UTF-16 code units, it is the LSP default
+	// and must be supported by all servers.
+	UTF16 PositionEncodingKind = "utf-16"
+	// UTF32 counts characters in Unicode code points.
+	UTF32 PositionEncodingKind = "utf-32"
+)
+
+// NegotiateEncoding returns the first encoding in preferred that the client
+// supports. Clients that do not advertise any position encodings only support
+// UTF16, which is also returned if there is no common encoding. If preferred
+// is empty the first supported encoding advertised by the client is used.
+func NegotiateEncoding(client []PositionEncodingKind, preferred ...PositionEncodingKind) PositionEncodingKind {
+	if len(client) == 0 {
+		return UTF16
+	}
+	if len(preferred) == 0 {
+		preferred = []PositionEncodingKind{UTF8, UTF16, UTF32}
+		for _, c := range client {
+			for _, p := range preferred {
+				if c == p {
+					return c
+				}
+			}
+		}
+		return UTF16
+	}
+	for _, p := range preferred {
+		for _, c := range client {
+			if c == p {
+				return p
+			}
+		}
+	}
+	return UTF16
+}
+
+// A Position is a zero-based line and character offset in a document.
+type Position struct {
+	Line      uint32 `json:"line"`
+	Character uint32 `json:"character"`
+}
+
+// A Range is a half-open range of positions in a document.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// A Mapper converts between byte offsets, Positions and Ranges of a
+// document. Lines are terminated by "\n", "\r\n" or "\r" as required by the
+// protocol. A Mapper is safe for concurrent use.
+type Mapper struct {
+	content string
+	enc     PositionEncodingKind
+	lines   []int // byte offset of the start of each line
+}
+
+// NewMapper returns a Mapper for content that counts characters using enc.
+// An empty enc is treated as UTF16.
+func NewMapper(content string, enc PositionEncodingKind) (*Mapper, error) {
+	enc, err := checkEncoding(enc)
+	if err != nil {
+		return nil, err
+	}
+	return &Mapper{
+		content: content,
+		enc:     enc,
+		lines:   lineStarts(content),
+	}, nil
+}
+
+func checkEncoding(enc PositionEncodingKind) (PositionEncodingKind, error) {
+	switch enc {
+	case UTF8, UTF16, UTF32:
+		return enc, nil
+	case "":
+		return UTF16, nil
+	}
+	return "", fmt.Errorf("lsp: unsupported position encoding: %q", enc)
+}
+
+func lineStarts(s string) []int {
+	lines := []int{0}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+			lines = append(lines, i+1)
+		case '\n':
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// Content returns the document content.
+func (m *Mapper) Content() string { return m.content }
+
+// Encoding returns the position encoding used by m.
+func (m *Mapper) Encoding() PositionEncodingKind { return m.enc }
+
+// LineCount returns the number of lines in the document. A document that
+// ends with a line terminator has an empty final line.
+func (m *Mapper) LineCount() int { return len(m.lines) }
+
+// line returns the content of line n excluding the line terminator.
+func (m *Mapper) line(n int) (start int, line string) {
+	start = m.lines[n]
+	end := len(m.content)
+	if n+1 < len(m.lines) {
+		end = m.lines[n+1]
+	}
+	return start, trimEOL(m.content[start:end])
+}
+
+// trimEOL removes the line terminator from line.
+func trimEOL(line string) string {
+	switch n := len(line); {
+	case n >= 2 && line[n-2] == '\r' && line[n-1] == '\n':
+		return line[:n-2]
+	case n >= 1 && (line[n-1] == '\n' || line[n-1] == '\r'):
+		return line[:n-1]
+	}
+	return line
+}
+
+// charIndex returns the number of characters in line, counted using enc.
+func charIndex(line string, enc PositionEncodingKind) int {
+	switch enc {
+	case UTF8:
+		return len(line)
+	case UTF32:
+		return utf8.RuneCountInString(line)
+	}
+	return utfconv.UTF16EncodedLenString(line)
+}
+
+// byteIndex returns the byte index of character char of line, counted using
+// enc. The result is clamped to the length of line and rounded down to the
+// start of the character that contains it.
+func byteIndex(line string, char int, enc PositionEncodingKind) int {
+	var i int
+	switch enc {
+	case UTF8:
+		i = char
+		if i > len(line) {
+			i = len(line)
+		}
+		for i > 0 && i < len(line) && !utf8.RuneStart(line[i]) {
+			i--
+		}
+	case UTF32:
+		for i < len(line) && char > 0 {
+			_, size := utf8.DecodeRuneInString(line[i:])
+			i += size
+			char--
+		}
+	default:
+		if i = utfconv.ByteIndexUTF16(line, char); i < 0 {
+			i = len(line)
+		}
+	}
+	return i
+}
+
+// OffsetPosition returns the Position of byte offset off.
+func (m *Mapper) OffsetPosition(off int) (Position, error) {
+	if off < 0 || off > len(m.content) {
+		return Position{}, fmt.Errorf("lsp: offset %d out of range [0, %d]", off, len(m.content))
+	}
+	// find the last line starting at or before off
+	lo, hi := 0, len(m.lines)
+	for lo < hi {
+		h := int(uint(lo+hi) >> 1)
+		if m.lines[h] <= off {
+			lo = h + 1
+		} else {
+			hi = h
+		}
+	}
+	n := lo - 1
+	start, line := m.line(n)
+	if off-start > len(line) {
+		// offset within the line terminator
+		off = start + len(line)
+	}
+	char := charIndex(line[:off-start], m.enc)
+	return Position{Line: uint32(n), Character: uint32(char)}, nil
+}
+
+// PositionOffset returns the byte offset of Position p. As specified by the
+// protocol, a Character greater than the length of the line defaults back to
+// the end of the line. A Character that falls within a character, such as
+// between the two code units of a UTF-16 surrogate pair, is rounded down to
+// the start of that character.
+func (m *Mapper) PositionOffset(p Position) (int, error) {
+	if int64(p.Line) >= int64(len(m.lines)) {
+		return -1, fmt.Errorf("lsp: line %d out of range [0, %d)", p.Line, len(m.lines))
+	}
+	start, line := m.line(int(p.Line))
+	i := byteIndex(line, int(p.Character), m.enc)
+	return start + i, nil
+}
+
+// OffsetRange returns the Range of the byte offsets [start, end).
+func (m *Mapper) OffsetRange(start, end int) (Range, error) {
+	if start > end {
+		return Range{}, fmt.Errorf("lsp: invalid range: start %d > end %d", start, end)
+	}
+	s, err := m.OffsetPosition(start)
+	if err != nil {
+		return Range{}, err
+	}
+	e, err := m.OffsetPosition(end)
+	if err != nil {
+		return Range{}, err
+	}
+	return Range{Start: s, End: e}, nil
+}
+
+// RangeOffsets returns the byte offsets of Range r.
+func (m *Mapper) RangeOffsets(r Range) (start, end int, err error) {
+	if start, err = m.PositionOffset(r.Start); err != nil {
+		return -1, -1, err
+	}
+	if end, err = m.PositionOffset(r.End); err != nil {
+		return -1, -1, err
+	}
+	if start > end {
+		return -1, -1, fmt.Errorf("lsp: invalid range: start %d > end %d", start, end)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,151 @@
+package lsp
+
+import "testing"
+
+// "a𐐀b" where U+10400 is 4 bytes, 2 UTF-16 code units and 1 code point.
+const testDoc = "abc\n" +
+	"a\U00010400b\r\n" +
+	"日本\r" +
+	"\r" +
+	"end"
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		client    []PositionEncodingKind
+		preferred []PositionEncodingKind
+		want      PositionEncodingKind
+	}{
+		{nil, nil, UTF16},
+		{nil, []PositionEncodingKind{UTF8}, UTF16},
+		{[]PositionEncodingKind{UTF32, UTF8}, nil, UTF32},
+		{[]PositionEncodingKind{UTF32, UTF8}, []PositionEncodingKind{UTF8}, UTF8},
+		{[]PositionEncodingKind{UTF32}, []PositionEncodingKind{UTF8}, UTF16},
+		{[]PositionEncodingKind{"utf-7"}, nil, UTF16},
+	}
+	for _, x := range tests {
+		if got := NegotiateEncoding(x.client, x.preferred...); got != x.want {
+			t.Errorf("NegotiateEncoding(%q, %q) = %q; want: %q", x.client, x.preferred, got, x.want)
+		}
+	}
+}
+
+func TestMapper(t *testing.T) {
+	type test struct {
+		off int
+		pos Position
+	}
+	tests := map[PositionEncodingKind][]test{
+		UTF8: {
+			{0, Position{0, 0}},
+			{3, Position{0, 3}},
+			{4, Position{1, 0}},
+			{5, Position{1, 1}},
+			{9, Position{1, 5}},
+			{10, Position{1, 6}},
+			{12, Position{2, 0}},
+			{15, Position{2, 3}},
+			{18, Position{2, 6}},
+			{19, Position{3, 0}},
+			{20, Position{4, 0}},
+			{23, Position{4, 3}},
+		},
+		UTF16: {
+			{0, Position{0, 0}},
+			{4, Position{1, 0}},
+			{5, Position{1, 1}},
+			{9, Position{1, 3}},
+			{10, Position{1, 4}},
+			{12, Position{2, 0}},
+			{15, Position{2, 1}},
+			{18, Position{2, 2}},
+			{19, Position{3, 0}},
+			{23, Position{4, 3}},
+		},
+		UTF32: {
+			{0, Position{0, 0}},
+			{5, Position{1, 1}},
+			{9, Position{1, 2}},
+			{10, Position{1, 3}},
+			{15, Position{2, 1}},
+			{18, Position{2, 2}},
+			{23, Position{4, 3}},
+		},
+	}
+	for enc, tests := range tests {
+		m, err := NewMapper(testDoc, enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := m.LineCount(); n != 5 {
+			t.Fatalf("%s: LineCount() = %d; want: 5", enc, n)
+		}
+		for _, x := range tests {
+			pos, err := m.OffsetPosition(x.off)
+			if err != nil || pos != x.pos {
+				t.Errorf("%s: OffsetPosition(%d) = %v, %v; want: %v", enc, x.off, pos, err, x.pos)
+			}
+			off, err := m.PositionOffset(x.pos)
+			if err != nil || off != x.off {
+				t.Errorf("%s: PositionOffset(%v) = %d, %v; want: %d", enc, x.pos, off, err, x.off)
+			}
+		}
+	}
+}
+
+func TestMapperClamp(t *testing.T) {
+	tests := []struct {
+		enc PositionEncodingKind
+		pos Position
+		off int
+	}{
+		// past the end of the line
+		{UTF16, Position{0, 100}, 3},
+		{UTF8, Position{1, 100}, 10},
+		{UTF32, Position{2, 100}, 18},
+		// within a character
+		{UTF16, Position{1, 2}, 5},
+		{UTF8, Position{1, 3}, 5},
+		{UTF8, Position{2, 1}, 12},
+	}
+	for _, x := range tests {
+		m, _ := NewMapper(testDoc, x.enc)
+		off, err := m.PositionOffset(x.pos)
+		if err != nil || off != x.off {
+			t.Errorf("%s: PositionOffset(%v) = %d, %v; want: %d", x.enc, x.pos, off, err, x.off)
+		}
+	}
+}
+
+func TestMapperErrors(t *testing.T) {
+	if _, err := NewMapper(testDoc, "utf-7"); err == nil {
+		t.Error("NewMapper: expected error for unsupported encoding")
+	}
+	m, _ := NewMapper(testDoc, UTF16)
+	for _, off := range []int{-1, len(testDoc) + 1} {
+		if _, err := m.OffsetPosition(off); err == nil {
+			t.Errorf("OffsetPosition(%d): expected error", off)
+		}
+	}
+	if _, err := m.PositionOffset(Position{5, 0}); err == nil {
+		t.Error("PositionOffset: expected error for line out of range")
+	}
+	if _, _, err := m.RangeOffsets(Range{Position{1, 0}, Position{0, 0}}); err == nil {
+		t.Error("RangeOffsets: expected error for inverted range")
+	}
+}
+
+func TestMapperRange(t *testing.T) {
+	m, _ := NewMapper(testDoc, UTF16)
+	r, err := m.OffsetRange(5, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Range{Position{1, 1}, Position{2, 1}}
+	if r != want {
+		t.Errorf("OffsetRange(5, 15) = %v; want: %v", r, want)
+	}
+	start, end, err := m.RangeOffsets(r)
+	if err != nil || start != 5 || end != 15 {
+		t.Errorf("RangeOffsets(%v) = %d, %d, %v; want: 5, 15", r, start, end, err)
+	}
+}
@@ -0,0 +1,177 @@
+package utfconv
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// EncodeMultiSZ returns the REG_MULTI_SZ encoding of a: each string followed
+// by a NUL, and a terminating NUL. Empty strings, which would end the list,
+// are omitted and a string containing a NUL is truncated at the NUL. A list
+// with no strings is encoded as two NULs.
+func EncodeMultiSZ(a []string) []uint16 {
+	n := 1
+	for _, s := range a {
+		if i := strings.IndexByte(s, 0); i != -1 {
+			s = s[:i]
+		}
+		if s != "" {
+			n += UTF16EncodedLenString(s) + 1
+		}
+	}
+	if n == 1 {
+		n = 2
+	}
+	var b UTF16Builder
+	b.Grow(n)
+	for _, s := range a {
+		if i := strings.IndexByte(s, 0); i != -1 {
+			s = s[:i]
+		}
+		if s != "" {
+			b.WriteString(s)
+			b.WriteByte(0)
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteByte(0)
+	}
+	b.WriteByte(0)
+	return b.UTF16()
+}
+
+// DecodeMultiSZ returns the strings of the REG_MULTI_SZ value s. The list
+// ends at the first empty string or at the end of s, so a missing NUL
+// terminator is tolerated. It returns nil if s contains no strings.
+func DecodeMultiSZ(s []uint16) []string {
+	var a []string
+	for len(s) > 0 {
+		i := 0
+		for i < len(s) && s[i] != 0 {
+			i++
+		}
+		if i == 0 {
+			break
+		}
+		a = append(a, UTF16ToString(s[:i]))
+		if i == len(s) {
+			break
+		}
+		s = s[i+1:]
+	}
+	return a
+}
+
+// EncodeMultiSZBytes is like EncodeMultiSZ but returns the little-endian
+// bytes of the value, as stored in the registry.
+func EncodeMultiSZBytes(a []string) []byte {
+	return utf16ToBytesLE(EncodeMultiSZ(a))
+}
+
+// DecodeMultiSZBytes is like DecodeMultiSZ but decodes the little-endian
+// bytes of the value. A trailing odd byte is ignored.
+func DecodeMultiSZBytes(p []byte) []string {
+	return DecodeMultiSZ(bytesLEToUTF16(p))
+}
+
+// envName returns the name of the environment variable "name=value". The
+// name of the per-drive current directory variables, such as "=C:=C:\dir",
+// starts with "=".
+func envName(kv string) string {
+	if kv == "" {
+		return ""
+	}
+	if i := strings.IndexByte(kv[1:], '='); i != -1 {
+		return kv[:i+1]
+	}
+	return kv
+}
+
+// EncodeEnvironmentBlock returns the Windows environment block of env, a
+// list of "name=value" strings as returned by os.Environ, for use with
+// CreateProcess. Variables are sorted by name, ignoring case, as Windows
+// requires, and if a name occurs more than once the last value is used.
+// Empty strings are omitted and a string containing a NUL is truncated at
+// the NUL. An empty env is encoded as two NULs.
+func EncodeEnvironmentBlock(env []string) []uint16 {
+	seen := make(map[string]int, len(env))
+	a := make([]string, 0, len(env))
+	keys := make([]string, 0, len(env))
+	for _, kv := range env {
+		if i := strings.IndexByte(kv, 0); i != -1 {
+			kv = kv[:i]
+		}
+		if kv == "" {
+			continue
+		}
+		k := strings.Map(unicode.ToUpper, envName(kv))
+		if i, ok := seen[k]; ok {
+			a[i] = kv
+			continue
+		}
+		seen[k] = len(a)
+		a = append(a, kv)
+		keys = append(keys, k)
+	}
+	sort.Sort(envSorter{a, keys})
+	if len(a) == 0 {
+		return []uint16{0, 0}
+	}
+	return EncodeMultiSZ(a)
+}
+
+// envSorter sorts environment variables by their upper case name in
+// UTF-16 code unit order.
+type envSorter struct {
+	env, keys []string
+}
+
+func (s envSorter) Len() int { return len(s.env) }
+
+func (s envSorter) Less(i, j int) bool { return CompareUTF16Order(s.keys[i], s.keys[j]) < 0 }
+
+func (s envSorter) Swap(i, j int) {
+	s.env[i], s.env[j] = s.env[j], s.env[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// DecodeEnvironmentBlock returns the "name=value" strings of the Windows
+// environment block s, in order. The block ends at the first empty string
+// or at the end of s.
+func DecodeEnvironmentBlock(s []uint16) []string {
+	return DecodeMultiSZ(s)
+}
+
+// EncodeEnvironmentBlockBytes is like EncodeEnvironmentBlock but returns
+// the little-endian bytes of the block.
+func EncodeEnvironmentBlockBytes(env []string) []byte {
+	return utf16ToBytesLE(EncodeEnvironmentBlock(env))
+}
+
+// DecodeEnvironmentBlockBytes is like DecodeEnvironmentBlock but decodes
+// the little-endian bytes of the block, such as read from the memory of a
+// process. A trailing odd byte is ignored.
+func DecodeEnvironmentBlockBytes(p []byte) []string {
+	return DecodeMultiSZ(bytesLEToUTF16(p))
+}
+
+func utf16ToBytesLE(s []uint16) []byte {
+	return appendUTF16LE(make([]byte, 0, 2*len(s)), s)
+}
+
+func appendUTF16LE(b []byte, s []uint16) []byte {
+	for _, c := range s {
+		b = binary.LittleEndian.AppendUint16(b, c)
+	}
+	return b
+}
+
+func bytesLEToUTF16(p []byte) []uint16 {
+	s := make([]uint16, len(p)/2)
+	for i := range s {
+		s[i] = binary.LittleEndian.Uint16(p[2*i:])
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestMultiSZ(t *testing.T) {
+	tests := []struct {
+		in  []string
+		enc string
+		out []string
+	}{
+		{nil, "\x00\x00", nil},
+		{[]string{}, "\x00\x00", nil},
+		{[]string{""}, "\x00\x00", nil},
+		{[]string{"", "\x00a"}, "\x00\x00", nil},
+		{[]string{"a"}, "a\x00\x00", []string{"a"}},
+		{[]string{"abc", "日本", "\U0001F600"}, "abc\x00日本\x00\U0001F600\x00\x00", []string{"abc", "日本", "\U0001F600"}},
+		{[]string{"a", "", "b"}, "a\x00b\x00\x00", []string{"a", "b"}},
+		{[]string{"a\x00b", "c"}, "a\x00c\x00\x00", []string{"a", "c"}},
+	}
+	for _, x := range tests {
+		enc := utf16.Encode([]rune(x.enc))
+		if u := EncodeMultiSZ(x.in); !reflect.DeepEqual(u, enc) {
+			t.Errorf("EncodeMultiSZ(%q) = %v; want: %v", x.in, u, enc)
+		}
+		if a := DecodeMultiSZ(enc); !reflect.DeepEqual(a, x.out) {
+			t.Errorf("DecodeMultiSZ(%v) = %q; want: %q", enc, a, x.out)
+		}
+		p := EncodeMultiSZBytes(x.in)
+		if !reflect.DeepEqual(p, utf16ToBytesLE(enc)) {
+			t.Errorf("EncodeMultiSZBytes(%q) = %v; want: %v", x.in, p, utf16ToBytesLE(enc))
+		}
+		if a := DecodeMultiSZBytes(p); !reflect.DeepEqual(a, x.out) {
+			t.Errorf("DecodeMultiSZBytes(%v) = %q; want: %q", p, a, x.out)
+		}
+	}
+}
+
+func TestDecodeMultiSZ(t *testing.T) {
+	tests := []struct {
+		in  []uint16
+		out []string
+	}{
+		{nil, nil},
+		{[]uint16{0, 0}, nil},
+		{[]uint16{'a'}, []string{"a"}},    // unterminated
+		{[]uint16{'a', 0}, []string{"a"}}, // missing final NUL
+		{[]uint16{'a', 0, 0, 'b', 0, 0}, []string{"a"}},
+		{[]uint16{0xD800, 0, 'b', 0, 0}, []string{"\uFFFD", "b"}},
+	}
+	for _, x := range tests {
+		if a := DecodeMultiSZ(x.in); !reflect.DeepEqual(a, x.out) {
+			t.Errorf("DecodeMultiSZ(%v) = %q; want: %q", x.in, a, x.out)
+		}
+	}
+	// little-endian with an odd trailing byte
+	p := []byte{'a', 0, 0, 0, 'b', 0, 0, 0, 0, 0, 'x'}
+	if a, exp := DecodeMultiSZBytes(p), []string{"a", "b"}; !reflect.DeepEqual(a, exp) {
+		t.Errorf("DecodeMultiSZBytes(%v) = %q; want: %q", p, a, exp)
+	}
+}
+
+func TestEnvironmentBlock(t *testing.T) {
+	tests := []struct {
+		env []string
+		enc string
+	}{
+		{nil, "\x00\x00"},
+		{[]string{""}, "\x00\x00"},
+		{[]string{"A=1"}, "A=1\x00\x00"},
+		{
+			[]string{"path=C:\\bin", "=C:=C:\\dir", "TEMP=x", "Path=D:\\", "a=", "_X=1", "=D:=D:\\"},
+			"=C:=C:\\dir\x00=D:=D:\\\x00a=\x00Path=D:\\\x00TEMP=x\x00_X=1\x00\x00",
+		},
+		{[]string{"b=1", "B=2", "a=\x00x"}, "a=\x00B=2\x00\x00"},
+		{[]string{"ä=1", "z=2", "Ä=3"}, "z=2\x00Ä=3\x00\x00"},
+	}
+	for _, x := range tests {
+		enc := utf16.Encode([]rune(x.enc))
+		if u := EncodeEnvironmentBlock(x.env); !reflect.DeepEqual(u, enc) {
+			t.Errorf("EncodeEnvironmentBlock(%q) = %q; want: %q", x.env, UTF16ToString(u), x.enc)
+		}
+		if p := EncodeEnvironmentBlockBytes(x.env); !reflect.DeepEqual(p, utf16ToBytesLE(enc)) {
+			t.Errorf("EncodeEnvironmentBlockBytes(%q) = %v; want: %v", x.env, p, utf16ToBytesLE(enc))
+		}
+		want := DecodeMultiSZ(enc)
+		if a := DecodeEnvironmentBlock(enc); !reflect.DeepEqual(a, want) {
+			t.Errorf("DecodeEnvironmentBlock(%q) = %q; want: %q", x.enc, a, want)
+		}
+		if a := DecodeEnvironmentBlockBytes(utf16ToBytesLE(enc)); !reflect.DeepEqual(a, want) {
+			t.Errorf("DecodeEnvironmentBlockBytes(%q) = %q; want: %q", x.enc, a, want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+//go:build ignore
+
+// This program generates tables.go from the normalization data in
+// internal/ucd. Run it with go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charlievieth/utfconv/internal/ucd"
+)
+
+var (
+	ucdDir = flag.String("ucd", "../internal/ucd", "directory containing the UCD files")
+	output = flag.String("output", "tables.go", "output file")
+)
+
+// Keep in sync with norm.go.
+const (
+	nfdNo = 1 << iota
+	nfkdNo
+	nfcNo
+	nfcMaybe
+	nfkcNo
+	nfkcMaybe
+)
+
+var flagNames = map[string]uint8{
+	"NFD_QC=N":  nfdNo,
+	"NFKD_QC=N": nfkdNo,
+	"NFC_QC=N":  nfcNo,
+	"NFC_QC=M":  nfcMaybe,
+	"NFKC_QC=N": nfkcNo,
+	"NFKC_QC=M": nfkcMaybe,
+}
+
+type mapping struct {
+	compat bool
+	runes  []rune
+}
+
+var (
+	mappings = map[rune]mapping{}
+	ccc      = map[rune]uint8{}
+	flags    = map[rune]uint8{}
+	excluded = map[rune]bool{}
+)
+
+func parse(name string, f func([]string) error) {
+	if err := ucd.Parse(filepath.Join(*ucdDir, name), f); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func forRange(s string, f func(r rune)) error {
+	lo, hi, err := ucd.Range(s)
+	if err != nil {
+		return err
+	}
+	for r := lo; r <= hi; r++ {
+		f(r)
+	}
+	return nil
+}
+
+// decompose appends the full decomposition of r to a.
+func decompose(a []rune, r rune, compat bool) []rune {
+	m, ok := mappings[r]
+	if !ok || (m.compat && !compat) {
+		return append(a, r)
+	}
+	for _, c := range m.runes {
+		a = decompose(a, c, compat)
+	}
+	return a
+}
+
+func quote(r []rune) string { return strconv.QuoteToASCII(string(r)) }
+
+func main() {
+	flag.Parse()
+	parse("Decompositions.txt", func(f []string) error {
+		r, err := ucd.Rune(f[0])
+		if err != nil {
+			return err
+		}
+		var m mapping
+		s := f[1]
+		if strings.HasPrefix(s, "<") {
+			m.compat = true
+			s = s[strings.IndexByte(s, '>')+1:]
+		}
+		if m.runes, err = ucd.Runes(s); err != nil {
+			return err
+		}
+		mappings[r] = m
+		return nil
+	})
+	parse("DerivedCombiningClass.txt", func(f []string) error {
+		c, err := strconv.ParseUint(f[1], 10, 8)
+		if err != nil {
+			return err
+		}
+		return forRange(f[0], func(r rune) { ccc[r] = uint8(c) })
+	})
+	parse("DerivedNormalizationProps.txt", func(f []string) error {
+		if f[1] == "Full_Composition_Exclusion" {
+			return forRange(f[0], func(r rune) { excluded[r] = true })
+		}
+		if len(f) < 3 {
+			return fmt.Errorf("missing value: %q", f)
+		}
+		flag, ok := flagNames[f[1]+"="+f[2]]
+		if !ok {
+			return fmt.Errorf("unknown property: %q", f)
+		}
+		return forRange(f[0], func(r rune) { flags[r] |= flag })
+	})
+
+	// properties of each code point as ranges
+	var cps []rune
+	seen := map[rune]bool{}
+	for r := range ccc {
+		cps, seen[r] = append(cps, r), true
+	}
+	for r := range flags {
+		if !seen[r] {
+			cps = append(cps, r)
+		}
+	}
+	sort.Slice(cps, func(i, j int) bool { return cps[i] < cps[j] })
+	type prop struct {
+		lo, hi     rune
+		ccc, flags uint8
+	}
+	var props []prop
+	for _, r := range cps {
+		if n := len(props); n > 0 {
+			p := &props[n-1]
+			if p.hi+1 == r && p.ccc == ccc[r] && p.flags == flags[r] {
+				p.hi = r
+				continue
+			}
+		}
+		props = append(props, prop{r, r, ccc[r], flags[r]})
+	}
+
+	var keys []rune
+	for r := range mappings {
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package norm\n\n")
+	buf.WriteString("// UnicodeVersion is the version of Unicode the tables are generated from.\n")
+	fmt.Fprintf(&buf, "const UnicodeVersion = %q\n\n", ucd.Version)
+
+	buf.WriteString("// props are the canonical combining classes and quick check values of\n")
+	buf.WriteString("// the code points for which either is not the default.\n")
+	buf.WriteString("var props = [...]propRange{\n")
+	for _, p := range props {
+		fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X, %d, 0x%02X},\n", p.lo, p.hi, p.ccc, p.flags)
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// canonDecomps are the full canonical decompositions.\n")
+	buf.WriteString("var canonDecomps = map[rune]string{\n")
+	for _, r := range keys {
+		if !mappings[r].compat {
+			fmt.Fprintf(&buf, "\t0x%04X: %s,\n", r, quote(decompose(nil, r, false)))
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// compatDecomps are the full compatibility decompositions that differ\n")
+	buf.WriteString("// from the canonical decomposition.\n")
+	buf.WriteString("var compatDecomps = map[rune]string{\n")
+	for _, r := range keys {
+		d := decompose(nil, r, true)
+		if string(d) != string(decompose(nil, r, false)) {
+			fmt.Fprintf(&buf, "\t0x%04X: %s,\n", r, quote(d))
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// compositions maps pairs of code points to their primary composite.\n")
+	buf.WriteString("var compositions = map[[2]rune]rune{\n")
+	for _, r := range keys {
+		m := mappings[r]
+		if m.compat || len(m.runes) != 2 || excluded[r] {
+			continue
+		}
+		fmt.Fprintf(&buf, "\t{0x%04X, 0x%04X}: 0x%04X,\n", m.runes[0], m.runes[1], r)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
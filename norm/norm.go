@@ -0,0 +1,349 @@
+// Package norm implements the Unicode normalization forms of Unicode Standard
+// Annex #15 for UTF-16 encoded text.
+//
+// Unpaired surrogates are not valid Unicode but are common in UTF-16 text.
+// They are passed through unchanged, as starters that neither decompose nor
+// compose.
+package norm
+
+import (
+	"unicode/utf16"
+
+	"github.com/charlievieth/utfconv"
+)
+
+//go:generate go run gen.go
+
+// A Form is a Unicode normalization form.
+type Form int
+
+const (
+	NFC  Form = iota // canonical decomposition followed by canonical composition
+	NFD              // canonical decomposition
+	NFKC             // compatibility decomposition followed by canonical composition
+	NFKD             // compatibility decomposition
+)
+
+// String returns the name of f.
+func (f Form) String() string {
+	switch f {
+	case NFC:
+		return "NFC"
+	case NFD:
+		return "NFD"
+	case NFKC:
+		return "NFKC"
+	case NFKD:
+		return "NFKD"
+	}
+	return "Form(invalid)"
+}
+
+// QuickCheckResult is the result of a normalization quick check.
+type QuickCheckResult int
+
+const (
+	Yes   QuickCheckResult = iota // the text is normalized
+	No                            // the text is not normalized
+	Maybe                         // the text may be normalized
+)
+
+// String returns the name of r.
+func (r QuickCheckResult) String() string {
+	switch r {
+	case Yes:
+		return "Yes"
+	case No:
+		return "No"
+	case Maybe:
+		return "Maybe"
+	}
+	return "QuickCheckResult(invalid)"
+}
+
+// Quick check flags of propRange, keep in sync with gen.go.
+const (
+	nfdNo = 1 << iota
+	nfkdNo
+	nfcNo
+	nfcMaybe
+	nfkcNo
+	nfkcMaybe
+)
+
+type propRange struct {
+	lo, hi rune
+	ccc    uint8 // canonical combining class
+	flags  uint8 // quick check values
+}
+
+// lookup returns the canonical combining class and quick check flags of r.
+func lookup(r rune) (ccc, flags uint8) {
+	if r < props[0].lo {
+		return 0, 0
+	}
+	lo, hi := 0, len(props)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		switch {
+		case r < props[m].lo:
+			hi = m
+		case r > props[m].hi:
+			lo = m + 1
+		default:
+			return props[m].ccc, props[m].flags
+		}
+	}
+	return 0, 0
+}
+
+func combiningClass(r rune) uint8 {
+	ccc, _ := lookup(r)
+	return ccc
+}
+
+// masks returns the quick check flags of code points that are not allowed
+// and may not be allowed in form f.
+func (f Form) masks() (no, maybe uint8) {
+	switch f {
+	case NFC:
+		return nfcNo, nfcMaybe
+	case NFD:
+		return nfdNo, 0
+	case NFKC:
+		return nfkcNo, nfkcMaybe
+	case NFKD:
+		return nfkdNo, 0
+	}
+	panic("norm: invalid form")
+}
+
+func (f Form) compat() bool { return f == NFKC || f == NFKD }
+
+func (f Form) composed() bool { return f == NFC || f == NFKC }
+
+// quickSpan returns the quick check result of s and, if it is not Yes, the
+// offset of the last starter before the first code point that is not
+// allowed. The text before that offset is normalized and is not affected
+// by the text that follows it.
+func (f Form) quickSpan(s []uint16) (QuickCheckResult, int) {
+	no, maybe := f.masks()
+	res := Yes
+	var last uint8 // combining class of the previous code point
+	start := 0     // offset of the last starter
+	for i := 0; i < len(s); {
+		if rune(s[i]) < props[0].lo {
+			if res == Yes {
+				start = i
+			}
+			last = 0
+			i++
+			continue
+		}
+		// an unpaired surrogate decodes as U+FFFD, which is a starter
+		// with the same properties
+		r, n := utfconv.DecodeRuneUTF16(s[i:])
+		ccc, flags := lookup(r)
+		if flags&no != 0 || (ccc != 0 && last > ccc) {
+			return No, start
+		}
+		if flags&maybe != 0 {
+			res = Maybe
+		} else if ccc == 0 && res == Yes {
+			start = i
+		}
+		last = ccc
+		i += n
+	}
+	return res, start
+}
+
+// QuickCheck returns whether s is in form f using the quick check algorithm
+// of UAX #15. A result of Maybe means that s must be normalized to tell.
+func (f Form) QuickCheck(s []uint16) QuickCheckResult {
+	res, _ := f.quickSpan(s)
+	return res
+}
+
+// IsNormal reports whether s is in form f.
+func (f Form) IsNormal(s []uint16) bool {
+	res, start := f.quickSpan(s)
+	if res != Maybe {
+		return res == Yes
+	}
+	t := f.normalize(nil, s[start:])
+	if len(t) != len(s)-start {
+		return false
+	}
+	for i, c := range t {
+		if s[start+i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize returns s in form f. If s is already in form f it is returned
+// as is, otherwise a new slice is allocated.
+func (f Form) Normalize(s []uint16) []uint16 {
+	res, start := f.quickSpan(s)
+	if res == Yes {
+		return s
+	}
+	out := make([]uint16, start, len(s)+len(s)/2)
+	copy(out, s[:start])
+	return f.normalize(out, s[start:])
+}
+
+// Append appends s in form f to dst and returns the extended slice. The
+// text of dst is not normalized again, s should start with a starter for
+// the result to be normalized.
+func (f Form) Append(dst, s []uint16) []uint16 {
+	res, start := f.quickSpan(s)
+	dst = append(dst, s[:start]...)
+	if res == Yes {
+		return append(dst, s[start:]...)
+	}
+	return f.normalize(dst, s[start:])
+}
+
+// NormalizeString returns the UTF-16 encoding of the string s in form f.
+// Invalid UTF-8 is converted to U+FFFD.
+func (f Form) NormalizeString(s string) []uint16 {
+	u := make([]uint16, 0, len(s))
+	for _, r := range s {
+		u = utfconv.AppendRuneUTF16(u, r)
+	}
+	return f.Normalize(u)
+}
+
+// normalize appends s in form f to dst.
+func (f Form) normalize(dst, s []uint16) []uint16 {
+	var buf [64]rune
+	a := buf[:0]
+	compat := f.compat()
+	for i := 0; i < len(s); {
+		r, n := utfconv.DecodeRuneUTF16(s[i:])
+		if n == 1 && utf16.IsSurrogate(rune(s[i])) {
+			r = rune(s[i]) // pass unpaired surrogates through
+		}
+		a = decompose(a, r, compat)
+		i += n
+	}
+	reorder(a)
+	if f.composed() {
+		a = compose(a)
+	}
+	for _, r := range a {
+		if utf16.IsSurrogate(r) {
+			dst = append(dst, uint16(r))
+		} else {
+			dst = utfconv.AppendRuneUTF16(dst, r)
+		}
+	}
+	return dst
+}
+
+// Hangul syllable constants from section 3.12 of the Unicode Standard.
+const (
+	sBase  = 0xAC00
+	lBase  = 0x1100
+	vBase  = 0x1161
+	tBase  = 0x11A7
+	lCount = 19
+	vCount = 21
+	tCount = 28
+	nCount = vCount * tCount
+	sCount = lCount * nCount
+)
+
+// decompose appends the full canonical or compatibility decomposition of r
+// to a.
+func decompose(a []rune, r rune, compat bool) []rune {
+	if r < props[0].lo {
+		return append(a, r)
+	}
+	if s := r - sBase; 0 <= s && s < sCount {
+		a = append(a, lBase+s/nCount, vBase+(s%nCount)/tCount)
+		if t := s % tCount; t != 0 {
+			a = append(a, tBase+t)
+		}
+		return a
+	}
+	_, flags := lookup(r)
+	if compat && flags&nfkdNo != 0 {
+		if d, ok := compatDecomps[r]; ok {
+			for _, c := range d {
+				a = append(a, c)
+			}
+			return a
+		}
+	}
+	if flags&nfdNo != 0 {
+		if d, ok := canonDecomps[r]; ok {
+			for _, c := range d {
+				a = append(a, c)
+			}
+			return a
+		}
+	}
+	return append(a, r)
+}
+
+// reorder puts the combining marks of a in canonical order.
+func reorder(a []rune) {
+	for i := 1; i < len(a); i++ {
+		ccc := combiningClass(a[i])
+		if ccc == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			if prev := combiningClass(a[j-1]); prev == 0 || prev <= ccc {
+				break
+			}
+			a[j-1], a[j] = a[j], a[j-1]
+		}
+	}
+}
+
+// compose applies the canonical composition algorithm to the canonically
+// ordered code points of a.
+func compose(a []rune) []rune {
+	k := 0         // length of the result
+	starter := -1  // offset of the last starter in the result
+	var last uint8 // combining class of the last code point in the result
+	for _, r := range a {
+		ccc := combiningClass(r)
+		if starter >= 0 && (k == starter+1 || (last != 0 && last < ccc)) {
+			if c, ok := composePair(a[starter], r); ok {
+				a[starter] = c
+				continue
+			}
+		}
+		if ccc == 0 {
+			starter = k
+		}
+		a[k] = r
+		k++
+		last = ccc
+	}
+	return a[:k]
+}
+
+// composePair returns the primary composite of a and b, if any.
+func composePair(a, b rune) (rune, bool) {
+	if l := a - lBase; 0 <= l && l < lCount {
+		if v := b - vBase; 0 <= v && v < vCount {
+			return sBase + (l*vCount+v)*tCount, true
+		}
+		return 0, false
+	}
+	if s := a - sBase; 0 <= s && s < sCount && s%tCount == 0 {
+		if t := b - tBase; 0 < t && t < tCount {
+			return a + t, true
+		}
+		return 0, false
+	}
+	c, ok := compositions[[2]rune{a, b}]
+	return c, ok
+}
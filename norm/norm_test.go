@@ -0,0 +1,212 @@
+package norm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/charlievieth/utfconv"
+	"github.com/charlievieth/utfconv/internal/ucd"
+)
+
+func enc(s string) []uint16 { return utf16.Encode([]rune(s)) }
+
+var forms = []Form{NFC, NFD, NFKC, NFKD}
+
+// normalizationCase is a line of NormalizationCases.txt.
+type normalizationCase struct {
+	c    [5][]uint16 // source, NFC, NFD, NFKC, NFKD
+	line int
+}
+
+func readCases(t testing.TB) (cases []normalizationCase, single map[rune]bool) {
+	single = make(map[rune]bool)
+	part1, line := false, 0
+	err := ucd.Parse("../internal/ucd/NormalizationCases.txt", func(f []string) error {
+		line++
+		if strings.HasPrefix(f[0], "@Part") {
+			part1 = f[0] == "@Part1"
+			return nil
+		}
+		x := normalizationCase{line: line}
+		for i := range x.c {
+			r, err := ucd.Runes(f[i])
+			if err != nil {
+				return err
+			}
+			if part1 && i == 0 {
+				single[r[0]] = true
+			}
+			x.c[i] = utf16.Encode(r)
+		}
+		cases = append(cases, x)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatal("no test cases")
+	}
+	return cases, single
+}
+
+func TestNormalizationCases(t *testing.T) {
+	cases, _ := readCases(t)
+	// want are the columns of the expected result of each form, for each
+	// column of the source.
+	want := map[Form][5]int{
+		NFC:  {1, 1, 1, 3, 3},
+		NFD:  {2, 2, 2, 4, 4},
+		NFKC: {3, 3, 3, 3, 3},
+		NFKD: {4, 4, 4, 4, 4},
+	}
+	for _, x := range cases {
+		for _, f := range forms {
+			for i, s := range x.c {
+				exp := x.c[want[f][i]]
+				got := f.Normalize(s)
+				if !reflect.DeepEqual(got, exp) {
+					t.Errorf("%d: %s(%04X) = %04X; want: %04X", x.line, f, s, got, exp)
+				}
+				if got := f.Append([]uint16{'a'}, s); !reflect.DeepEqual(got[1:], exp) {
+					t.Errorf("%d: %s.Append(%04X) = %04X; want: %04X", x.line, f, s, got[1:], exp)
+				}
+				normal := reflect.DeepEqual(s, exp)
+				if got := f.IsNormal(s); got != normal {
+					t.Errorf("%d: %s.IsNormal(%04X) = %t; want: %t", x.line, f, s, got, normal)
+				}
+				if qc := f.QuickCheck(s); (qc == Yes && !normal) || (qc == No && normal) {
+					t.Errorf("%d: %s.QuickCheck(%04X) = %s; normal: %t", x.line, f, s, qc, normal)
+				}
+			}
+		}
+	}
+}
+
+// Test that the code points not listed in part 1 are not changed by any
+// form. Hangul syllables are only sampled in part 1.
+func TestNormalizationUnchanged(t *testing.T) {
+	_, single := readCases(t)
+	s := make([]uint16, 0, 2)
+	for r := rune(0); r <= '\U0010FFFF'; r++ {
+		if single[r] || (sBase <= r && r < sBase+sCount) || utf16.IsSurrogate(r) {
+			continue
+		}
+		s = utfconv.AppendRuneUTF16(s[:0], r)
+		for _, f := range forms {
+			if got := f.Normalize(s); !reflect.DeepEqual(got, s) {
+				t.Errorf("%s(%U) = %04X; want: %04X", f, r, got, s)
+			}
+			if !f.IsNormal(s) {
+				t.Errorf("%s.IsNormal(%U) = false; want: true", f, r)
+			}
+		}
+	}
+}
+
+func TestUnicodeVersion(t *testing.T) {
+	if UnicodeVersion != ucd.Version {
+		t.Errorf("UnicodeVersion = %q; want: %q, run go generate", UnicodeVersion, ucd.Version)
+	}
+}
+
+func TestNormalizeSurrogates(t *testing.T) {
+	tests := []struct {
+		f       Form
+		in, out []uint16
+	}{
+		{NFC, []uint16{0xD800, 'A', 0x0301, 0xDC00}, []uint16{0xD800, 0x00C1, 0xDC00}},
+		{NFC, []uint16{'A', 0xD800, 0x0301}, []uint16{'A', 0xD800, 0x0301}},
+		{NFC, []uint16{'A', 0xDFFF, 0x0301}, []uint16{'A', 0xDFFF, 0x0301}},
+		{NFD, []uint16{0x00C1, 0xDFFF}, []uint16{'A', 0x0301, 0xDFFF}},
+		{NFD, []uint16{0xDBFF, 0x00C1}, []uint16{0xDBFF, 'A', 0x0301}},
+		{NFKD, []uint16{0xD834, 0xDD5E, 0xD834}, []uint16{0xD834, 0xDD57, 0xD834, 0xDD65, 0xD834}},
+	}
+	for _, x := range tests {
+		if got := x.f.Normalize(x.in); !reflect.DeepEqual(got, x.out) {
+			t.Errorf("%s(%04X) = %04X; want: %04X", x.f, x.in, got, x.out)
+		}
+		if !x.f.IsNormal(x.out) {
+			t.Errorf("%s.IsNormal(%04X) = false; want: true", x.f, x.out)
+		}
+	}
+}
+
+func TestNormalizeNoCopy(t *testing.T) {
+	for _, s := range []string{"abc", "café 日本", "e\u0301\u0301"} {
+		u := enc(s)
+		for _, f := range forms {
+			if f.QuickCheck(u) != Yes {
+				continue
+			}
+			if got := f.Normalize(u); &got[0] != &u[0] {
+				t.Errorf("%s(%q): returned a copy", f, s)
+			}
+		}
+	}
+}
+
+func TestQuickCheck(t *testing.T) {
+	tests := []struct {
+		s    string
+		f    Form
+		want QuickCheckResult
+	}{
+		{"", NFC, Yes},
+		{"abc", NFC, Yes},
+		{"é", NFC, Yes},
+		{"é", NFD, No},
+		{"e\u0301", NFC, Maybe},
+		{"e\u0301", NFD, Yes},
+		{"\u0301\u0316", NFD, No},
+		{"\u0316\u0301", NFD, Yes},
+		{"ﬁ", NFC, Yes},
+		{"ﬁ", NFKC, No},
+		{"\u0344", NFC, No},
+		{"\u1100\u1161", NFC, Maybe},
+		{"가", NFD, No},
+	}
+	for _, x := range tests {
+		if got := x.f.QuickCheck(enc(x.s)); got != x.want {
+			t.Errorf("%s.QuickCheck(%q) = %s; want: %s", x.f, x.s, got, x.want)
+		}
+	}
+}
+
+func TestNormalizeString(t *testing.T) {
+	tests := []struct {
+		s    string
+		f    Form
+		want string
+	}{
+		{"", NFC, ""},
+		{"café", NFD, "cafe\u0301"},
+		{"cafe\u0301", NFC, "café"},
+		{"ﬁ\u2075", NFKC, "fi5"},
+		{"\u1100\u1161\u11A8", NFC, "각"},
+		{"각", NFD, "\u1100\u1161\u11A8"},
+		{"a\xffe\u0301", NFC, "a\uFFFDé"},
+		{"a\U0001E08F\u0323", NFD, "a\u0323\U0001E08F"},
+		{"a\U0001E08F\u0323", NFC, "\u1EA1\U0001E08F"},
+	}
+	for _, x := range tests {
+		if got := string(utf16.Decode(x.f.NormalizeString(x.s))); got != x.want {
+			t.Errorf("%s.NormalizeString(%q) = %q; want: %q", x.f, x.s, got, x.want)
+		}
+	}
+}
+
+func BenchmarkNormalize(b *testing.B) {
+	for _, f := range forms {
+		for _, s := range []string{"Hello, 世界 ", "cafe\u0301 été ", "각\u1100\u1161 "} {
+			u := enc(strings.Repeat(s, 8))
+			b.Run(f.String()+"/"+strings.TrimSpace(s), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					f.Normalize(u)
+				}
+			})
+		}
+	}
+}
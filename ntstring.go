@@ -0,0 +1,184 @@
+package utfconv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// PointerSize is the size in bytes of a pointer in the layout of a
+// structure, which depends on whether it comes from a 32-bit or a 64-bit
+// process.
+type PointerSize int
+
+const (
+	Pointer32 PointerSize = 4
+	Pointer64 PointerSize = 8
+)
+
+// unicodeStringSize returns the size of a UNICODE_STRING header, the
+// Buffer field is aligned to the pointer size.
+func (p PointerSize) unicodeStringSize() (int, error) {
+	switch p {
+	case Pointer32:
+		return 8, nil
+	case Pointer64:
+		return 16, nil
+	}
+	return 0, fmt.Errorf("utfconv: invalid pointer size: %d", int(p))
+}
+
+// A UnicodeString is the header of an NT UNICODE_STRING structure, as
+// found in memory images and self-relative structures. All fields are
+// little-endian.
+type UnicodeString struct {
+	Length        uint16 // length of the string in bytes, without a NUL
+	MaximumLength uint16 // size of the buffer in bytes
+	Buffer        uint64 // address or offset of the buffer
+}
+
+// ReadUnicodeString decodes the UNICODE_STRING header at offset off of b.
+func ReadUnicodeString(b []byte, off int, ptr PointerSize) (UnicodeString, error) {
+	size, err := ptr.unicodeStringSize()
+	if err != nil {
+		return UnicodeString{}, err
+	}
+	if off < 0 || off > len(b) || len(b)-off < size {
+		return UnicodeString{}, fmt.Errorf("utfconv: UNICODE_STRING at offset %d out of range [0, %d]",
+			off, len(b))
+	}
+	p := b[off:]
+	u := UnicodeString{
+		Length:        binary.LittleEndian.Uint16(p),
+		MaximumLength: binary.LittleEndian.Uint16(p[2:]),
+	}
+	if ptr == Pointer32 {
+		u.Buffer = uint64(binary.LittleEndian.Uint32(p[4:]))
+	} else {
+		u.Buffer = binary.LittleEndian.Uint64(p[8:])
+	}
+	return u, nil
+}
+
+// Put encodes u at offset off of b. The padding of a 64-bit header is set
+// to zero.
+func (u UnicodeString) Put(b []byte, off int, ptr PointerSize) error {
+	size, err := ptr.unicodeStringSize()
+	if err != nil {
+		return err
+	}
+	if off < 0 || off > len(b) || len(b)-off < size {
+		return fmt.Errorf("utfconv: UNICODE_STRING at offset %d out of range [0, %d]",
+			off, len(b))
+	}
+	p := b[off:]
+	binary.LittleEndian.PutUint16(p, u.Length)
+	binary.LittleEndian.PutUint16(p[2:], u.MaximumLength)
+	if ptr == Pointer32 {
+		if u.Buffer > 1<<32-1 {
+			return fmt.Errorf("utfconv: UNICODE_STRING buffer %#x overflows a 32-bit pointer", u.Buffer)
+		}
+		binary.LittleEndian.PutUint32(p[4:], uint32(u.Buffer))
+	} else {
+		binary.LittleEndian.PutUint32(p[4:], 0)
+		binary.LittleEndian.PutUint64(p[8:], u.Buffer)
+	}
+	return nil
+}
+
+// Decode returns the string described by u, whose buffer is in b. The
+// address of b[0] is base: use the address at which b was mapped for memory
+// images, or 0 if the Buffer field is an offset into b. It returns an error
+// if the length is odd or greater than the maximum length, or if the
+// buffer is not within b.
+func (u UnicodeString) Decode(b []byte, base uint64) (string, error) {
+	if u.Length%2 != 0 {
+		return "", fmt.Errorf("utfconv: UNICODE_STRING has odd length %d", u.Length)
+	}
+	if u.Length > u.MaximumLength {
+		return "", fmt.Errorf("utfconv: UNICODE_STRING length %d exceeds maximum length %d",
+			u.Length, u.MaximumLength)
+	}
+	if u.Length == 0 {
+		return "", nil
+	}
+	if u.Buffer < base || u.Buffer-base > uint64(len(b)) ||
+		uint64(len(b))-(u.Buffer-base) < uint64(u.Length) {
+		return "", fmt.Errorf("utfconv: UNICODE_STRING buffer %#x out of range [%#x, %#x]",
+			u.Buffer, base, base+uint64(len(b)))
+	}
+	i := int(u.Buffer - base)
+	return UTF16ToString(bytesLEToUTF16(b[i : i+int(u.Length)])), nil
+}
+
+// DecodeUnicodeString decodes the UNICODE_STRING header at offset off of b
+// and returns the string it describes. See UnicodeString.Decode.
+func DecodeUnicodeString(b []byte, off int, ptr PointerSize, base uint64) (string, error) {
+	u, err := ReadUnicodeString(b, off, ptr)
+	if err != nil {
+		return "", err
+	}
+	return u.Decode(b, base)
+}
+
+// AppendUnicodeString appends a self-relative UNICODE_STRING to b: a header
+// immediately followed by the NUL-terminated UTF-16 encoding of s. The
+// Buffer field is set to base plus the offset of the string in the result.
+// It returns an error if s is too long for the 16-bit length fields.
+func AppendUnicodeString(b []byte, s string, ptr PointerSize, base uint64) ([]byte, error) {
+	size, err := ptr.unicodeStringSize()
+	if err != nil {
+		return b, err
+	}
+	n := 2 * UTF16EncodedLenString(s)
+	if n > 1<<16-1-2 {
+		return b, fmt.Errorf("utfconv: string of %d bytes too long for UNICODE_STRING", n)
+	}
+	off := len(b)
+	b = append(b, make([]byte, size)...)
+	u := UnicodeString{
+		Length:        uint16(n),
+		MaximumLength: uint16(n + 2),
+		Buffer:        base + uint64(off+size),
+	}
+	if err := u.Put(b, off, ptr); err != nil {
+		return b[:off], err
+	}
+	return appendUTF16LE(b, stringToUTF16(s, 1)), nil
+}
+
+var errBSTRTerminator = errors.New("utfconv: BSTR is not NUL-terminated")
+
+// DecodeBSTR returns the OLE BSTR at offset off of b. As with a BSTR
+// pointer, off is the offset of the string data, which is preceded by its
+// 4-byte little-endian length in bytes and followed by a NUL. It returns an
+// error if the length is odd or the string is not within b.
+func DecodeBSTR(b []byte, off int) (string, error) {
+	if off < 4 || off > len(b) {
+		return "", fmt.Errorf("utfconv: BSTR at offset %d out of range [4, %d]", off, len(b))
+	}
+	n := uint64(binary.LittleEndian.Uint32(b[off-4:]))
+	if n%2 != 0 {
+		return "", fmt.Errorf("utfconv: BSTR has odd length %d", n)
+	}
+	if uint64(len(b)-off) < n+2 {
+		return "", fmt.Errorf("utfconv: BSTR of length %d at offset %d exceeds buffer of length %d",
+			n, off, len(b))
+	}
+	end := off + int(n)
+	if b[end] != 0 || b[end+1] != 0 {
+		return "", errBSTRTerminator
+	}
+	return UTF16ToString(bytesLEToUTF16(b[off:end])), nil
+}
+
+// AppendBSTR appends the OLE BSTR layout of s to b: the 4-byte
+// little-endian length of the UTF-16 encoding of s in bytes, the encoding
+// and a NUL. It returns the extended slice and the offset of the string
+// data, which is where a BSTR pointer points.
+func AppendBSTR(b []byte, s string) ([]byte, int) {
+	u := stringToUTF16(s, 1)
+	b = binary.LittleEndian.AppendUint32(b, uint32(2*(len(u)-1)))
+	off := len(b)
+	return appendUTF16LE(b, u), off
+}
@@ -0,0 +1,132 @@
+package utfconv
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnicodeString(t *testing.T) {
+	for _, ptr := range []PointerSize{Pointer32, Pointer64} {
+		for _, s := range []string{"", "a", `C:\Windows\System32`, "日本語\U0001F600"} {
+			prefix := []byte{0xAA, 0xBB, 0xCC}
+			const base = 0x7FF0000
+			b, err := AppendUnicodeString(prefix, s, ptr, base)
+			if err != nil {
+				t.Fatalf("AppendUnicodeString(%q, %d): %v", s, ptr, err)
+			}
+			u, err := ReadUnicodeString(b, len(prefix), ptr)
+			if err != nil {
+				t.Fatalf("ReadUnicodeString(%q, %d): %v", s, ptr, err)
+			}
+			n := 2 * UTF16EncodedLenString(s)
+			size := 8
+			if ptr == Pointer64 {
+				size = 16
+			}
+			exp := UnicodeString{uint16(n), uint16(n + 2), base + uint64(len(prefix)+size)}
+			if u != exp {
+				t.Errorf("ReadUnicodeString(%q, %d) = %+v; want: %+v", s, ptr, u, exp)
+			}
+			got, err := DecodeUnicodeString(b, len(prefix), ptr, base)
+			if err != nil || got != s {
+				t.Errorf("DecodeUnicodeString(%q, %d) = %q, %v; want: %q, <nil>", s, ptr, got, err, s)
+			}
+			if len(b) != len(prefix)+size+n+2 {
+				t.Errorf("AppendUnicodeString(%q, %d): length = %d; want: %d", s, ptr, len(b), len(prefix)+size+n+2)
+			}
+		}
+	}
+}
+
+func TestUnicodeStringLayout(t *testing.T) {
+	b32 := []byte{4, 0, 6, 0, 8, 0, 0, 0, 'h', 0, 'i', 0, 0, 0}
+	b64 := []byte{4, 0, 6, 0, 0, 0, 0, 0, 16, 0, 0, 0, 0, 0, 0, 0, 'h', 0, 'i', 0, 0, 0}
+	for _, x := range []struct {
+		b   []byte
+		ptr PointerSize
+	}{{b32, Pointer32}, {b64, Pointer64}} {
+		s, err := DecodeUnicodeString(x.b, 0, x.ptr, 0)
+		if err != nil || s != "hi" {
+			t.Errorf("DecodeUnicodeString(%v, %d) = %q, %v; want: %q, <nil>", x.b, x.ptr, s, err, "hi")
+		}
+		b, _ := AppendUnicodeString(nil, "hi", x.ptr, 0)
+		if !bytes.Equal(b, x.b) {
+			t.Errorf("AppendUnicodeString(%q, %d) = %v; want: %v", "hi", x.ptr, b, x.b)
+		}
+	}
+}
+
+func TestUnicodeStringErrors(t *testing.T) {
+	b := make([]byte, 32)
+	if _, err := ReadUnicodeString(b, 25, Pointer64); err == nil {
+		t.Error("ReadUnicodeString: expected error for truncated header")
+	}
+	if _, err := ReadUnicodeString(b, -1, Pointer32); err == nil {
+		t.Error("ReadUnicodeString: expected error for negative offset")
+	}
+	if _, err := ReadUnicodeString(b, 0, 2); err == nil {
+		t.Error("ReadUnicodeString: expected error for invalid pointer size")
+	}
+	tests := []struct {
+		u    UnicodeString
+		base uint64
+	}{
+		{UnicodeString{3, 4, 0}, 0},       // odd length
+		{UnicodeString{6, 4, 0}, 0},       // length > maximum length
+		{UnicodeString{4, 4, 30}, 0},      // past the end
+		{UnicodeString{4, 4, 0x100}, 0},   // past the end
+		{UnicodeString{4, 4, 0x10}, 0x20}, // before the start
+		{UnicodeString{4, 4, 1<<64 - 2}, 0},
+	}
+	for _, x := range tests {
+		if s, err := x.u.Decode(b, x.base); err == nil {
+			t.Errorf("%+v.Decode(base=%#x) = %q; expected error", x.u, x.base, s)
+		}
+	}
+	if err := (UnicodeString{Buffer: 1 << 32}).Put(b, 0, Pointer32); err == nil {
+		t.Error("Put: expected error for 32-bit pointer overflow")
+	}
+	long := string(bytes.Repeat([]byte{'a'}, 1<<15-1))
+	if _, err := AppendUnicodeString(nil, long, Pointer64, 0); err == nil {
+		t.Error("AppendUnicodeString: expected error for long string")
+	}
+	if _, err := AppendUnicodeString(nil, long[:1<<15-2], Pointer64, 0); err != nil {
+		t.Errorf("AppendUnicodeString: %v", err)
+	}
+}
+
+func TestBSTR(t *testing.T) {
+	for _, s := range []string{"", "a", "hello", "日本語\U0001F600"} {
+		b, off := AppendBSTR([]byte{1, 2, 3}, s)
+		if off != 7 {
+			t.Errorf("AppendBSTR(%q): offset = %d; want: 7", s, off)
+		}
+		if n := len(b) - off; n != 2*UTF16EncodedLenString(s)+2 {
+			t.Errorf("AppendBSTR(%q): data length = %d", s, n)
+		}
+		if got, err := DecodeBSTR(b, off); err != nil || got != s {
+			t.Errorf("DecodeBSTR(%q) = %q, %v; want: %q, <nil>", s, got, err, s)
+		}
+	}
+
+	b := []byte{4, 0, 0, 0, 'h', 0, 'i', 0, 0, 0}
+	if s, err := DecodeBSTR(b, 4); err != nil || s != "hi" {
+		t.Errorf("DecodeBSTR(%v, 4) = %q, %v; want: %q, <nil>", b, s, err, "hi")
+	}
+	errTests := []struct {
+		b   []byte
+		off int
+	}{
+		{b, 3},
+		{b, 11},
+		{b[:9], 4}, // missing NUL
+		{[]byte{4, 0, 0, 0, 'h', 0, 'i', 0, 'x', 0}, 4}, // not NUL
+		{[]byte{3, 0, 0, 0, 'h', 0, 'i', 0, 0, 0}, 4},   // odd length
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0, 0}, 4},
+	}
+	for _, x := range errTests {
+		if s, err := DecodeBSTR(x.b, x.off); err == nil {
+			t.Errorf("DecodeBSTR(%v, %d) = %q; expected error", x.b, x.off, s)
+		}
+	}
+}
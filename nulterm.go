@@ -0,0 +1,49 @@
+package utfconv
+
+import (
+	"errors"
+	"strings"
+	"unsafe"
+)
+
+// ErrNUL is returned when a string that must be NUL-terminated contains a
+// NUL character.
+var ErrNUL = errors.New("utfconv: string contains NUL")
+
+// StringToUTF16Z returns the UTF-16 encoding of s with a terminating NUL
+// added, as expected by Windows APIs. If s contains a NUL byte, it returns
+// ErrNUL. It is a portable equivalent of syscall.UTF16FromString.
+func StringToUTF16Z(s string) ([]uint16, error) {
+	if strings.IndexByte(s, 0) != -1 {
+		return nil, ErrNUL
+	}
+	return stringToUTF16(s, 1), nil
+}
+
+// UTF16ZToString returns the UTF-8 encoding of s up to its first NUL, or all
+// of s if it contains no NUL. It is a portable equivalent of
+// syscall.UTF16ToString.
+func UTF16ZToString(s []uint16) string {
+	for i, c := range s {
+		if c == 0 {
+			return UTF16ToString(s[:i])
+		}
+	}
+	return UTF16ToString(s)
+}
+
+// UTF16PtrToString returns the UTF-8 encoding of the NUL-terminated UTF-16
+// string at p, reading at most maxLen code units, which makes it safe to use
+// with buffers that may not be terminated, such as fixed size fields of
+// Windows data structures. It returns "" if p is nil or maxLen is not
+// positive.
+func UTF16PtrToString(p *uint16, maxLen int) string {
+	if p == nil || maxLen <= 0 {
+		return ""
+	}
+	n := 0
+	for n < maxLen && *(*uint16)(unsafe.Add(unsafe.Pointer(p), 2*n)) != 0 {
+		n++
+	}
+	return UTF16ToString(unsafe.Slice(p, n))
+}
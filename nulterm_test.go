@@ -0,0 +1,75 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestStringToUTF16Z(t *testing.T) {
+	for _, s := range []string{"", "abc", "日本語", "a\U0001F600b", "\xff"} {
+		u, err := StringToUTF16Z(s)
+		if err != nil {
+			t.Errorf("StringToUTF16Z(%q): %v", s, err)
+			continue
+		}
+		exp := append(StringToUTF16(s), 0)
+		if !reflect.DeepEqual(u, exp) {
+			t.Errorf("StringToUTF16Z(%q) = %v; want: %v", s, u, exp)
+		}
+	}
+	for _, s := range []string{"\x00", "a\x00", "日本\x00語"} {
+		if u, err := StringToUTF16Z(s); err != ErrNUL || u != nil {
+			t.Errorf("StringToUTF16Z(%q) = %v, %v; want: nil, %v", s, u, err, ErrNUL)
+		}
+	}
+}
+
+func TestUTF16ZToString(t *testing.T) {
+	tests := []struct {
+		in  []uint16
+		exp string
+	}{
+		{nil, ""},
+		{[]uint16{0}, ""},
+		{[]uint16{'a', 'b'}, "ab"},
+		{[]uint16{'a', 'b', 0}, "ab"},
+		{[]uint16{'a', 0, 'b', 0}, "a"},
+		{[]uint16{0xD83D, 0xDE00, 0, 0xD83D}, "\U0001F600"},
+		{[]uint16{0xD83D, 0}, "\uFFFD"},
+	}
+	for _, x := range tests {
+		if s := UTF16ZToString(x.in); s != x.exp {
+			t.Errorf("UTF16ZToString(%v) = %q; want: %q", x.in, s, x.exp)
+		}
+	}
+}
+
+func TestUTF16PtrToString(t *testing.T) {
+	buf := utf16.Encode([]rune("日本語\x00abc"))
+	tests := []struct {
+		p      *uint16
+		maxLen int
+		exp    string
+	}{
+		{nil, 10, ""},
+		{&buf[0], 0, ""},
+		{&buf[0], -1, ""},
+		{&buf[0], 2, "日本"},
+		{&buf[0], 3, "日本語"},
+		{&buf[0], len(buf), "日本語"},
+		{&buf[4], 3, "abc"},
+		{&buf[3], 3, ""},
+	}
+	for _, x := range tests {
+		if s := UTF16PtrToString(x.p, x.maxLen); s != x.exp {
+			t.Errorf("UTF16PtrToString(%p, %d) = %q; want: %q", x.p, x.maxLen, s, x.exp)
+		}
+	}
+}
+
+func BenchmarkStringToUTF16Z_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		StringToUTF16Z(SixtyFourUnicodeChars)
+	}
+}
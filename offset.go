@@ -0,0 +1,57 @@
+package utfconv
+
+import "unicode/utf8"
+
+// UTF16Index returns the number of UTF-16 code units required to encode
+// s[:i], that is the UTF-16 index corresponding to byte index i of s.
+// Invalid UTF-8 is counted as one code unit per byte, matching the U+FFFD
+// substitution performed by StringToUTF16.
+//
+// UTF16Index panics if i is not in the range [0, len(s)].
+func UTF16Index(s string, i int) int {
+	return UTF16EncodedLenString(s[:i])
+}
+
+// ByteIndexUTF16 returns the byte index in s of UTF-16 code unit n, it is the
+// inverse of UTF16Index. If n falls between the two code units of a surrogate
+// pair, the index of the start of the encoded rune is returned. If n is
+// negative or greater than UTF16EncodedLenString(s), -1 is returned.
+func ByteIndexUTF16(s string, n int) int {
+	if n < 0 {
+		return -1
+	}
+	i := 0
+	for ; i < len(s) && n > 0; i++ {
+		if s[i] >= runeSelf {
+			goto Slow
+		}
+		n--
+	}
+	if n == 0 {
+		return i
+	}
+	return -1
+
+Slow:
+	for i < len(s) && n > 0 {
+		if s[i] < runeSelf {
+			i++
+			n--
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r >= surrSelf {
+			if n == 1 {
+				// n splits a surrogate pair
+				return i
+			}
+			n--
+		}
+		i += size
+		n--
+	}
+	if n == 0 {
+		return i
+	}
+	return -1
+}
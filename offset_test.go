@@ -0,0 +1,48 @@
+package utfconv
+
+import (
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func TestUTF16Index(t *testing.T) {
+	for _, s := range append(testStrings, invalidSequenceTests...) {
+		for i := 0; i <= len(s); i++ {
+			exp := len(utf16.Encode([]rune(s[:i])))
+			if n := UTF16Index(s, i); n != exp {
+				t.Errorf("UTF16Index(%q, %d) = %d; want: %d", s, i, n, exp)
+			}
+		}
+	}
+}
+
+func TestByteIndexUTF16(t *testing.T) {
+	for _, s := range append(testStrings, invalidSequenceTests...) {
+		// the inverse of UTF16Index at every rune boundary
+		for i := 0; i <= len(s); {
+			n := UTF16Index(s, i)
+			if j := ByteIndexUTF16(s, n); j != i {
+				t.Errorf("ByteIndexUTF16(%q, %d) = %d; want: %d", s, n, j, i)
+			}
+			if i == len(s) {
+				break
+			}
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r >= 0x10000 {
+				// splitting a surrogate pair rounds down
+				if j := ByteIndexUTF16(s, n+1); j != i {
+					t.Errorf("ByteIndexUTF16(%q, %d) = %d; want: %d", s, n+1, j, i)
+				}
+			}
+			i += size
+		}
+		n := UTF16EncodedLenString(s)
+		if j := ByteIndexUTF16(s, n+1); j != -1 {
+			t.Errorf("ByteIndexUTF16(%q, %d) = %d; want: -1", s, n+1, j)
+		}
+		if j := ByteIndexUTF16(s, -1); j != -1 {
+			t.Errorf("ByteIndexUTF16(%q, -1) = %d; want: -1", s, j)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package utfconv
+
+import "unicode/utf8"
+
+// Go compares strings in code point order, which is also the order of their
+// UTF-8 encodings. Java, C#, JavaScript and SQL Server compare strings by
+// their UTF-16 code units, which differs for supplementary characters: they
+// are encoded as surrogates (0xD800-0xDFFF) and so sort before the code
+// points U+E000-U+FFFF, instead of after them.
+
+// utf16Key returns a key for r that orders runes by their UTF-16 encoding.
+func utf16Key(r rune) rune {
+	if surr3 <= r && r < surrSelf {
+		// sort after all supplementary characters
+		return r + (maxRune + 1)
+	}
+	return r
+}
+
+// CompareUTF16Order returns an integer comparing strings a and b by the
+// order of their UTF-16 code units, as Java's String.compareTo does. It is
+// the equivalent of comparing StringToUTF16(a) and StringToUTF16(b) without
+// converting either string. Invalid UTF-8 is treated as U+FFFD.
+func CompareUTF16Order(a, b string) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	if i == len(a) && i == len(b) {
+		return 0
+	}
+	// back up to the start of the rune containing the first difference,
+	// runes are never split at a byte that starts a rune
+	for i > 0 && !utf8.RuneStart(a[i-1]) {
+		i--
+	}
+	if i > 0 {
+		i--
+	}
+	j := i
+	for i < len(a) && j < len(b) {
+		r1, size1 := utf8.DecodeRuneInString(a[i:])
+		r2, size2 := utf8.DecodeRuneInString(b[j:])
+		if r1 != r2 {
+			if utf16Key(r1) < utf16Key(r2) {
+				return -1
+			}
+			return 1
+		}
+		i += size1
+		j += size2
+	}
+	switch {
+	case i < len(a):
+		return 1
+	case j < len(b):
+		return -1
+	}
+	return 0
+}
+
+// LessUTF16Order reports whether a sorts before b in UTF-16 code unit order.
+func LessUTF16Order(a, b string) bool {
+	return CompareUTF16Order(a, b) < 0
+}
+
+// ByUTF16Order implements sort.Interface for a []string sorted in UTF-16
+// code unit order. CompareUTF16Order may be used with slices.SortFunc.
+type ByUTF16Order []string
+
+func (p ByUTF16Order) Len() int           { return len(p) }
+func (p ByUTF16Order) Less(i, j int) bool { return CompareUTF16Order(p[i], p[j]) < 0 }
+func (p ByUTF16Order) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// CompareCodePointOrder returns an integer comparing UTF-16 slices a and b
+// in code point order, the order Go uses for strings. It is the equivalent
+// of strings.Compare(UTF16ToString(a), UTF16ToString(b)) without converting
+// either slice. Unpaired surrogates are treated as U+FFFD.
+func CompareCodePointOrder(a, b []uint16) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	if i == len(a) && i == len(b) {
+		return 0
+	}
+	if i > 0 && surr1 <= a[i-1] && a[i-1] < surr2 {
+		// the difference may be in the low half of a surrogate pair
+		i--
+	}
+	j := i
+	for i < len(a) && j < len(b) {
+		r1, size1 := DecodeRuneUTF16(a[i:])
+		r2, size2 := DecodeRuneUTF16(b[j:])
+		if r1 != r2 {
+			if r1 < r2 {
+				return -1
+			}
+			return 1
+		}
+		i += size1
+		j += size2
+	}
+	switch {
+	case i < len(a):
+		return 1
+	case j < len(b):
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+package utfconv
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// compareUnits compares UTF-16 slices by code unit.
+func compareUnits(a, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+var orderTests = append(append([]string{
+	"",
+	"a",
+	"ab",
+	"\uE000",
+	"\uFFFF",
+	"\uFFFD",
+	"\U00010000",
+	"\U0010FFFF",
+	"a\uFF61",
+	"a\U0001F600",
+	"a\U0001F600b",
+	"a\U0001F601",
+	"\xff",
+	"a\xff",
+	"a\xe2\x80",
+}, testStrings...), invalidSequenceTests...)
+
+func TestCompareUTF16Order(t *testing.T) {
+	for _, a := range orderTests {
+		for _, b := range orderTests {
+			exp := compareUnits(StringToUTF16(a), StringToUTF16(b))
+			if c := CompareUTF16Order(a, b); c != exp {
+				t.Errorf("CompareUTF16Order(%q, %q) = %d; want: %d", a, b, c, exp)
+			}
+		}
+	}
+}
+
+func TestCompareCodePointOrder(t *testing.T) {
+	units := [][]uint16{
+		{0xd800},
+		{'a', 0xdc00},
+		{0xd83d, 0xde00, 0xd800},
+	}
+	for _, s := range orderTests {
+		units = append(units, StringToUTF16(s))
+	}
+	for _, a := range units {
+		for _, b := range units {
+			exp := strings.Compare(UTF16ToString(a), UTF16ToString(b))
+			if c := CompareCodePointOrder(a, b); c != exp {
+				t.Errorf("CompareCodePointOrder(%#x, %#x) = %d; want: %d", a, b, c, exp)
+			}
+		}
+	}
+}
+
+func TestByUTF16Order(t *testing.T) {
+	a := []string{"\uFFFF", "\U0001F600", "z", "\uE000", "a"}
+	exp := []string{"a", "z", "\U0001F600", "\uE000", "\uFFFF"}
+	sort.Sort(ByUTF16Order(a))
+	for i := range a {
+		if a[i] != exp[i] {
+			t.Fatalf("sort.Sort(ByUTF16Order) = %q; want: %q", a, exp)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package utfconv
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var errPowerShellOddLength = errors.New("utfconv: encoded command has an odd number of bytes")
+
+// EncodePowerShellCommand returns script encoded for the -EncodedCommand
+// parameter of PowerShell: the standard base64 encoding of its UTF-16LE
+// encoding. Invalid UTF-8 is encoded as U+FFFD.
+func EncodePowerShellCommand(script string) string {
+	return base64.StdEncoding.EncodeToString(utf16ToBytesLE(StringToUTF16(script)))
+}
+
+// DecodePowerShellCommand decodes a command encoded for the -EncodedCommand
+// parameter of PowerShell. It returns an error if s is not valid standard
+// base64, if the decoded payload has an odd number of bytes, or if it
+// contains an unpaired surrogate, which is reported with its byte offset.
+func DecodePowerShellCommand(s string) (string, error) {
+	p, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("utfconv: invalid encoded command: %w", err)
+	}
+	if len(p)%2 != 0 {
+		return "", errPowerShellOddLength
+	}
+	u := bytesLEToUTF16(p)
+	for i := 0; i < len(u); {
+		// only an unpaired surrogate decodes as U+FFFD without being U+FFFD
+		r, n := DecodeRuneUTF16(u[i:])
+		if r == runeError && u[i] != runeError {
+			return "", fmt.Errorf("utfconv: encoded command has unpaired surrogate %#04x at byte offset %d",
+				u[i], 2*i)
+		}
+		i += n
+	}
+	return UTF16ToString(u), nil
+}
@@ -0,0 +1,46 @@
+package utfconv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPowerShellCommand(t *testing.T) {
+	tests := []struct {
+		script, enc string
+	}{
+		{"", ""},
+		{"dir", "ZABpAHIA"},
+		{"Write-Host 'hi'", "VwByAGkAdABlAC0ASABvAHMAdAAgACcAaABpACcA"},
+		{"echo 日本\U0001F600", "ZQBjAGgAbwAgAOVlLGc92ADe"},
+	}
+	for _, x := range tests {
+		if enc := EncodePowerShellCommand(x.script); enc != x.enc {
+			t.Errorf("EncodePowerShellCommand(%q) = %q; want: %q", x.script, enc, x.enc)
+		}
+		if s, err := DecodePowerShellCommand(x.enc); err != nil || s != x.script {
+			t.Errorf("DecodePowerShellCommand(%q) = %q, %v; want: %q, <nil>", x.enc, s, err, x.script)
+		}
+	}
+	if enc := EncodePowerShellCommand("a\xff"); enc != "YQD9/w==" {
+		t.Errorf("EncodePowerShellCommand(%q) = %q; want: %q", "a\xff", enc, "YQD9/w==")
+	}
+}
+
+func TestDecodePowerShellCommandErrors(t *testing.T) {
+	tests := []struct {
+		enc, err string
+	}{
+		{"ZABpAH", "invalid encoded command"},   // bad base64
+		{"ZABpAHI=", "odd number of bytes"},     // "d\x00i\x00r"
+		{"PdhhAA==", "0xd83d at byte offset 0"}, // high surrogate followed by 'a'
+		{"YQAA3g==", "0xde00 at byte offset 2"}, // low surrogate
+		{"YQA92A==", "0xd83d at byte offset 2"}, // high surrogate at end
+	}
+	for _, x := range tests {
+		s, err := DecodePowerShellCommand(x.enc)
+		if err == nil || !strings.Contains(err.Error(), x.err) {
+			t.Errorf("DecodePowerShellCommand(%q) = %q, %v; want error containing %q", x.enc, s, err, x.err)
+		}
+	}
+}
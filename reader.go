@@ -0,0 +1,150 @@
+package utfconv
+
+import (
+	"errors"
+	"io"
+)
+
+var errUnreadRune = errors.New("utfconv.UTF16Reader.UnreadRune: previous operation was not ReadRune")
+
+// A UTF16Reader implements the io.RuneReader and io.RuneScanner interfaces
+// by reading from a UTF-16 slice, which allows matching it with the Reader
+// methods of regexp.Regexp without converting it to a string. Unpaired
+// surrogates are read as U+FFFD.
+//
+// The size returned by ReadRune is the length of the rune in UTF-8, so the
+// match indexes reported by regexp are byte offsets into UTF16ToString(s).
+// Use MatchIndexUTF16 to convert them to indexes into s.
+type UTF16Reader struct {
+	s    []uint16
+	i    int // current index
+	prev int // index of the previous rune, or -1
+}
+
+// NewUTF16Reader returns a new UTF16Reader reading from s.
+func NewUTF16Reader(s []uint16) *UTF16Reader { return &UTF16Reader{s, 0, -1} }
+
+// Len returns the number of unread code units.
+func (r *UTF16Reader) Len() int {
+	if r.i >= len(r.s) {
+		return 0
+	}
+	return len(r.s) - r.i
+}
+
+// ReadRune reads the next rune and returns it and its length in UTF-8. At
+// the end of the slice it returns io.EOF.
+func (r *UTF16Reader) ReadRune() (ch rune, size int, err error) {
+	if r.i >= len(r.s) {
+		r.prev = -1
+		return 0, 0, io.EOF
+	}
+	r.prev = r.i
+	if c := r.s[r.i]; c < runeSelf {
+		r.i++
+		return rune(c), 1, nil
+	}
+	ch, n := DecodeRuneUTF16(r.s[r.i:])
+	r.i += n
+	switch {
+	case ch <= rune2Max:
+		return ch, 2, nil
+	case ch <= rune3Max:
+		return ch, 3, nil
+	}
+	return ch, 4, nil
+}
+
+// UnreadRune unreads the last rune. It returns an error if the previous
+// operation was not a successful ReadRune.
+func (r *UTF16Reader) UnreadRune() error {
+	if r.prev < 0 {
+		return errUnreadRune
+	}
+	r.i, r.prev = r.prev, -1
+	return nil
+}
+
+// Reset resets the reader to read from s.
+func (r *UTF16Reader) Reset(s []uint16) { *r = UTF16Reader{s, 0, -1} }
+
+// UTF16IndexUTF8 returns the index in UTF-16 slice s of the code point at
+// byte index i of UTF16ToString(s). Unpaired surrogates are counted as the
+// three bytes of U+FFFD. If i falls within the encoding of a code point, the
+// index of that code point is returned. If i is negative or greater than
+// UTF8EncodedLen(s), -1 is returned.
+func UTF16IndexUTF8(s []uint16, i int) int {
+	n, _ := utf16IndexUTF8(s, 0, 0, i)
+	return n
+}
+
+// utf16IndexUTF8 is like UTF16IndexUTF8 but starts scanning at index n of s
+// which is at byte index b, and also returns the byte index of the result.
+func utf16IndexUTF8(s []uint16, n, b, i int) (int, int) {
+	if i < b {
+		return -1, -1
+	}
+	for n < len(s) {
+		c := s[n]
+		if c < runeSelf {
+			if b == i {
+				return n, b
+			}
+			n++
+			b++
+			continue
+		}
+		r, size := DecodeRuneUTF16(s[n:])
+		w := 3
+		switch {
+		case r <= rune2Max:
+			w = 2
+		case r > rune3Max:
+			w = 4
+		}
+		if i < b+w {
+			return n, b
+		}
+		n += size
+		b += w
+	}
+	if b == i {
+		return n, b
+	}
+	return -1, -1
+}
+
+// MatchIndexUTF16 converts the byte indexes of a match in UTF-16 slice s, as
+// returned by the FindReaderIndex and FindReaderSubmatchIndex methods of
+// regexp.Regexp using a UTF16Reader, to indexes into s. Negative indexes,
+// which mark unmatched subexpressions, are kept as is. It returns nil if loc
+// is nil.
+//
+// Since regexp reports matches relative to the start of the reader, the
+// indexes of a match found after reading from s[k:] are relative to k.
+func MatchIndexUTF16(s []uint16, loc []int) []int {
+	if loc == nil {
+		return nil
+	}
+	a := make([]int, len(loc))
+	// The submatches are within the match, convert its start once and
+	// scan from there.
+	n, b := 0, 0
+	if len(loc) > 0 && loc[0] >= 0 {
+		n, b = utf16IndexUTF8(s, 0, 0, loc[0])
+		if n < 0 {
+			n, b = 0, 0
+		}
+	}
+	for j, i := range loc {
+		switch {
+		case i < 0:
+			a[j] = i
+		case i >= b:
+			a[j], _ = utf16IndexUTF8(s, n, b, i)
+		default:
+			a[j] = UTF16IndexUTF8(s, i)
+		}
+	}
+	return a
+}
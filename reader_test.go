@@ -0,0 +1,138 @@
+package utfconv
+
+import (
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestUTF16Reader(t *testing.T) {
+	s := []uint16{'a', 0x00E9, 0x4E16, 0xD834, 0xDD1E, 0xD800, 'b'}
+	want := []struct {
+		r    rune
+		size int
+	}{
+		{'a', 1},
+		{0x00E9, 2},
+		{0x4E16, 3},
+		{0x1D11E, 4},
+		{runeError, 3},
+		{'b', 1},
+	}
+	rd := NewUTF16Reader(s)
+	for i, x := range want {
+		r, size, err := rd.ReadRune()
+		if r != x.r || size != x.size || err != nil {
+			t.Fatalf("%d: ReadRune() = %q, %d, %v; want: %q, %d, <nil>", i, r, size, err, x.r, x.size)
+		}
+		if err := rd.UnreadRune(); err != nil {
+			t.Fatalf("%d: UnreadRune: %v", i, err)
+		}
+		if err := rd.UnreadRune(); err == nil {
+			t.Fatalf("%d: UnreadRune: expected error after UnreadRune", i)
+		}
+		if r2, _, _ := rd.ReadRune(); r2 != r {
+			t.Fatalf("%d: ReadRune after UnreadRune = %q; want: %q", i, r2, r)
+		}
+	}
+	if n := rd.Len(); n != 0 {
+		t.Errorf("Len() = %d; want: 0", n)
+	}
+	if _, _, err := rd.ReadRune(); err != io.EOF {
+		t.Errorf("ReadRune() at end: err = %v; want: %v", err, io.EOF)
+	}
+	if err := rd.UnreadRune(); err == nil {
+		t.Error("UnreadRune: expected error after EOF")
+	}
+	rd.Reset(s[3:])
+	if n := rd.Len(); n != 4 {
+		t.Errorf("Len() after Reset = %d; want: 4", n)
+	}
+	if r, _, _ := rd.ReadRune(); r != 0x1D11E {
+		t.Errorf("ReadRune() after Reset = %q; want: %q", r, rune(0x1D11E))
+	}
+}
+
+func TestUTF16IndexUTF8(t *testing.T) {
+	s := []uint16{'a', 0x00E9, 0x4E16, 0xD834, 0xDD1E, 0xDC00, 'b'}
+	// byte index => UTF-16 index
+	want := []int{0, 1, 1, 2, 2, 2, 3, 3, 3, 3, 5, 5, 5, 6, 7}
+	for i, n := range want {
+		if got := UTF16IndexUTF8(s, i); got != n {
+			t.Errorf("UTF16IndexUTF8(s, %d) = %d; want: %d", i, got, n)
+		}
+	}
+	for _, i := range []int{-1, len(want)} {
+		if got := UTF16IndexUTF8(s, i); got != -1 {
+			t.Errorf("UTF16IndexUTF8(s, %d) = %d; want: -1", i, got)
+		}
+	}
+}
+
+func TestMatchIndexUTF16(t *testing.T) {
+	tests := []struct {
+		re string
+		s  []uint16
+	}{
+		{`b+`, utf16.Encode([]rune("aaabbbccc"))},
+		{`ö(\w*)`, utf16.Encode([]rune("héllo 𝄞 wörld"))},
+		{`𝄞\s(w)(x)?`, utf16.Encode([]rune("héllo 𝄞 wörld"))},
+		{`\x{FFFD}b`, []uint16{'a', 0xD800, 'b', 0xDC00, 'b'}},
+		{`(?:日本)+(語)?`, utf16.Encode([]rune("こんにちは日本日本"))},
+		{`^$`, nil},
+		{`x`, utf16.Encode([]rune("abc"))},
+	}
+	for _, x := range tests {
+		re := regexp.MustCompile(x.re)
+		str := UTF16ToString(x.s)
+		var want []int
+		for _, i := range re.FindStringSubmatchIndex(str) {
+			if i >= 0 {
+				i = UTF16Index(str, i)
+			}
+			want = append(want, i)
+		}
+		loc := re.FindReaderSubmatchIndex(NewUTF16Reader(x.s))
+		if got := MatchIndexUTF16(x.s, loc); !reflect.DeepEqual(got, want) {
+			t.Errorf("MatchIndexUTF16(%q, %v) = %v; want: %v", str, loc, got, want)
+		}
+	}
+}
+
+func TestMatchIndexUTF16All(t *testing.T) {
+	// find all matches by reading from the end of the previous match
+	s := utf16.Encode([]rune("ab 日本 𝄞𝄞 cd"))
+	re := regexp.MustCompile(`[^ ]+`)
+	var got [][]uint16
+	for k := 0; k < len(s); {
+		loc := MatchIndexUTF16(s[k:], re.FindReaderIndex(NewUTF16Reader(s[k:])))
+		if loc == nil {
+			break
+		}
+		got = append(got, s[k+loc[0]:k+loc[1]])
+		k += loc[1]
+	}
+	want := [][]uint16{
+		utf16.Encode([]rune("ab")),
+		utf16.Encode([]rune("日本")),
+		utf16.Encode([]rune("𝄞𝄞")),
+		utf16.Encode([]rune("cd")),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matches = %v; want: %v", got, want)
+	}
+}
+
+func BenchmarkUTF16Reader_SixtyFourUnicode(b *testing.B) {
+	rd := NewUTF16Reader(SixtyFourUnicodeCharsUTF16)
+	for i := 0; i < b.N; i++ {
+		rd.Reset(SixtyFourUnicodeCharsUTF16)
+		for {
+			if _, _, err := rd.ReadRune(); err != nil {
+				break
+			}
+		}
+	}
+}
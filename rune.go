@@ -0,0 +1,44 @@
+package utfconv
+
+// DecodeRuneUTF16 unpacks the first rune of the UTF-16 slice s and returns
+// it and the number of code units it occupies. If s is empty it returns
+// (U+FFFD, 0) and if s begins with an unpaired surrogate it returns
+// (U+FFFD, 1), like utf8.DecodeRune.
+func DecodeRuneUTF16(s []uint16) (rune, int) {
+	if len(s) == 0 {
+		return runeError, 0
+	}
+	switch r := rune(s[0]); {
+	case r < surr1, surr3 <= r:
+		return r, 1
+	case r < surr2 && len(s) > 1 && surr2 <= s[1] && s[1] < surr3:
+		return (r-surr1)<<10 | (rune(s[1]) - surr2) + surrSelf, 2
+	}
+	return runeError, 1
+}
+
+// AppendRuneUTF16 appends the UTF-16 encoding of r to a and returns the
+// extended slice. Surrogate code points and values that are not valid
+// Unicode code points are appended as U+FFFD.
+func AppendRuneUTF16(a []uint16, r rune) []uint16 {
+	switch {
+	case 0 <= r && r < surr1, surr3 <= r && r < surrSelf:
+		return append(a, uint16(r))
+	case surrSelf <= r && r <= maxRune:
+		r -= surrSelf
+		return append(a, uint16(surr1+(r>>10)&0x3ff), uint16(surr2+r&0x3ff))
+	}
+	return append(a, runeError)
+}
+
+// RuneLenUTF16 returns the number of UTF-16 code units needed to encode r,
+// or -1 if r is a surrogate or not a valid Unicode code point.
+func RuneLenUTF16(r rune) int {
+	switch {
+	case 0 <= r && r < surr1, surr3 <= r && r < surrSelf:
+		return 1
+	case surrSelf <= r && r <= maxRune:
+		return 2
+	}
+	return -1
+}
@@ -0,0 +1,44 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestDecodeRuneUTF16(t *testing.T) {
+	tests := []struct {
+		in []uint16
+		r  rune
+		n  int
+	}{
+		{nil, runeError, 0},
+		{[]uint16{'a', 'b'}, 'a', 1},
+		{[]uint16{0xFFFF}, 0xFFFF, 1},
+		{[]uint16{0xd83d, 0xde00, 'a'}, 0x1F600, 2},
+		{[]uint16{0xd83d}, runeError, 1},
+		{[]uint16{0xd83d, 'a'}, runeError, 1},
+		{[]uint16{0xde00, 0xd83d}, runeError, 1},
+	}
+	for _, x := range tests {
+		if r, n := DecodeRuneUTF16(x.in); r != x.r || n != x.n {
+			t.Errorf("DecodeRuneUTF16(%#x) = %U, %d; want: %U, %d", x.in, r, n, x.r, x.n)
+		}
+	}
+}
+
+func TestAppendRuneUTF16(t *testing.T) {
+	for _, r := range []rune{0, 'a', 0xD7FF, 0xD800, 0xDFFF, 0xE000, 0xFFFF, 0x10000, 0x1F600, maxRune, maxRune + 1, -1} {
+		exp := utf16.AppendRune([]uint16{'x'}, r)
+		if a := AppendRuneUTF16([]uint16{'x'}, r); !reflect.DeepEqual(a, exp) {
+			t.Errorf("AppendRuneUTF16(%U) = %#x; want: %#x", r, a, exp)
+		}
+		n := len(exp) - 1
+		if exp[1] == runeError && r != runeError {
+			n = -1
+		}
+		if l := RuneLenUTF16(r); l != n {
+			t.Errorf("RuneLenUTF16(%U) = %d; want: %d", r, l, n)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package utfconv
+
+import "unicode/utf8"
+
+//go:generate go run gen_segment.go
+
+// A Segment is the position of a grapheme cluster, word or sentence in text,
+// as a range of byte offsets into its UTF-8 encoding and as a range of
+// offsets into its UTF-16 encoding.
+type Segment struct {
+	Start, End           int // byte offsets
+	StartUTF16, EndUTF16 int // UTF-16 code unit offsets
+}
+
+// breakProperty is a code point property used by the text segmentation
+// rules of UAX #29, such as Grapheme_Cluster_Break or Word_Break.
+type breakProperty uint8
+
+// sot is the property of the start of text.
+const sot breakProperty = 0xff
+
+type propRange struct {
+	lo, hi rune
+	prop   breakProperty
+}
+
+// lookupProp returns the property of r in ranges or 0 (Other) if r is not
+// in ranges.
+func lookupProp(ranges []propRange, r rune) breakProperty {
+	lo, hi := 0, len(ranges)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		switch {
+		case r < ranges[m].lo:
+			hi = m
+		case r > ranges[m].hi:
+			lo = m + 1
+		default:
+			return ranges[m].prop
+		}
+	}
+	return 0
+}
+
+// A segmenter splits a string or UTF-16 slice into segments. The step
+// function of an iterator reports whether there is a boundary before the
+// code point r at native offset i, the byte offset into s or the code unit
+// offset into u, and is called once for each code point in order.
+type segmenter struct {
+	s     string
+	u     []uint16
+	utf16 bool
+	seg   Segment
+	step  func(r rune, i int) bool
+
+	// the code point at the end of seg
+	nb, nu int  // length in bytes and UTF-16 code units, 0 at the end
+	brk    bool // boundary before the code point
+}
+
+// len returns the length of the text in native offsets.
+func (t *segmenter) len() int {
+	if t.utf16 {
+		return len(t.u)
+	}
+	return len(t.s)
+}
+
+// decode returns the code point at native offset i and its native length.
+// Invalid UTF-8 and unpaired surrogates decode as U+FFFD.
+func (t *segmenter) decode(i int) (rune, int) {
+	if t.utf16 {
+		return DecodeRuneUTF16(t.u[i:])
+	}
+	if c := t.s[i]; c < runeSelf {
+		return rune(c), 1
+	}
+	return utf8.DecodeRuneInString(t.s[i:])
+}
+
+func (t *segmenter) init(s string, u []uint16, utf16 bool, step func(rune, int) bool) {
+	t.s, t.u, t.utf16, t.step = s, u, utf16, step
+	t.peek()
+}
+
+// peek decodes the code point at the end of the current segment.
+func (t *segmenter) peek() {
+	i := t.seg.End
+	if t.utf16 {
+		i = t.seg.EndUTF16
+	}
+	if i == t.len() {
+		t.nb, t.nu = 0, 0
+		return
+	}
+	r, n := t.decode(i)
+	if t.utf16 {
+		t.nu = n
+		switch {
+		case r <= rune1Max:
+			t.nb = 1
+		case r <= rune2Max:
+			t.nb = 2
+		case r <= rune3Max:
+			t.nb = 3
+		default:
+			t.nb = 4
+		}
+	} else {
+		t.nb, t.nu = n, 1
+		if r >= surrSelf {
+			t.nu = 2
+		}
+	}
+	t.brk = t.step(r, i)
+}
+
+// next advances to the next segment and reports whether there is one.
+func (t *segmenter) next() bool {
+	t.seg.Start, t.seg.StartUTF16 = t.seg.End, t.seg.EndUTF16
+	if t.nb == 0 {
+		return false
+	}
+	for {
+		t.seg.End += t.nb
+		t.seg.EndUTF16 += t.nu
+		t.peek()
+		if t.nb == 0 || t.brk {
+			return true
+		}
+	}
+}
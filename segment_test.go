@@ -0,0 +1,68 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/charlievieth/utfconv/internal/ucd"
+)
+
+// testBreakFile checks that segmenting each test case of the UAX #29 test
+// file at path, both as a string and as UTF-16, yields its segments.
+func testBreakFile(t *testing.T, path string, str func(string) []Segment, u16 func([]uint16) []Segment) {
+	t.Helper()
+	tests, err := ucd.ParseBreakTest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) == 0 {
+		t.Fatalf("%s: no test cases", path)
+	}
+	for _, x := range tests {
+		s := x.String()
+		var exp []Segment
+		var g Segment
+		for _, seg := range x.Segments {
+			g.Start, g.StartUTF16 = g.End, g.EndUTF16
+			for _, r := range seg {
+				g.End += utf8.RuneLen(r)
+				g.EndUTF16 += len(utf16.Encode([]rune{r}))
+			}
+			exp = append(exp, g)
+		}
+		if got := str(s); !reflect.DeepEqual(got, exp) {
+			t.Errorf("%+q: string segments = %v; want: %v\n\t# %s", s, got, exp, x.Comment)
+		}
+		if got := u16(StringToUTF16(s)); !reflect.DeepEqual(got, exp) {
+			t.Errorf("%+q: UTF-16 segments = %v; want: %v\n\t# %s", s, got, exp, x.Comment)
+		}
+	}
+}
+
+func TestLookupProp(t *testing.T) {
+	for i := 1; i < len(graphemeRanges); i++ {
+		if graphemeRanges[i-1].hi >= graphemeRanges[i].lo {
+			t.Fatalf("graphemeRanges: %U is not sorted", graphemeRanges[i].lo)
+		}
+	}
+	tests := []struct {
+		r   rune
+		exp breakProperty
+	}{
+		{'\r', gbCR},
+		{0x0300, gbExtend},
+		{0x036F, gbExtend},
+		{0x0370, gbOther},
+		{0x200D, gbZWJ},
+		{0x1F1E6, gbRegionalIndicator},
+		{0x1F600, gbExtendedPictographic},
+		{maxRune, gbOther},
+	}
+	for _, x := range tests {
+		if p := lookupProp(graphemeRanges[:], x.r); p != x.exp {
+			t.Errorf("lookupProp(graphemeRanges, %U) = %d; want: %d", x.r, p, x.exp)
+		}
+	}
+}
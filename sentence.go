@@ -0,0 +1,138 @@
+package utfconv
+
+// Sentence_Break property values.
+const (
+	sbOther breakProperty = iota
+	sbCR
+	sbLF
+	sbExtend
+	sbSep
+	sbFormat
+	sbSp
+	sbLower
+	sbUpper
+	sbOLetter
+	sbNumeric
+	sbATerm
+	sbSContinue
+	sbSTerm
+	sbClose
+)
+
+// sentenceProp returns the sentence break property of r.
+func sentenceProp(r rune) breakProperty {
+	if r < runeSelf {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return sbLower
+		case 'A' <= r && r <= 'Z':
+			return sbUpper
+		case '0' <= r && r <= '9':
+			return sbNumeric
+		case r == ' ':
+			return sbSp
+		}
+	}
+	return lookupProp(sentenceRanges[:], r)
+}
+
+func isParaSep(p breakProperty) bool { return p == sbSep || p == sbCR || p == sbLF }
+
+// sentenceState holds the context needed to apply the sentence boundary
+// rules of UAX #29 to the next code point.
+type sentenceState struct {
+	raw   breakProperty // previous code point
+	prev  breakProperty // previous code point ignoring Extend and Format
+	prev2 breakProperty // code point before prev
+	term  breakProperty // ATerm or STerm if text ends with SATerm Close* Sp*
+	sp    bool          // the SATerm Close* Sp* sequence has spaces
+}
+
+// A SentenceIterator iterates over the sentences of a string or a UTF-16
+// slice, as defined by the sentence boundary rules of Unicode Standard Annex
+// #29. Invalid UTF-8 and unpaired surrogates are treated as U+FFFD.
+type SentenceIterator struct {
+	segmenter
+	st sentenceState
+}
+
+// NewSentenceIterator returns a SentenceIterator over the string s.
+func NewSentenceIterator(s string) *SentenceIterator {
+	it := &SentenceIterator{st: sentenceState{raw: sot, prev: sot, prev2: sot}}
+	it.init(s, nil, false, it.breakBefore)
+	return it
+}
+
+// NewSentenceIteratorUTF16 returns a SentenceIterator over the UTF-16 slice
+// s.
+func NewSentenceIteratorUTF16(s []uint16) *SentenceIterator {
+	it := &SentenceIterator{st: sentenceState{raw: sot, prev: sot, prev2: sot}}
+	it.init("", s, true, it.breakBefore)
+	return it
+}
+
+// Next advances the iterator to the next sentence and reports whether there
+// is one.
+func (it *SentenceIterator) Next() bool { return it.next() }
+
+// Sentence returns the position of the current sentence.
+func (it *SentenceIterator) Sentence() Segment { return it.seg }
+
+// lowerFollows reports whether the text starting at native offset i matches
+// (¬(OLetter | Upper | Lower | ParaSep | SATerm))* Lower, as in rule SB8.
+func (it *SentenceIterator) lowerFollows(i int) bool {
+	for n := 0; i < it.len(); i += n {
+		var r rune
+		r, n = it.decode(i)
+		switch p := sentenceProp(r); {
+		case p == sbLower:
+			return true
+		case p == sbOLetter, p == sbUpper, isParaSep(p), p == sbATerm, p == sbSTerm:
+			return false
+		}
+	}
+	return false
+}
+
+func (it *SentenceIterator) breakBefore(r rune, i int) bool {
+	p := sentenceProp(r)
+	st := &it.st
+	raw, prev, prev2, term, sp := st.raw, st.prev, st.prev2, st.term, st.sp
+	st.raw = p
+	if (p != sbExtend && p != sbFormat) || raw == sot || isParaSep(raw) {
+		st.prev2, st.prev = prev, p
+		switch {
+		case p == sbATerm || p == sbSTerm:
+			st.term, st.sp = p, false
+		case p == sbClose && term != sbOther && !sp:
+		case p == sbSp && term != sbOther:
+			st.sp = true
+		default:
+			st.term = sbOther
+		}
+	}
+
+	switch {
+	case raw == sbCR && p == sbLF: // SB3
+		return false
+	case isParaSep(raw): // SB4
+		return true
+	case p == sbExtend || p == sbFormat: // SB5
+		return false
+	case prev == sbATerm && p == sbNumeric: // SB6
+		return false
+	case (prev2 == sbUpper || prev2 == sbLower) && prev == sbATerm && p == sbUpper: // SB7
+		return false
+	case term == sbATerm && it.lowerFollows(i): // SB8
+		return false
+	case term != sbOther && (p == sbSContinue || p == sbATerm || p == sbSTerm): // SB8a
+		return false
+	case term != sbOther && !sp && (p == sbClose || p == sbSp || isParaSep(p)): // SB9
+		return false
+	case term != sbOther && (p == sbSp || isParaSep(p)): // SB10
+		return false
+	case term != sbOther: // SB11
+		return true
+	}
+	return false // SB998
+}
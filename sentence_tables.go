@@ -0,0 +1,2429 @@
+// Code generated by gen_segment.go; DO NOT EDIT.
+
+package utfconv
+
+// sentenceRanges are the Sentence_Break property values of Unicode
+// 15.0.0 sorted by code point.
+var sentenceRanges = [...]propRange{
+	{0x0009, 0x0009, sbSp},
+	{0x000A, 0x000A, sbLF},
+	{0x000B, 0x000C, sbSp},
+	{0x000D, 0x000D, sbCR},
+	{0x0020, 0x0020, sbSp},
+	{0x0021, 0x0021, sbSTerm},
+	{0x0022, 0x0022, sbClose},
+	{0x0027, 0x0029, sbClose},
+	{0x002C, 0x002D, sbSContinue},
+	{0x002E, 0x002E, sbATerm},
+	{0x0030, 0x0039, sbNumeric},
+	{0x003A, 0x003A, sbSContinue},
+	{0x003F, 0x003F, sbSTerm},
+	{0x0041, 0x005A, sbUpper},
+	{0x005B, 0x005B, sbClose},
+	{0x005D, 0x005D, sbClose},
+	{0x0061, 0x007A, sbLower},
+	{0x007B, 0x007B, sbClose},
+	{0x007D, 0x007D, sbClose},
+	{0x0085, 0x0085, sbSep},
+	{0x00A0, 0x00A0, sbSp},
+	{0x00AA, 0x00AA, sbLower},
+	{0x00AB, 0x00AB, sbClose},
+	{0x00AD, 0x00AD, sbFormat},
+	{0x00B5, 0x00B5, sbLower},
+	{0x00BA, 0x00BA, sbLower},
+	{0x00BB, 0x00BB, sbClose},
+	{0x00C0, 0x00D6, sbUpper},
+	{0x00D8, 0x00DE, sbUpper},
+	{0x00DF, 0x00F6, sbLower},
+	{0x00F8, 0x00FF, sbLower},
+	{0x0100, 0x0100, sbUpper},
+	{0x0101, 0x0101, sbLower},
+	{0x0102, 0x0102, sbUpper},
+	{0x0103, 0x0103, sbLower},
+	{0x0104, 0x0104, sbUpper},
+	{0x0105, 0x0105, sbLower},
+	{0x0106, 0x0106, sbUpper},
+	{0x0107, 0x0107, sbLower},
+	{0x0108, 0x0108, sbUpper},
+	{0x0109, 0x0109, sbLower},
+	{0x010A, 0x010A, sbUpper},
+	{0x010B, 0x010B, sbLower},
+	{0x010C, 0x010C, sbUpper},
+	{0x010D, 0x010D, sbLower},
+	{0x010E, 0x010E, sbUpper},
+	{0x010F, 0x010F, sbLower},
+	{0x0110, 0x0110, sbUpper},
+	{0x0111, 0x0111, sbLower},
+	{0x0112, 0x0112, sbUpper},
+	{0x0113, 0x0113, sbLower},
+	{0x0114, 0x0114, sbUpper},
+	{0x0115, 0x0115, sbLower},
+	{0x0116, 0x0116, sbUpper},
+	{0x0117, 0x0117, sbLower},
+	{0x0118, 0x0118, sbUpper},
+	{0x0119, 0x0119, sbLower},
+	{0x011A, 0x011A, sbUpper},
+	{0x011B, 0x011B, sbLower},
+	{0x011C, 0x011C, sbUpper},
+	{0x011D, 0x011D, sbLower},
+	{0x011E, 0x011E, sbUpper},
+	{0x011F, 0x011F, sbLower},
+	{0x0120, 0x0120, sbUpper},
+	{0x0121, 0x0121, sbLower},
+	{0x0122, 0x0122, sbUpper},
+	{0x0123, 0x0123, sbLower},
+	{0x0124, 0x0124, sbUpper},
+	{0x0125, 0x0125, sbLower},
+	{0x0126, 0x0126, sbUpper},
+	{0x0127, 0x0127, sbLower},
+	{0x0128, 0x0128, sbUpper},
+	{0x0129, 0x0129, sbLower},
+	{0x012A, 0x012A, sbUpper},
+	{0x012B, 0x012B, sbLower},
+	{0x012C, 0x012C, sbUpper},
+	{0x012D, 0x012D, sbLower},
+	{0x012E, 0x012E, sbUpper},
+	{0x012F, 0x012F, sbLower},
+	{0x0130, 0x0130, sbUpper},
+	{0x0131, 0x0131, sbLower},
+	{0x0132, 0x0132, sbUpper},
+	{0x0133, 0x0133, sbLower},
+	{0x0134, 0x0134, sbUpper},
+	{0x0135, 0x0135, sbLower},
+	{0x0136, 0x0136, sbUpper},
+	{0x0137, 0x0138, sbLower},
+	{0x0139, 0x0139, sbUpper},
+	{0x013A, 0x013A, sbLower},
+	{0x013B, 0x013B, sbUpper},
+	{0x013C, 0x013C, sbLower},
+	{0x013D, 0x013D, sbUpper},
+	{0x013E, 0x013E, sbLower},
+	{0x013F, 0x013F, sbUpper},
+	{0x0140, 0x0140, sbLower},
+	{0x0141, 0x0141, sbUpper},
+	{0x0142, 0x0142, sbLower},
+	{0x0143, 0x0143, sbUpper},
+	{0x0144, 0x0144, sbLower},
+	{0x0145, 0x0145, sbUpper},
+	{0x0146, 0x0146, sbLower},
+	{0x0147, 0x0147, sbUpper},
+	{0x0148, 0x0149, sbLower},
+	{0x014A, 0x014A, sbUpper},
+	{0x014B, 0x014B, sbLower},
+	{0x014C, 0x014C, sbUpper},
+	{0x014D, 0x014D, sbLower},
+	{0x014E, 0x014E, sbUpper},
+	{0x014F, 0x014F, sbLower},
+	{0x0150, 0x0150, sbUpper},
+	{0x0151, 0x0151, sbLower},
+	{0x0152, 0x0152, sbUpper},
+	{0x0153, 0x0153, sbLower},
+	{0x0154, 0x0154, sbUpper},
+	{0x0155, 0x0155, sbLower},
+	{0x0156, 0x0156, sbUpper},
+	{0x0157, 0x0157, sbLower},
+	{0x0158, 0x0158, sbUpper},
+	{0x0159, 0x0159, sbLower},
+	{0x015A, 0x015A, sbUpper},
+	{0x015B, 0x015B, sbLower},
+	{0x015C, 0x015C, sbUpper},
+	{0x015D, 0x015D, sbLower},
+	{0x015E, 0x015E, sbUpper},
+	{0x015F, 0x015F, sbLower},
+	{0x0160, 0x0160, sbUpper},
+	{0x0161, 0x0161, sbLower},
+	{0x0162, 0x0162, sbUpper},
+	{0x0163, 0x0163, sbLower},
+	{0x0164, 0x0164, sbUpper},
+	{0x0165, 0x0165, sbLower},
+	{0x0166, 0x0166, sbUpper},
+	{0x0167, 0x0167, sbLower},
+	{0x0168, 0x0168, sbUpper},
+	{0x0169, 0x0169, sbLower},
+	{0x016A, 0x016A, sbUpper},
+	{0x016B, 0x016B, sbLower},
+	{0x016C, 0x016C, sbUpper},
+	{0x016D, 0x016D, sbLower},
+	{0x016E, 0x016E, sbUpper},
+	{0x016F, 0x016F, sbLower},
+	{0x0170, 0x0170, sbUpper},
+	{0x0171, 0x0171, sbLower},
+	{0x0172, 0x0172, sbUpper},
+	{0x0173, 0x0173, sbLower},
+	{0x0174, 0x0174, sbUpper},
+	{0x0175, 0x0175, sbLower},
+	{0x0176, 0x0176, sbUpper},
+	{0x0177, 0x0177, sbLower},
+	{0x0178, 0x0179, sbUpper},
+	{0x017A, 0x017A, sbLower},
+	{0x017B, 0x017B, sbUpper},
+	{0x017C, 0x017C, sbLower},
+	{0x017D, 0x017D, sbUpper},
+	{0x017E, 0x0180, sbLower},
+	{0x0181, 0x0182, sbUpper},
+	{0x0183, 0x0183, sbLower},
+	{0x0184, 0x0184, sbUpper},
+	{0x0185, 0x0185, sbLower},
+	{0x0186, 0x0187, sbUpper},
+	{0x0188, 0x0188, sbLower},
+	{0x0189, 0x018B, sbUpper},
+	{0x018C, 0x018D, sbLower},
+	{0x018E, 0x0191, sbUpper},
+	{0x0192, 0x0192, sbLower},
+	{0x0193, 0x0194, sbUpper},
+	{0x0195, 0x0195, sbLower},
+	{0x0196, 0x0198, sbUpper},
+	{0x0199, 0x019B, sbLower},
+	{0x019C, 0x019D, sbUpper},
+	{0x019E, 0x019E, sbLower},
+	{0x019F, 0x01A0, sbUpper},
+	{0x01A1, 0x01A1, sbLower},
+	{0x01A2, 0x01A2, sbUpper},
+	{0x01A3, 0x01A3, sbLower},
+	{0x01A4, 0x01A4, sbUpper},
+	{0x01A5, 0x01A5, sbLower},
+	{0x01A6, 0x01A7, sbUpper},
+	{0x01A8, 0x01A8, sbLower},
+	{0x01A9, 0x01A9, sbUpper},
+	{0x01AA, 0x01AB, sbLower},
+	{0x01AC, 0x01AC, sbUpper},
+	{0x01AD, 0x01AD, sbLower},
+	{0x01AE, 0x01AF, sbUpper},
+	{0x01B0, 0x01B0, sbLower},
+	{0x01B1, 0x01B3, sbUpper},
+	{0x01B4, 0x01B4, sbLower},
+	{0x01B5, 0x01B5, sbUpper},
+	{0x01B6, 0x01B6, sbLower},
+	{0x01B7, 0x01B8, sbUpper},
+	{0x01B9, 0x01BA, sbLower},
+	{0x01BB, 0x01BB, sbOLetter},
+	{0x01BC, 0x01BC, sbUpper},
+	{0x01BD, 0x01BF, sbLower},
+	{0x01C0, 0x01C3, sbOLetter},
+	{0x01C4, 0x01C5, sbUpper},
+	{0x01C6, 0x01C6, sbLower},
+	{0x01C7, 0x01C8, sbUpper},
+	{0x01C9, 0x01C9, sbLower},
+	{0x01CA, 0x01CB, sbUpper},
+	{0x01CC, 0x01CC, sbLower},
+	{0x01CD, 0x01CD, sbUpper},
+	{0x01CE, 0x01CE, sbLower},
+	{0x01CF, 0x01CF, sbUpper},
+	{0x01D0, 0x01D0, sbLower},
+	{0x01D1, 0x01D1, sbUpper},
+	{0x01D2, 0x01D2, sbLower},
+	{0x01D3, 0x01D3, sbUpper},
+	{0x01D4, 0x01D4, sbLower},
+	{0x01D5, 0x01D5, sbUpper},
+	{0x01D6, 0x01D6, sbLower},
+	{0x01D7, 0x01D7, sbUpper},
+	{0x01D8, 0x01D8, sbLower},
+	{0x01D9, 0x01D9, sbUpper},
+	{0x01DA, 0x01DA, sbLower},
+	{0x01DB, 0x01DB, sbUpper},
+	{0x01DC, 0x01DD, sbLower},
+	{0x01DE, 0x01DE, sbUpper},
+	{0x01DF, 0x01DF, sbLower},
+	{0x01E0, 0x01E0, sbUpper},
+	{0x01E1, 0x01E1, sbLower},
+	{0x01E2, 0x01E2, sbUpper},
+	{0x01E3, 0x01E3, sbLower},
+	{0x01E4, 0x01E4, sbUpper},
+	{0x01E5, 0x01E5, sbLower},
+	{0x01E6, 0x01E6, sbUpper},
+	{0x01E7, 0x01E7, sbLower},
+	{0x01E8, 0x01E8, sbUpper},
+	{0x01E9, 0x01E9, sbLower},
+	{0x01EA, 0x01EA, sbUpper},
+	{0x01EB, 0x01EB, sbLower},
+	{0x01EC, 0x01EC, sbUpper},
+	{0x01ED, 0x01ED, sbLower},
+	{0x01EE, 0x01EE, sbUpper},
+	{0x01EF, 0x01F0, sbLower},
+	{0x01F1, 0x01F2, sbUpper},
+	{0x01F3, 0x01F3, sbLower},
+	{0x01F4, 0x01F4, sbUpper},
+	{0x01F5, 0x01F5, sbLower},
+	{0x01F6, 0x01F8, sbUpper},
+	{0x01F9, 0x01F9, sbLower},
+	{0x01FA, 0x01FA, sbUpper},
+	{0x01FB, 0x01FB, sbLower},
+	{0x01FC, 0x01FC, sbUpper},
+	{0x01FD, 0x01FD, sbLower},
+	{0x01FE, 0x01FE, sbUpper},
+	{0x01FF, 0x01FF, sbLower},
+	{0x0200, 0x0200, sbUpper},
+	{0x0201, 0x0201, sbLower},
+	{0x0202, 0x0202, sbUpper},
+	{0x0203, 0x0203, sbLower},
+	{0x0204, 0x0204, sbUpper},
+	{0x0205, 0x0205, sbLower},
+	{0x0206, 0x0206, sbUpper},
+	{0x0207, 0x0207, sbLower},
+	{0x0208, 0x0208, sbUpper},
+	{0x0209, 0x0209, sbLower},
+	{0x020A, 0x020A, sbUpper},
+	{0x020B, 0x020B, sbLower},
+	{0x020C, 0x020C, sbUpper},
+	{0x020D, 0x020D, sbLower},
+	{0x020E, 0x020E, sbUpper},
+	{0x020F, 0x020F, sbLower},
+	{0x0210, 0x0210, sbUpper},
+	{0x0211, 0x0211, sbLower},
+	{0x0212, 0x0212, sbUpper},
+	{0x0213, 0x0213, sbLower},
+	{0x0214, 0x0214, sbUpper},
+	{0x0215, 0x0215, sbLower},
+	{0x0216, 0x0216, sbUpper},
+	{0x0217, 0x0217, sbLower},
+	{0x0218, 0x0218, sbUpper},
+	{0x0219, 0x0219, sbLower},
+	{0x021A, 0x021A, sbUpper},
+	{0x021B, 0x021B, sbLower},
+	{0x021C, 0x021C, sbUpper},
+	{0x021D, 0x021D, sbLower},
+	{0x021E, 0x021E, sbUpper},
+	{0x021F, 0x021F, sbLower},
+	{0x0220, 0x0220, sbUpper},
+	{0x0221, 0x0221, sbLower},
+	{0x0222, 0x0222, sbUpper},
+	{0x0223, 0x0223, sbLower},
+	{0x0224, 0x0224, sbUpper},
+	{0x0225, 0x0225, sbLower},
+	{0x0226, 0x0226, sbUpper},
+	{0x0227, 0x0227, sbLower},
+	{0x0228, 0x0228, sbUpper},
+	{0x0229, 0x0229, sbLower},
+	{0x022A, 0x022A, sbUpper},
+	{0x022B, 0x022B, sbLower},
+	{0x022C, 0x022C, sbUpper},
+	{0x022D, 0x022D, sbLower},
+	{0x022E, 0x022E, sbUpper},
+	{0x022F, 0x022F, sbLower},
+	{0x0230, 0x0230, sbUpper},
+	{0x0231, 0x0231, sbLower},
+	{0x0232, 0x0232, sbUpper},
+	{0x0233, 0x0239, sbLower},
+	{0x023A, 0x023B, sbUpper},
+	{0x023C, 0x023C, sbLower},
+	{0x023D, 0x023E, sbUpper},
+	{0x023F, 0x0240, sbLower},
+	{0x0241, 0x0241, sbUpper},
+	{0x0242, 0x0242, sbLower},
+	{0x0243, 0x0246, sbUpper},
+	{0x0247, 0x0247, sbLower},
+	{0x0248, 0x0248, sbUpper},
+	{0x0249, 0x0249, sbLower},
+	{0x024A, 0x024A, sbUpper},
+	{0x024B, 0x024B, sbLower},
+	{0x024C, 0x024C, sbUpper},
+	{0x024D, 0x024D, sbLower},
+	{0x024E, 0x024E, sbUpper},
+	{0x024F, 0x0293, sbLower},
+	{0x0294, 0x0294, sbOLetter},
+	{0x0295, 0x02B8, sbLower},
+	{0x02B9, 0x02BF, sbOLetter},
+	{0x02C0, 0x02C1, sbLower},
+	{0x02C6, 0x02D1, sbOLetter},
+	{0x02E0, 0x02E4, sbLower},
+	{0x02EC, 0x02EC, sbOLetter},
+	{0x02EE, 0x02EE, sbOLetter},
+	{0x0300, 0x036F, sbExtend},
+	{0x0370, 0x0370, sbUpper},
+	{0x0371, 0x0371, sbLower},
+	{0x0372, 0x0372, sbUpper},
+	{0x0373, 0x0373, sbLower},
+	{0x0374, 0x0374, sbOLetter},
+	{0x0376, 0x0376, sbUpper},
+	{0x0377, 0x0377, sbLower},
+	{0x037A, 0x037D, sbLower},
+	{0x037F, 0x037F, sbUpper},
+	{0x0386, 0x0386, sbUpper},
+	{0x0388, 0x038A, sbUpper},
+	{0x038C, 0x038C, sbUpper},
+	{0x038E, 0x038F, sbUpper},
+	{0x0390, 0x0390, sbLower},
+	{0x0391, 0x03A1, sbUpper},
+	{0x03A3, 0x03AB, sbUpper},
+	{0x03AC, 0x03CE, sbLower},
+	{0x03CF, 0x03CF, sbUpper},
+	{0x03D0, 0x03D1, sbLower},
+	{0x03D2, 0x03D4, sbUpper},
+	{0x03D5, 0x03D7, sbLower},
+	{0x03D8, 0x03D8, sbUpper},
+	{0x03D9, 0x03D9, sbLower},
+	{0x03DA, 0x03DA, sbUpper},
+	{0x03DB, 0x03DB, sbLower},
+	{0x03DC, 0x03DC, sbUpper},
+	{0x03DD, 0x03DD, sbLower},
+	{0x03DE, 0x03DE, sbUpper},
+	{0x03DF, 0x03DF, sbLower},
+	{0x03E0, 0x03E0, sbUpper},
+	{0x03E1, 0x03E1, sbLower},
+	{0x03E2, 0x03E2, sbUpper},
+	{0x03E3, 0x03E3, sbLower},
+	{0x03E4, 0x03E4, sbUpper},
+	{0x03E5, 0x03E5, sbLower},
+	{0x03E6, 0x03E6, sbUpper},
+	{0x03E7, 0x03E7, sbLower},
+	{0x03E8, 0x03E8, sbUpper},
+	{0x03E9, 0x03E9, sbLower},
+	{0x03EA, 0x03EA, sbUpper},
+	{0x03EB, 0x03EB, sbLower},
+	{0x03EC, 0x03EC, sbUpper},
+	{0x03ED, 0x03ED, sbLower},
+	{0x03EE, 0x03EE, sbUpper},
+	{0x03EF, 0x03F3, sbLower},
+	{0x03F4, 0x03F4, sbUpper},
+	{0x03F5, 0x03F5, sbLower},
+	{0x03F7, 0x03F7, sbUpper},
+	{0x03F8, 0x03F8, sbLower},
+	{0x03F9, 0x03FA, sbUpper},
+	{0x03FB, 0x03FC, sbLower},
+	{0x03FD, 0x042F, sbUpper},
+	{0x0430, 0x045F, sbLower},
+	{0x0460, 0x0460, sbUpper},
+	{0x0461, 0x0461, sbLower},
+	{0x0462, 0x0462, sbUpper},
+	{0x0463, 0x0463, sbLower},
+	{0x0464, 0x0464, sbUpper},
+	{0x0465, 0x0465, sbLower},
+	{0x0466, 0x0466, sbUpper},
+	{0x0467, 0x0467, sbLower},
+	{0x0468, 0x0468, sbUpper},
+	{0x0469, 0x0469, sbLower},
+	{0x046A, 0x046A, sbUpper},
+	{0x046B, 0x046B, sbLower},
+	{0x046C, 0x046C, sbUpper},
+	{0x046D, 0x046D, sbLower},
+	{0x046E, 0x046E, sbUpper},
+	{0x046F, 0x046F, sbLower},
+	{0x0470, 0x0470, sbUpper},
+	{0x0471, 0x0471, sbLower},
+	{0x0472, 0x0472, sbUpper},
+	{0x0473, 0x0473, sbLower},
+	{0x0474, 0x0474, sbUpper},
+	{0x0475, 0x0475, sbLower},
+	{0x0476, 0x0476, sbUpper},
+	{0x0477, 0x0477, sbLower},
+	{0x0478, 0x0478, sbUpper},
+	{0x0479, 0x0479, sbLower},
+	{0x047A, 0x047A, sbUpper},
+	{0x047B, 0x047B, sbLower},
+	{0x047C, 0x047C, sbUpper},
+	{0x047D, 0x047D, sbLower},
+	{0x047E, 0x047E, sbUpper},
+	{0x047F, 0x047F, sbLower},
+	{0x0480, 0x0480, sbUpper},
+	{0x0481, 0x0481, sbLower},
+	{0x0483, 0x0489, sbExtend},
+	{0x048A, 0x048A, sbUpper},
+	{0x048B, 0x048B, sbLower},
+	{0x048C, 0x048C, sbUpper},
+	{0x048D, 0x048D, sbLower},
+	{0x048E, 0x048E, sbUpper},
+	{0x048F, 0x048F, sbLower},
+	{0x0490, 0x0490, sbUpper},
+	{0x0491, 0x0491, sbLower},
+	{0x0492, 0x0492, sbUpper},
+	{0x0493, 0x0493, sbLower},
+	{0x0494, 0x0494, sbUpper},
+	{0x0495, 0x0495, sbLower},
+	{0x0496, 0x0496, sbUpper},
+	{0x0497, 0x0497, sbLower},
+	{0x0498, 0x0498, sbUpper},
+	{0x0499, 0x0499, sbLower},
+	{0x049A, 0x049A, sbUpper},
+	{0x049B, 0x049B, sbLower},
+	{0x049C, 0x049C, sbUpper},
+	{0x049D, 0x049D, sbLower},
+	{0x049E, 0x049E, sbUpper},
+	{0x049F, 0x049F, sbLower},
+	{0x04A0, 0x04A0, sbUpper},
+	{0x04A1, 0x04A1, sbLower},
+	{0x04A2, 0x04A2, sbUpper},
+	{0x04A3, 0x04A3, sbLower},
+	{0x04A4, 0x04A4, sbUpper},
+	{0x04A5, 0x04A5, sbLower},
+	{0x04A6, 0x04A6, sbUpper},
+	{0x04A7, 0x04A7, sbLower},
+	{0x04A8, 0x04A8, sbUpper},
+	{0x04A9, 0x04A9, sbLower},
+	{0x04AA, 0x04AA, sbUpper},
+	{0x04AB, 0x04AB, sbLower},
+	{0x04AC, 0x04AC, sbUpper},
+	{0x04AD, 0x04AD, sbLower},
+	{0x04AE, 0x04AE, sbUpper},
+	{0x04AF, 0x04AF, sbLower},
+	{0x04B0, 0x04B0, sbUpper},
+	{0x04B1, 0x04B1, sbLower},
+	{0x04B2, 0x04B2, sbUpper},
+	{0x04B3, 0x04B3, sbLower},
+	{0x04B4, 0x04B4, sbUpper},
+	{0x04B5, 0x04B5, sbLower},
+	{0x04B6, 0x04B6, sbUpper},
+	{0x04B7, 0x04B7, sbLower},
+	{0x04B8, 0x04B8, sbUpper},
+	{0x04B9, 0x04B9, sbLower},
+	{0x04BA, 0x04BA, sbUpper},
+	{0x04BB, 0x04BB, sbLower},
+	{0x04BC, 0x04BC, sbUpper},
+	{0x04BD, 0x04BD, sbLower},
+	{0x04BE, 0x04BE, sbUpper},
+	{0x04BF, 0x04BF, sbLower},
+	{0x04C0, 0x04C1, sbUpper},
+	{0x04C2, 0x04C2, sbLower},
+	{0x04C3, 0x04C3, sbUpper},
+	{0x04C4, 0x04C4, sbLower},
+	{0x04C5, 0x04C5, sbUpper},
+	{0x04C6, 0x04C6, sbLower},
+	{0x04C7, 0x04C7, sbUpper},
+	{0x04C8, 0x04C8, sbLower},
+	{0x04C9, 0x04C9, sbUpper},
+	{0x04CA, 0x04CA, sbLower},
+	{0x04CB, 0x04CB, sbUpper},
+	{0x04CC, 0x04CC, sbLower},
+	{0x04CD, 0x04CD, sbUpper},
+	{0x04CE, 0x04CF, sbLower},
+	{0x04D0, 0x04D0, sbUpper},
+	{0x04D1, 0x04D1, sbLower},
+	{0x04D2, 0x04D2, sbUpper},
+	{0x04D3, 0x04D3, sbLower},
+	{0x04D4, 0x04D4, sbUpper},
+	{0x04D5, 0x04D5, sbLower},
+	{0x04D6, 0x04D6, sbUpper},
+	{0x04D7, 0x04D7, sbLower},
+	{0x04D8, 0x04D8, sbUpper},
+	{0x04D9, 0x04D9, sbLower},
+	{0x04DA, 0x04DA, sbUpper},
+	{0x04DB, 0x04DB, sbLower},
+	{0x04DC, 0x04DC, sbUpper},
+	{0x04DD, 0x04DD, sbLower},
+	{0x04DE, 0x04DE, sbUpper},
+	{0x04DF, 0x04DF, sbLower},
+	{0x04E0, 0x04E0, sbUpper},
+	{0x04E1, 0x04E1, sbLower},
+	{0x04E2, 0x04E2, sbUpper},
+	{0x04E3, 0x04E3, sbLower},
+	{0x04E4, 0x04E4, sbUpper},
+	{0x04E5, 0x04E5, sbLower},
+	{0x04E6, 0x04E6, sbUpper},
+	{0x04E7, 0x04E7, sbLower},
+	{0x04E8, 0x04E8, sbUpper},
+	{0x04E9, 0x04E9, sbLower},
+	{0x04EA, 0x04EA, sbUpper},
+	{0x04EB, 0x04EB, sbLower},
+	{0x04EC, 0x04EC, sbUpper},
+	{0x04ED, 0x04ED, sbLower},
+	{0x04EE, 0x04EE, sbUpper},
+	{0x04EF, 0x04EF, sbLower},
+	{0x04F0, 0x04F0, sbUpper},
+	{0x04F1, 0x04F1, sbLower},
+	{0x04F2, 0x04F2, sbUpper},
+	{0x04F3, 0x04F3, sbLower},
+	{0x04F4, 0x04F4, sbUpper},
+	{0x04F5, 0x04F5, sbLower},
+	{0x04F6, 0x04F6, sbUpper},
+	{0x04F7, 0x04F7, sbLower},
+	{0x04F8, 0x04F8, sbUpper},
+	{0x04F9, 0x04F9, sbLower},
+	{0x04FA, 0x04FA, sbUpper},
+	{0x04FB, 0x04FB, sbLower},
+	{0x04FC, 0x04FC, sbUpper},
+	{0x04FD, 0x04FD, sbLower},
+	{0x04FE, 0x04FE, sbUpper},
+	{0x04FF, 0x04FF, sbLower},
+	{0x0500, 0x0500, sbUpper},
+	{0x0501, 0x0501, sbLower},
+	{0x0502, 0x0502, sbUpper},
+	{0x0503, 0x0503, sbLower},
+	{0x0504, 0x0504, sbUpper},
+	{0x0505, 0x0505, sbLower},
+	{0x0506, 0x0506, sbUpper},
+	{0x0507, 0x0507, sbLower},
+	{0x0508, 0x0508, sbUpper},
+	{0x0509, 0x0509, sbLower},
+	{0x050A, 0x050A, sbUpper},
+	{0x050B, 0x050B, sbLower},
+	{0x050C, 0x050C, sbUpper},
+	{0x050D, 0x050D, sbLower},
+	{0x050E, 0x050E, sbUpper},
+	{0x050F, 0x050F, sbLower},
+	{0x0510, 0x0510, sbUpper},
+	{0x0511, 0x0511, sbLower},
+	{0x0512, 0x0512, sbUpper},
+	{0x0513, 0x0513, sbLower},
+	{0x0514, 0x0514, sbUpper},
+	{0x0515, 0x0515, sbLower},
+	{0x0516, 0x0516, sbUpper},
+	{0x0517, 0x0517, sbLower},
+	{0x0518, 0x0518, sbUpper},
+	{0x0519, 0x0519, sbLower},
+	{0x051A, 0x051A, sbUpper},
+	{0x051B, 0x051B, sbLower},
+	{0x051C, 0x051C, sbUpper},
+	{0x051D, 0x051D, sbLower},
+	{0x051E, 0x051E, sbUpper},
+	{0x051F, 0x051F, sbLower},
+	{0x0520, 0x0520, sbUpper},
+	{0x0521, 0x0521, sbLower},
+	{0x0522, 0x0522, sbUpper},
+	{0x0523, 0x0523, sbLower},
+	{0x0524, 0x0524, sbUpper},
+	{0x0525, 0x0525, sbLower},
+	{0x0526, 0x0526, sbUpper},
+	{0x0527, 0x0527, sbLower},
+	{0x0528, 0x0528, sbUpper},
+	{0x0529, 0x0529, sbLower},
+	{0x052A, 0x052A, sbUpper},
+	{0x052B, 0x052B, sbLower},
+	{0x052C, 0x052C, sbUpper},
+	{0x052D, 0x052D, sbLower},
+	{0x052E, 0x052E, sbUpper},
+	{0x052F, 0x052F, sbLower},
+	{0x0531, 0x0556, sbUpper},
+	{0x0559, 0x0559, sbOLetter},
+	{0x055D, 0x055D, sbSContinue},
+	{0x0560, 0x0588, sbLower},
+	{0x0589, 0x0589, sbSTerm},
+	{0x0591, 0x05BD, sbExtend},
+	{0x05BF, 0x05BF, sbExtend},
+	{0x05C1, 0x05C2, sbExtend},
+	{0x05C4, 0x05C5, sbExtend},
+	{0x05C7, 0x05C7, sbExtend},
+	{0x05D0, 0x05EA, sbOLetter},
+	{0x05EF, 0x05F3, sbOLetter},
+	{0x0600, 0x0605, sbFormat},
+	{0x060C, 0x060D, sbSContinue},
+	{0x0610, 0x061A, sbExtend},
+	{0x061C, 0x061C, sbFormat},
+	{0x061D, 0x061F, sbSTerm},
+	{0x0620, 0x064A, sbOLetter},
+	{0x064B, 0x065F, sbExtend},
+	{0x0660, 0x0669, sbNumeric},
+	{0x066B, 0x066C, sbNumeric},
+	{0x066E, 0x066F, sbOLetter},
+	{0x0670, 0x0670, sbExtend},
+	{0x0671, 0x06D3, sbOLetter},
+	{0x06D4, 0x06D4, sbSTerm},
+	{0x06D5, 0x06D5, sbOLetter},
+	{0x06D6, 0x06DC, sbExtend},
+	{0x06DD, 0x06DD, sbFormat},
+	{0x06DF, 0x06E4, sbExtend},
+	{0x06E5, 0x06E6, sbOLetter},
+	{0x06E7, 0x06E8, sbExtend},
+	{0x06EA, 0x06ED, sbExtend},
+	{0x06EE, 0x06EF, sbOLetter},
+	{0x06F0, 0x06F9, sbNumeric},
+	{0x06FA, 0x06FC, sbOLetter},
+	{0x06FF, 0x06FF, sbOLetter},
+	{0x0700, 0x0702, sbSTerm},
+	{0x070F, 0x070F, sbFormat},
+	{0x0710, 0x0710, sbOLetter},
+	{0x0711, 0x0711, sbExtend},
+	{0x0712, 0x072F, sbOLetter},
+	{0x0730, 0x074A, sbExtend},
+	{0x074D, 0x07A5, sbOLetter},
+	{0x07A6, 0x07B0, sbExtend},
+	{0x07B1, 0x07B1, sbOLetter},
+	{0x07C0, 0x07C9, sbNumeric},
+	{0x07CA, 0x07EA, sbOLetter},
+	{0x07EB, 0x07F3, sbExtend},
+	{0x07F4, 0x07F5, sbOLetter},
+	{0x07F8, 0x07F8, sbSContinue},
+	{0x07F9, 0x07F9, sbSTerm},
+	{0x07FA, 0x07FA, sbOLetter},
+	{0x07FD, 0x07FD, sbExtend},
+	{0x0800, 0x0815, sbOLetter},
+	{0x0816, 0x0819, sbExtend},
+	{0x081A, 0x081A, sbOLetter},
+	{0x081B, 0x0823, sbExtend},
+	{0x0824, 0x0824, sbOLetter},
+	{0x0825, 0x0827, sbExtend},
+	{0x0828, 0x0828, sbOLetter},
+	{0x0829, 0x082D, sbExtend},
+	{0x0837, 0x0837, sbSTerm},
+	{0x0839, 0x0839, sbSTerm},
+	{0x083D, 0x083E, sbSTerm},
+	{0x0840, 0x0858, sbOLetter},
+	{0x0859, 0x085B, sbExtend},
+	{0x0860, 0x086A, sbOLetter},
+	{0x0870, 0x0887, sbOLetter},
+	{0x0889, 0x088E, sbOLetter},
+	{0x0890, 0x0891, sbFormat},
+	{0x0898, 0x089F, sbExtend},
+	{0x08A0, 0x08C9, sbOLetter},
+	{0x08CA, 0x08E1, sbExtend},
+	{0x08E2, 0x08E2, sbFormat},
+	{0x08E3, 0x0903, sbExtend},
+	{0x0904, 0x0939, sbOLetter},
+	{0x093A, 0x093C, sbExtend},
+	{0x093D, 0x093D, sbOLetter},
+	{0x093E, 0x094F, sbExtend},
+	{0x0950, 0x0950, sbOLetter},
+	{0x0951, 0x0957, sbExtend},
+	{0x0958, 0x0961, sbOLetter},
+	{0x0962, 0x0963, sbExtend},
+	{0x0964, 0x0965, sbSTerm},
+	{0x0966, 0x096F, sbNumeric},
+	{0x0971, 0x0980, sbOLetter},
+	{0x0981, 0x0983, sbExtend},
+	{0x0985, 0x098C, sbOLetter},
+	{0x098F, 0x0990, sbOLetter},
+	{0x0993, 0x09A8, sbOLetter},
+	{0x09AA, 0x09B0, sbOLetter},
+	{0x09B2, 0x09B2, sbOLetter},
+	{0x09B6, 0x09B9, sbOLetter},
+	{0x09BC, 0x09BC, sbExtend},
+	{0x09BD, 0x09BD, sbOLetter},
+	{0x09BE, 0x09C4, sbExtend},
+	{0x09C7, 0x09C8, sbExtend},
+	{0x09CB, 0x09CD, sbExtend},
+	{0x09CE, 0x09CE, sbOLetter},
+	{0x09D7, 0x09D7, sbExtend},
+	{0x09DC, 0x09DD, sbOLetter},
+	{0x09DF, 0x09E1, sbOLetter},
+	{0x09E2, 0x09E3, sbExtend},
+	{0x09E6, 0x09EF, sbNumeric},
+	{0x09F0, 0x09F1, sbOLetter},
+	{0x09FC, 0x09FC, sbOLetter},
+	{0x09FE, 0x09FE, sbExtend},
+	{0x0A01, 0x0A03, sbExtend},
+	{0x0A05, 0x0A0A, sbOLetter},
+	{0x0A0F, 0x0A10, sbOLetter},
+	{0x0A13, 0x0A28, sbOLetter},
+	{0x0A2A, 0x0A30, sbOLetter},
+	{0x0A32, 0x0A33, sbOLetter},
+	{0x0A35, 0x0A36, sbOLetter},
+	{0x0A38, 0x0A39, sbOLetter},
+	{0x0A3C, 0x0A3C, sbExtend},
+	{0x0A3E, 0x0A42, sbExtend},
+	{0x0A47, 0x0A48, sbExtend},
+	{0x0A4B, 0x0A4D, sbExtend},
+	{0x0A51, 0x0A51, sbExtend},
+	{0x0A59, 0x0A5C, sbOLetter},
+	{0x0A5E, 0x0A5E, sbOLetter},
+	{0x0A66, 0x0A6F, sbNumeric},
+	{0x0A70, 0x0A71, sbExtend},
+	{0x0A72, 0x0A74, sbOLetter},
+	{0x0A75, 0x0A75, sbExtend},
+	{0x0A81, 0x0A83, sbExtend},
+	{0x0A85, 0x0A8D, sbOLetter},
+	{0x0A8F, 0x0A91, sbOLetter},
+	{0x0A93, 0x0AA8, sbOLetter},
+	{0x0AAA, 0x0AB0, sbOLetter},
+	{0x0AB2, 0x0AB3, sbOLetter},
+	{0x0AB5, 0x0AB9, sbOLetter},
+	{0x0ABC, 0x0ABC, sbExtend},
+	{0x0ABD, 0x0ABD, sbOLetter},
+	{0x0ABE, 0x0AC5, sbExtend},
+	{0x0AC7, 0x0AC9, sbExtend},
+	{0x0ACB, 0x0ACD, sbExtend},
+	{0x0AD0, 0x0AD0, sbOLetter},
+	{0x0AE0, 0x0AE1, sbOLetter},
+	{0x0AE2, 0x0AE3, sbExtend},
+	{0x0AE6, 0x0AEF, sbNumeric},
+	{0x0AF9, 0x0AF9, sbOLetter},
+	{0x0AFA, 0x0AFF, sbExtend},
+	{0x0B01, 0x0B03, sbExtend},
+	{0x0B05, 0x0B0C, sbOLetter},
+	{0x0B0F, 0x0B10, sbOLetter},
+	{0x0B13, 0x0B28, sbOLetter},
+	{0x0B2A, 0x0B30, sbOLetter},
+	{0x0B32, 0x0B33, sbOLetter},
+	{0x0B35, 0x0B39, sbOLetter},
+	{0x0B3C, 0x0B3C, sbExtend},
+	{0x0B3D, 0x0B3D, sbOLetter},
+	{0x0B3E, 0x0B44, sbExtend},
+	{0x0B47, 0x0B48, sbExtend},
+	{0x0B4B, 0x0B4D, sbExtend},
+	{0x0B55, 0x0B57, sbExtend},
+	{0x0B5C, 0x0B5D, sbOLetter},
+	{0x0B5F, 0x0B61, sbOLetter},
+	{0x0B62, 0x0B63, sbExtend},
+	{0x0B66, 0x0B6F, sbNumeric},
+	{0x0B71, 0x0B71, sbOLetter},
+	{0x0B82, 0x0B82, sbExtend},
+	{0x0B83, 0x0B83, sbOLetter},
+	{0x0B85, 0x0B8A, sbOLetter},
+	{0x0B8E, 0x0B90, sbOLetter},
+	{0x0B92, 0x0B95, sbOLetter},
+	{0x0B99, 0x0B9A, sbOLetter},
+	{0x0B9C, 0x0B9C, sbOLetter},
+	{0x0B9E, 0x0B9F, sbOLetter},
+	{0x0BA3, 0x0BA4, sbOLetter},
+	{0x0BA8, 0x0BAA, sbOLetter},
+	{0x0BAE, 0x0BB9, sbOLetter},
+	{0x0BBE, 0x0BC2, sbExtend},
+	{0x0BC6, 0x0BC8, sbExtend},
+	{0x0BCA, 0x0BCD, sbExtend},
+	{0x0BD0, 0x0BD0, sbOLetter},
+	{0x0BD7, 0x0BD7, sbExtend},
+	{0x0BE6, 0x0BEF, sbNumeric},
+	{0x0C00, 0x0C04, sbExtend},
+	{0x0C05, 0x0C0C, sbOLetter},
+	{0x0C0E, 0x0C10, sbOLetter},
+	{0x0C12, 0x0C28, sbOLetter},
+	{0x0C2A, 0x0C39, sbOLetter},
+	{0x0C3C, 0x0C3C, sbExtend},
+	{0x0C3D, 0x0C3D, sbOLetter},
+	{0x0C3E, 0x0C44, sbExtend},
+	{0x0C46, 0x0C48, sbExtend},
+	{0x0C4A, 0x0C4D, sbExtend},
+	{0x0C55, 0x0C56, sbExtend},
+	{0x0C58, 0x0C5A, sbOLetter},
+	{0x0C5D, 0x0C5D, sbOLetter},
+	{0x0C60, 0x0C61, sbOLetter},
+	{0x0C62, 0x0C63, sbExtend},
+	{0x0C66, 0x0C6F, sbNumeric},
+	{0x0C80, 0x0C80, sbOLetter},
+	{0x0C81, 0x0C83, sbExtend},
+	{0x0C85, 0x0C8C, sbOLetter},
+	{0x0C8E, 0x0C90, sbOLetter},
+	{0x0C92, 0x0CA8, sbOLetter},
+	{0x0CAA, 0x0CB3, sbOLetter},
+	{0x0CB5, 0x0CB9, sbOLetter},
+	{0x0CBC, 0x0CBC, sbExtend},
+	{0x0CBD, 0x0CBD, sbOLetter},
+	{0x0CBE, 0x0CC4, sbExtend},
+	{0x0CC6, 0x0CC8, sbExtend},
+	{0x0CCA, 0x0CCD, sbExtend},
+	{0x0CD5, 0x0CD6, sbExtend},
+	{0x0CDD, 0x0CDE, sbOLetter},
+	{0x0CE0, 0x0CE1, sbOLetter},
+	{0x0CE2, 0x0CE3, sbExtend},
+	{0x0CE6, 0x0CEF, sbNumeric},
+	{0x0CF1, 0x0CF2, sbOLetter},
+	{0x0CF3, 0x0CF3, sbExtend},
+	{0x0D00, 0x0D03, sbExtend},
+	{0x0D04, 0x0D0C, sbOLetter},
+	{0x0D0E, 0x0D10, sbOLetter},
+	{0x0D12, 0x0D3A, sbOLetter},
+	{0x0D3B, 0x0D3C, sbExtend},
+	{0x0D3D, 0x0D3D, sbOLetter},
+	{0x0D3E, 0x0D44, sbExtend},
+	{0x0D46, 0x0D48, sbExtend},
+	{0x0D4A, 0x0D4D, sbExtend},
+	{0x0D4E, 0x0D4E, sbOLetter},
+	{0x0D54, 0x0D56, sbOLetter},
+	{0x0D57, 0x0D57, sbExtend},
+	{0x0D5F, 0x0D61, sbOLetter},
+	{0x0D62, 0x0D63, sbExtend},
+	{0x0D66, 0x0D6F, sbNumeric},
+	{0x0D7A, 0x0D7F, sbOLetter},
+	{0x0D81, 0x0D83, sbExtend},
+	{0x0D85, 0x0D96, sbOLetter},
+	{0x0D9A, 0x0DB1, sbOLetter},
+	{0x0DB3, 0x0DBB, sbOLetter},
+	{0x0DBD, 0x0DBD, sbOLetter},
+	{0x0DC0, 0x0DC6, sbOLetter},
+	{0x0DCA, 0x0DCA, sbExtend},
+	{0x0DCF, 0x0DD4, sbExtend},
+	{0x0DD6, 0x0DD6, sbExtend},
+	{0x0DD8, 0x0DDF, sbExtend},
+	{0x0DE6, 0x0DEF, sbNumeric},
+	{0x0DF2, 0x0DF3, sbExtend},
+	{0x0E01, 0x0E30, sbOLetter},
+	{0x0E31, 0x0E31, sbExtend},
+	{0x0E32, 0x0E33, sbOLetter},
+	{0x0E34, 0x0E3A, sbExtend},
+	{0x0E40, 0x0E46, sbOLetter},
+	{0x0E47, 0x0E4E, sbExtend},
+	{0x0E50, 0x0E59, sbNumeric},
+	{0x0E81, 0x0E82, sbOLetter},
+	{0x0E84, 0x0E84, sbOLetter},
+	{0x0E86, 0x0E8A, sbOLetter},
+	{0x0E8C, 0x0EA3, sbOLetter},
+	{0x0EA5, 0x0EA5, sbOLetter},
+	{0x0EA7, 0x0EB0, sbOLetter},
+	{0x0EB1, 0x0EB1, sbExtend},
+	{0x0EB2, 0x0EB3, sbOLetter},
+	{0x0EB4, 0x0EBC, sbExtend},
+	{0x0EBD, 0x0EBD, sbOLetter},
+	{0x0EC0, 0x0EC4, sbOLetter},
+	{0x0EC6, 0x0EC6, sbOLetter},
+	{0x0EC8, 0x0ECE, sbExtend},
+	{0x0ED0, 0x0ED9, sbNumeric},
+	{0x0EDC, 0x0EDF, sbOLetter},
+	{0x0F00, 0x0F00, sbOLetter},
+	{0x0F18, 0x0F19, sbExtend},
+	{0x0F20, 0x0F29, sbNumeric},
+	{0x0F35, 0x0F35, sbExtend},
+	{0x0F37, 0x0F37, sbExtend},
+	{0x0F39, 0x0F39, sbExtend},
+	{0x0F3A, 0x0F3D, sbClose},
+	{0x0F3E, 0x0F3F, sbExtend},
+	{0x0F40, 0x0F47, sbOLetter},
+	{0x0F49, 0x0F6C, sbOLetter},
+	{0x0F71, 0x0F84, sbExtend},
+	{0x0F86, 0x0F87, sbExtend},
+	{0x0F88, 0x0F8C, sbOLetter},
+	{0x0F8D, 0x0F97, sbExtend},
+	{0x0F99, 0x0FBC, sbExtend},
+	{0x0FC6, 0x0FC6, sbExtend},
+	{0x1000, 0x102A, sbOLetter},
+	{0x102B, 0x103E, sbExtend},
+	{0x103F, 0x103F, sbOLetter},
+	{0x1040, 0x1049, sbNumeric},
+	{0x104A, 0x104B, sbSTerm},
+	{0x1050, 0x1055, sbOLetter},
+	{0x1056, 0x1059, sbExtend},
+	{0x105A, 0x105D, sbOLetter},
+	{0x105E, 0x1060, sbExtend},
+	{0x1061, 0x1061, sbOLetter},
+	{0x1062, 0x1064, sbExtend},
+	{0x1065, 0x1066, sbOLetter},
+	{0x1067, 0x106D, sbExtend},
+	{0x106E, 0x1070, sbOLetter},
+	{0x1071, 0x1074, sbExtend},
+	{0x1075, 0x1081, sbOLetter},
+	{0x1082, 0x108D, sbExtend},
+	{0x108E, 0x108E, sbOLetter},
+	{0x108F, 0x108F, sbExtend},
+	{0x1090, 0x1099, sbNumeric},
+	{0x109A, 0x109D, sbExtend},
+	{0x10A0, 0x10C5, sbUpper},
+	{0x10C7, 0x10C7, sbUpper},
+	{0x10CD, 0x10CD, sbUpper},
+	{0x10D0, 0x10FA, sbOLetter},
+	{0x10FC, 0x10FC, sbLower},
+	{0x10FD, 0x1248, sbOLetter},
+	{0x124A, 0x124D, sbOLetter},
+	{0x1250, 0x1256, sbOLetter},
+	{0x1258, 0x1258, sbOLetter},
+	{0x125A, 0x125D, sbOLetter},
+	{0x1260, 0x1288, sbOLetter},
+	{0x128A, 0x128D, sbOLetter},
+	{0x1290, 0x12B0, sbOLetter},
+	{0x12B2, 0x12B5, sbOLetter},
+	{0x12B8, 0x12BE, sbOLetter},
+	{0x12C0, 0x12C0, sbOLetter},
+	{0x12C2, 0x12C5, sbOLetter},
+	{0x12C8, 0x12D6, sbOLetter},
+	{0x12D8, 0x1310, sbOLetter},
+	{0x1312, 0x1315, sbOLetter},
+	{0x1318, 0x135A, sbOLetter},
+	{0x135D, 0x135F, sbExtend},
+	{0x1362, 0x1362, sbSTerm},
+	{0x1367, 0x1368, sbSTerm},
+	{0x1380, 0x138F, sbOLetter},
+	{0x13A0, 0x13F5, sbUpper},
+	{0x13F8, 0x13FD, sbLower},
+	{0x1401, 0x166C, sbOLetter},
+	{0x166E, 0x166E, sbSTerm},
+	{0x166F, 0x167F, sbOLetter},
+	{0x1680, 0x1680, sbSp},
+	{0x1681, 0x169A, sbOLetter},
+	{0x169B, 0x169C, sbClose},
+	{0x16A0, 0x16EA, sbOLetter},
+	{0x16EE, 0x16F8, sbOLetter},
+	{0x1700, 0x1711, sbOLetter},
+	{0x1712, 0x1715, sbExtend},
+	{0x171F, 0x1731, sbOLetter},
+	{0x1732, 0x1734, sbExtend},
+	{0x1735, 0x1736, sbSTerm},
+	{0x1740, 0x1751, sbOLetter},
+	{0x1752, 0x1753, sbExtend},
+	{0x1760, 0x176C, sbOLetter},
+	{0x176E, 0x1770, sbOLetter},
+	{0x1772, 0x1773, sbExtend},
+	{0x1780, 0x17B3, sbOLetter},
+	{0x17B4, 0x17D3, sbExtend},
+	{0x17D7, 0x17D7, sbOLetter},
+	{0x17DC, 0x17DC, sbOLetter},
+	{0x17DD, 0x17DD, sbExtend},
+	{0x17E0, 0x17E9, sbNumeric},
+	{0x1802, 0x1802, sbSContinue},
+	{0x1803, 0x1803, sbSTerm},
+	{0x1808, 0x1808, sbSContinue},
+	{0x1809, 0x1809, sbSTerm},
+	{0x180B, 0x180D, sbExtend},
+	{0x180E, 0x180E, sbFormat},
+	{0x180F, 0x180F, sbExtend},
+	{0x1810, 0x1819, sbNumeric},
+	{0x1820, 0x1878, sbOLetter},
+	{0x1880, 0x1884, sbOLetter},
+	{0x1885, 0x1886, sbExtend},
+	{0x1887, 0x18A8, sbOLetter},
+	{0x18A9, 0x18A9, sbExtend},
+	{0x18AA, 0x18AA, sbOLetter},
+	{0x18B0, 0x18F5, sbOLetter},
+	{0x1900, 0x191E, sbOLetter},
+	{0x1920, 0x192B, sbExtend},
+	{0x1930, 0x193B, sbExtend},
+	{0x1944, 0x1945, sbSTerm},
+	{0x1946, 0x194F, sbNumeric},
+	{0x1950, 0x196D, sbOLetter},
+	{0x1970, 0x1974, sbOLetter},
+	{0x1980, 0x19AB, sbOLetter},
+	{0x19B0, 0x19C9, sbOLetter},
+	{0x19D0, 0x19D9, sbNumeric},
+	{0x1A00, 0x1A16, sbOLetter},
+	{0x1A17, 0x1A1B, sbExtend},
+	{0x1A20, 0x1A54, sbOLetter},
+	{0x1A55, 0x1A5E, sbExtend},
+	{0x1A60, 0x1A7C, sbExtend},
+	{0x1A7F, 0x1A7F, sbExtend},
+	{0x1A80, 0x1A89, sbNumeric},
+	{0x1A90, 0x1A99, sbNumeric},
+	{0x1AA7, 0x1AA7, sbOLetter},
+	{0x1AA8, 0x1AAB, sbSTerm},
+	{0x1AB0, 0x1ACE, sbExtend},
+	{0x1B00, 0x1B04, sbExtend},
+	{0x1B05, 0x1B33, sbOLetter},
+	{0x1B34, 0x1B44, sbExtend},
+	{0x1B45, 0x1B4C, sbOLetter},
+	{0x1B50, 0x1B59, sbNumeric},
+	{0x1B5A, 0x1B5B, sbSTerm},
+	{0x1B5E, 0x1B5F, sbSTerm},
+	{0x1B6B, 0x1B73, sbExtend},
+	{0x1B7D, 0x1B7E, sbSTerm},
+	{0x1B80, 0x1B82, sbExtend},
+	{0x1B83, 0x1BA0, sbOLetter},
+	{0x1BA1, 0x1BAD, sbExtend},
+	{0x1BAE, 0x1BAF, sbOLetter},
+	{0x1BB0, 0x1BB9, sbNumeric},
+	{0x1BBA, 0x1BE5, sbOLetter},
+	{0x1BE6, 0x1BF3, sbExtend},
+	{0x1C00, 0x1C23, sbOLetter},
+	{0x1C24, 0x1C37, sbExtend},
+	{0x1C3B, 0x1C3C, sbSTerm},
+	{0x1C40, 0x1C49, sbNumeric},
+	{0x1C4D, 0x1C4F, sbOLetter},
+	{0x1C50, 0x1C59, sbNumeric},
+	{0x1C5A, 0x1C7D, sbOLetter},
+	{0x1C7E, 0x1C7F, sbSTerm},
+	{0x1C80, 0x1C88, sbLower},
+	{0x1C90, 0x1CBA, sbOLetter},
+	{0x1CBD, 0x1CBF, sbOLetter},
+	{0x1CD0, 0x1CD2, sbExtend},
+	{0x1CD4, 0x1CE8, sbExtend},
+	{0x1CE9, 0x1CEC, sbOLetter},
+	{0x1CED, 0x1CED, sbExtend},
+	{0x1CEE, 0x1CF3, sbOLetter},
+	{0x1CF4, 0x1CF4, sbExtend},
+	{0x1CF5, 0x1CF6, sbOLetter},
+	{0x1CF7, 0x1CF9, sbExtend},
+	{0x1CFA, 0x1CFA, sbOLetter},
+	{0x1D00, 0x1DBF, sbLower},
+	{0x1DC0, 0x1DFF, sbExtend},
+	{0x1E00, 0x1E00, sbUpper},
+	{0x1E01, 0x1E01, sbLower},
+	{0x1E02, 0x1E02, sbUpper},
+	{0x1E03, 0x1E03, sbLower},
+	{0x1E04, 0x1E04, sbUpper},
+	{0x1E05, 0x1E05, sbLower},
+	{0x1E06, 0x1E06, sbUpper},
+	{0x1E07, 0x1E07, sbLower},
+	{0x1E08, 0x1E08, sbUpper},
+	{0x1E09, 0x1E09, sbLower},
+	{0x1E0A, 0x1E0A, sbUpper},
+	{0x1E0B, 0x1E0B, sbLower},
+	{0x1E0C, 0x1E0C, sbUpper},
+	{0x1E0D, 0x1E0D, sbLower},
+	{0x1E0E, 0x1E0E, sbUpper},
+	{0x1E0F, 0x1E0F, sbLower},
+	{0x1E10, 0x1E10, sbUpper},
+	{0x1E11, 0x1E11, sbLower},
+	{0x1E12, 0x1E12, sbUpper},
+	{0x1E13, 0x1E13, sbLower},
+	{0x1E14, 0x1E14, sbUpper},
+	{0x1E15, 0x1E15, sbLower},
+	{0x1E16, 0x1E16, sbUpper},
+	{0x1E17, 0x1E17, sbLower},
+	{0x1E18, 0x1E18, sbUpper},
+	{0x1E19, 0x1E19, sbLower},
+	{0x1E1A, 0x1E1A, sbUpper},
+	{0x1E1B, 0x1E1B, sbLower},
+	{0x1E1C, 0x1E1C, sbUpper},
+	{0x1E1D, 0x1E1D, sbLower},
+	{0x1E1E, 0x1E1E, sbUpper},
+	{0x1E1F, 0x1E1F, sbLower},
+	{0x1E20, 0x1E20, sbUpper},
+	{0x1E21, 0x1E21, sbLower},
+	{0x1E22, 0x1E22, sbUpper},
+	{0x1E23, 0x1E23, sbLower},
+	{0x1E24, 0x1E24, sbUpper},
+	{0x1E25, 0x1E25, sbLower},
+	{0x1E26, 0x1E26, sbUpper},
+	{0x1E27, 0x1E27, sbLower},
+	{0x1E28, 0x1E28, sbUpper},
+	{0x1E29, 0x1E29, sbLower},
+	{0x1E2A, 0x1E2A, sbUpper},
+	{0x1E2B, 0x1E2B, sbLower},
+	{0x1E2C, 0x1E2C, sbUpper},
+	{0x1E2D, 0x1E2D, sbLower},
+	{0x1E2E, 0x1E2E, sbUpper},
+	{0x1E2F, 0x1E2F, sbLower},
+	{0x1E30, 0x1E30, sbUpper},
+	{0x1E31, 0x1E31, sbLower},
+	{0x1E32, 0x1E32, sbUpper},
+	{0x1E33, 0x1E33, sbLower},
+	{0x1E34, 0x1E34, sbUpper},
+	{0x1E35, 0x1E35, sbLower},
+	{0x1E36, 0x1E36, sbUpper},
+	{0x1E37, 0x1E37, sbLower},
+	{0x1E38, 0x1E38, sbUpper},
+	{0x1E39, 0x1E39, sbLower},
+	{0x1E3A, 0x1E3A, sbUpper},
+	{0x1E3B, 0x1E3B, sbLower},
+	{0x1E3C, 0x1E3C, sbUpper},
+	{0x1E3D, 0x1E3D, sbLower},
+	{0x1E3E, 0x1E3E, sbUpper},
+	{0x1E3F, 0x1E3F, sbLower},
+	{0x1E40, 0x1E40, sbUpper},
+	{0x1E41, 0x1E41, sbLower},
+	{0x1E42, 0x1E42, sbUpper},
+	{0x1E43, 0x1E43, sbLower},
+	{0x1E44, 0x1E44, sbUpper},
+	{0x1E45, 0x1E45, sbLower},
+	{0x1E46, 0x1E46, sbUpper},
+	{0x1E47, 0x1E47, sbLower},
+	{0x1E48, 0x1E48, sbUpper},
+	{0x1E49, 0x1E49, sbLower},
+	{0x1E4A, 0x1E4A, sbUpper},
+	{0x1E4B, 0x1E4B, sbLower},
+	{0x1E4C, 0x1E4C, sbUpper},
+	{0x1E4D, 0x1E4D, sbLower},
+	{0x1E4E, 0x1E4E, sbUpper},
+	{0x1E4F, 0x1E4F, sbLower},
+	{0x1E50, 0x1E50, sbUpper},
+	{0x1E51, 0x1E51, sbLower},
+	{0x1E52, 0x1E52, sbUpper},
+	{0x1E53, 0x1E53, sbLower},
+	{0x1E54, 0x1E54, sbUpper},
+	{0x1E55, 0x1E55, sbLower},
+	{0x1E56, 0x1E56, sbUpper},
+	{0x1E57, 0x1E57, sbLower},
+	{0x1E58, 0x1E58, sbUpper},
+	{0x1E59, 0x1E59, sbLower},
+	{0x1E5A, 0x1E5A, sbUpper},
+	{0x1E5B, 0x1E5B, sbLower},
+	{0x1E5C, 0x1E5C, sbUpper},
+	{0x1E5D, 0x1E5D, sbLower},
+	{0x1E5E, 0x1E5E, sbUpper},
+	{0x1E5F, 0x1E5F, sbLower},
+	{0x1E60, 0x1E60, sbUpper},
+	{0x1E61, 0x1E61, sbLower},
+	{0x1E62, 0x1E62, sbUpper},
+	{0x1E63, 0x1E63, sbLower},
+	{0x1E64, 0x1E64, sbUpper},
+	{0x1E65, 0x1E65, sbLower},
+	{0x1E66, 0x1E66, sbUpper},
+	{0x1E67, 0x1E67, sbLower},
+	{0x1E68, 0x1E68, sbUpper},
+	{0x1E69, 0x1E69, sbLower},
+	{0x1E6A, 0x1E6A, sbUpper},
+	{0x1E6B, 0x1E6B, sbLower},
+	{0x1E6C, 0x1E6C, sbUpper},
+	{0x1E6D, 0x1E6D, sbLower},
+	{0x1E6E, 0x1E6E, sbUpper},
+	{0x1E6F, 0x1E6F, sbLower},
+	{0x1E70, 0x1E70, sbUpper},
+	{0x1E71, 0x1E71, sbLower},
+	{0x1E72, 0x1E72, sbUpper},
+	{0x1E73, 0x1E73, sbLower},
+	{0x1E74, 0x1E74, sbUpper},
+	{0x1E75, 0x1E75, sbLower},
+	{0x1E76, 0x1E76, sbUpper},
+	{0x1E77, 0x1E77, sbLower},
+	{0x1E78, 0x1E78, sbUpper},
+	{0x1E79, 0x1E79, sbLower},
+	{0x1E7A, 0x1E7A, sbUpper},
+	{0x1E7B, 0x1E7B, sbLower},
+	{0x1E7C, 0x1E7C, sbUpper},
+	{0x1E7D, 0x1E7D, sbLower},
+	{0x1E7E, 0x1E7E, sbUpper},
+	{0x1E7F, 0x1E7F, sbLower},
+	{0x1E80, 0x1E80, sbUpper},
+	{0x1E81, 0x1E81, sbLower},
+	{0x1E82, 0x1E82, sbUpper},
+	{0x1E83, 0x1E83, sbLower},
+	{0x1E84, 0x1E84, sbUpper},
+	{0x1E85, 0x1E85, sbLower},
+	{0x1E86, 0x1E86, sbUpper},
+	{0x1E87, 0x1E87, sbLower},
+	{0x1E88, 0x1E88, sbUpper},
+	{0x1E89, 0x1E89, sbLower},
+	{0x1E8A, 0x1E8A, sbUpper},
+	{0x1E8B, 0x1E8B, sbLower},
+	{0x1E8C, 0x1E8C, sbUpper},
+	{0x1E8D, 0x1E8D, sbLower},
+	{0x1E8E, 0x1E8E, sbUpper},
+	{0x1E8F, 0x1E8F, sbLower},
+	{0x1E90, 0x1E90, sbUpper},
+	{0x1E91, 0x1E91, sbLower},
+	{0x1E92, 0x1E92, sbUpper},
+	{0x1E93, 0x1E93, sbLower},
+	{0x1E94, 0x1E94, sbUpper},
+	{0x1E95, 0x1E9D, sbLower},
+	{0x1E9E, 0x1E9E, sbUpper},
+	{0x1E9F, 0x1E9F, sbLower},
+	{0x1EA0, 0x1EA0, sbUpper},
+	{0x1EA1, 0x1EA1, sbLower},
+	{0x1EA2, 0x1EA2, sbUpper},
+	{0x1EA3, 0x1EA3, sbLower},
+	{0x1EA4, 0x1EA4, sbUpper},
+	{0x1EA5, 0x1EA5, sbLower},
+	{0x1EA6, 0x1EA6, sbUpper},
+	{0x1EA7, 0x1EA7, sbLower},
+	{0x1EA8, 0x1EA8, sbUpper},
+	{0x1EA9, 0x1EA9, sbLower},
+	{0x1EAA, 0x1EAA, sbUpper},
+	{0x1EAB, 0x1EAB, sbLower},
+	{0x1EAC, 0x1EAC, sbUpper},
+	{0x1EAD, 0x1EAD, sbLower},
+	{0x1EAE, 0x1EAE, sbUpper},
+	{0x1EAF, 0x1EAF, sbLower},
+	{0x1EB0, 0x1EB0, sbUpper},
+	{0x1EB1, 0x1EB1, sbLower},
+	{0x1EB2, 0x1EB2, sbUpper},
+	{0x1EB3, 0x1EB3, sbLower},
+	{0x1EB4, 0x1EB4, sbUpper},
+	{0x1EB5, 0x1EB5, sbLower},
+	{0x1EB6, 0x1EB6, sbUpper},
+	{0x1EB7, 0x1EB7, sbLower},
+	{0x1EB8, 0x1EB8, sbUpper},
+	{0x1EB9, 0x1EB9, sbLower},
+	{0x1EBA, 0x1EBA, sbUpper},
+	{0x1EBB, 0x1EBB, sbLower},
+	{0x1EBC, 0x1EBC, sbUpper},
+	{0x1EBD, 0x1EBD, sbLower},
+	{0x1EBE, 0x1EBE, sbUpper},
+	{0x1EBF, 0x1EBF, sbLower},
+	{0x1EC0, 0x1EC0, sbUpper},
+	{0x1EC1, 0x1EC1, sbLower},
+	{0x1EC2, 0x1EC2, sbUpper},
+	{0x1EC3, 0x1EC3, sbLower},
+	{0x1EC4, 0x1EC4, sbUpper},
+	{0x1EC5, 0x1EC5, sbLower},
+	{0x1EC6, 0x1EC6, sbUpper},
+	{0x1EC7, 0x1EC7, sbLower},
+	{0x1EC8, 0x1EC8, sbUpper},
+	{0x1EC9, 0x1EC9, sbLower},
+	{0x1ECA, 0x1ECA, sbUpper},
+	{0x1ECB, 0x1ECB, sbLower},
+	{0x1ECC, 0x1ECC, sbUpper},
+	{0x1ECD, 0x1ECD, sbLower},
+	{0x1ECE, 0x1ECE, sbUpper},
+	{0x1ECF, 0x1ECF, sbLower},
+	{0x1ED0, 0x1ED0, sbUpper},
+	{0x1ED1, 0x1ED1, sbLower},
+	{0x1ED2, 0x1ED2, sbUpper},
+	{0x1ED3, 0x1ED3, sbLower},
+	{0x1ED4, 0x1ED4, sbUpper},
+	{0x1ED5, 0x1ED5, sbLower},
+	{0x1ED6, 0x1ED6, sbUpper},
+	{0x1ED7, 0x1ED7, sbLower},
+	{0x1ED8, 0x1ED8, sbUpper},
+	{0x1ED9, 0x1ED9, sbLower},
+	{0x1EDA, 0x1EDA, sbUpper},
+	{0x1EDB, 0x1EDB, sbLower},
+	{0x1EDC, 0x1EDC, sbUpper},
+	{0x1EDD, 0x1EDD, sbLower},
+	{0x1EDE, 0x1EDE, sbUpper},
+	{0x1EDF, 0x1EDF, sbLower},
+	{0x1EE0, 0x1EE0, sbUpper},
+	{0x1EE1, 0x1EE1, sbLower},
+	{0x1EE2, 0x1EE2, sbUpper},
+	{0x1EE3, 0x1EE3, sbLower},
+	{0x1EE4, 0x1EE4, sbUpper},
+	{0x1EE5, 0x1EE5, sbLower},
+	{0x1EE6, 0x1EE6, sbUpper},
+	{0x1EE7, 0x1EE7, sbLower},
+	{0x1EE8, 0x1EE8, sbUpper},
+	{0x1EE9, 0x1EE9, sbLower},
+	{0x1EEA, 0x1EEA, sbUpper},
+	{0x1EEB, 0x1EEB, sbLower},
+	{0x1EEC, 0x1EEC, sbUpper},
+	{0x1EED, 0x1EED, sbLower},
+	{0x1EEE, 0x1EEE, sbUpper},
+	{0x1EEF, 0x1EEF, sbLower},
+	{0x1EF0, 0x1EF0, sbUpper},
+	{0x1EF1, 0x1EF1, sbLower},
+	{0x1EF2, 0x1EF2, sbUpper},
+	{0x1EF3, 0x1EF3, sbLower},
+	{0x1EF4, 0x1EF4, sbUpper},
+	{0x1EF5, 0x1EF5, sbLower},
+	{0x1EF6, 0x1EF6, sbUpper},
+	{0x1EF7, 0x1EF7, sbLower},
+	{0x1EF8, 0x1EF8, sbUpper},
+	{0x1EF9, 0x1EF9, sbLower},
+	{0x1EFA, 0x1EFA, sbUpper},
+	{0x1EFB, 0x1EFB, sbLower},
+	{0x1EFC, 0x1EFC, sbUpper},
+	{0x1EFD, 0x1EFD, sbLower},
+	{0x1EFE, 0x1EFE, sbUpper},
+	{0x1EFF, 0x1F07, sbLower},
+	{0x1F08, 0x1F0F, sbUpper},
+	{0x1F10, 0x1F15, sbLower},
+	{0x1F18, 0x1F1D, sbUpper},
+	{0x1F20, 0x1F27, sbLower},
+	{0x1F28, 0x1F2F, sbUpper},
+	{0x1F30, 0x1F37, sbLower},
+	{0x1F38, 0x1F3F, sbUpper},
+	{0x1F40, 0x1F45, sbLower},
+	{0x1F48, 0x1F4D, sbUpper},
+	{0x1F50, 0x1F57, sbLower},
+	{0x1F59, 0x1F59, sbUpper},
+	{0x1F5B, 0x1F5B, sbUpper},
+	{0x1F5D, 0x1F5D, sbUpper},
+	{0x1F5F, 0x1F5F, sbUpper},
+	{0x1F60, 0x1F67, sbLower},
+	{0x1F68, 0x1F6F, sbUpper},
+	{0x1F70, 0x1F7D, sbLower},
+	{0x1F80, 0x1F87, sbLower},
+	{0x1F88, 0x1F8F, sbUpper},
+	{0x1F90, 0x1F97, sbLower},
+	{0x1F98, 0x1F9F, sbUpper},
+	{0x1FA0, 0x1FA7, sbLower},
+	{0x1FA8, 0x1FAF, sbUpper},
+	{0x1FB0, 0x1FB4, sbLower},
+	{0x1FB6, 0x1FB7, sbLower},
+	{0x1FB8, 0x1FBC, sbUpper},
+	{0x1FBE, 0x1FBE, sbLower},
+	{0x1FC2, 0x1FC4, sbLower},
+	{0x1FC6, 0x1FC7, sbLower},
+	{0x1FC8, 0x1FCC, sbUpper},
+	{0x1FD0, 0x1FD3, sbLower},
+	{0x1FD6, 0x1FD7, sbLower},
+	{0x1FD8, 0x1FDB, sbUpper},
+	{0x1FE0, 0x1FE7, sbLower},
+	{0x1FE8, 0x1FEC, sbUpper},
+	{0x1FF2, 0x1FF4, sbLower},
+	{0x1FF6, 0x1FF7, sbLower},
+	{0x1FF8, 0x1FFC, sbUpper},
+	{0x2000, 0x200A, sbSp},
+	{0x200B, 0x200B, sbFormat},
+	{0x200C, 0x200D, sbExtend},
+	{0x200E, 0x200F, sbFormat},
+	{0x2013, 0x2014, sbSContinue},
+	{0x2018, 0x201F, sbClose},
+	{0x2024, 0x2024, sbATerm},
+	{0x2028, 0x2029, sbSep},
+	{0x202A, 0x202E, sbFormat},
+	{0x202F, 0x202F, sbSp},
+	{0x2039, 0x203A, sbClose},
+	{0x203C, 0x203D, sbSTerm},
+	{0x2045, 0x2046, sbClose},
+	{0x2047, 0x2049, sbSTerm},
+	{0x205F, 0x205F, sbSp},
+	{0x2060, 0x2064, sbFormat},
+	{0x2066, 0x206F, sbFormat},
+	{0x2071, 0x2071, sbLower},
+	{0x207D, 0x207E, sbClose},
+	{0x207F, 0x207F, sbLower},
+	{0x208D, 0x208E, sbClose},
+	{0x2090, 0x209C, sbLower},
+	{0x20D0, 0x20F0, sbExtend},
+	{0x2102, 0x2102, sbUpper},
+	{0x2107, 0x2107, sbUpper},
+	{0x210A, 0x210A, sbLower},
+	{0x210B, 0x210D, sbUpper},
+	{0x210E, 0x210F, sbLower},
+	{0x2110, 0x2112, sbUpper},
+	{0x2113, 0x2113, sbLower},
+	{0x2115, 0x2115, sbUpper},
+	{0x2119, 0x211D, sbUpper},
+	{0x2124, 0x2124, sbUpper},
+	{0x2126, 0x2126, sbUpper},
+	{0x2128, 0x2128, sbUpper},
+	{0x212A, 0x212D, sbUpper},
+	{0x212F, 0x212F, sbLower},
+	{0x2130, 0x2133, sbUpper},
+	{0x2134, 0x2134, sbLower},
+	{0x2135, 0x2138, sbOLetter},
+	{0x2139, 0x2139, sbLower},
+	{0x213C, 0x213D, sbLower},
+	{0x213E, 0x213F, sbUpper},
+	{0x2145, 0x2145, sbUpper},
+	{0x2146, 0x2149, sbLower},
+	{0x214E, 0x214E, sbLower},
+	{0x2160, 0x216F, sbUpper},
+	{0x2170, 0x217F, sbLower},
+	{0x2180, 0x2182, sbOLetter},
+	{0x2183, 0x2183, sbUpper},
+	{0x2184, 0x2184, sbLower},
+	{0x2185, 0x2188, sbOLetter},
+	{0x2308, 0x230B, sbClose},
+	{0x2329, 0x232A, sbClose},
+	{0x24B6, 0x24CF, sbUpper},
+	{0x24D0, 0x24E9, sbLower},
+	{0x275B, 0x2760, sbClose},
+	{0x2768, 0x2775, sbClose},
+	{0x27C5, 0x27C6, sbClose},
+	{0x27E6, 0x27EF, sbClose},
+	{0x2983, 0x2998, sbClose},
+	{0x29D8, 0x29DB, sbClose},
+	{0x29FC, 0x29FD, sbClose},
+	{0x2C00, 0x2C2F, sbUpper},
+	{0x2C30, 0x2C5F, sbLower},
+	{0x2C60, 0x2C60, sbUpper},
+	{0x2C61, 0x2C61, sbLower},
+	{0x2C62, 0x2C64, sbUpper},
+	{0x2C65, 0x2C66, sbLower},
+	{0x2C67, 0x2C67, sbUpper},
+	{0x2C68, 0x2C68, sbLower},
+	{0x2C69, 0x2C69, sbUpper},
+	{0x2C6A, 0x2C6A, sbLower},
+	{0x2C6B, 0x2C6B, sbUpper},
+	{0x2C6C, 0x2C6C, sbLower},
+	{0x2C6D, 0x2C70, sbUpper},
+	{0x2C71, 0x2C71, sbLower},
+	{0x2C72, 0x2C72, sbUpper},
+	{0x2C73, 0x2C74, sbLower},
+	{0x2C75, 0x2C75, sbUpper},
+	{0x2C76, 0x2C7D, sbLower},
+	{0x2C7E, 0x2C80, sbUpper},
+	{0x2C81, 0x2C81, sbLower},
+	{0x2C82, 0x2C82, sbUpper},
+	{0x2C83, 0x2C83, sbLower},
+	{0x2C84, 0x2C84, sbUpper},
+	{0x2C85, 0x2C85, sbLower},
+	{0x2C86, 0x2C86, sbUpper},
+	{0x2C87, 0x2C87, sbLower},
+	{0x2C88, 0x2C88, sbUpper},
+	{0x2C89, 0x2C89, sbLower},
+	{0x2C8A, 0x2C8A, sbUpper},
+	{0x2C8B, 0x2C8B, sbLower},
+	{0x2C8C, 0x2C8C, sbUpper},
+	{0x2C8D, 0x2C8D, sbLower},
+	{0x2C8E, 0x2C8E, sbUpper},
+	{0x2C8F, 0x2C8F, sbLower},
+	{0x2C90, 0x2C90, sbUpper},
+	{0x2C91, 0x2C91, sbLower},
+	{0x2C92, 0x2C92, sbUpper},
+	{0x2C93, 0x2C93, sbLower},
+	{0x2C94, 0x2C94, sbUpper},
+	{0x2C95, 0x2C95, sbLower},
+	{0x2C96, 0x2C96, sbUpper},
+	{0x2C97, 0x2C97, sbLower},
+	{0x2C98, 0x2C98, sbUpper},
+	{0x2C99, 0x2C99, sbLower},
+	{0x2C9A, 0x2C9A, sbUpper},
+	{0x2C9B, 0x2C9B, sbLower},
+	{0x2C9C, 0x2C9C, sbUpper},
+	{0x2C9D, 0x2C9D, sbLower},
+	{0x2C9E, 0x2C9E, sbUpper},
+	{0x2C9F, 0x2C9F, sbLower},
+	{0x2CA0, 0x2CA0, sbUpper},
+	{0x2CA1, 0x2CA1, sbLower},
+	{0x2CA2, 0x2CA2, sbUpper},
+	{0x2CA3, 0x2CA3, sbLower},
+	{0x2CA4, 0x2CA4, sbUpper},
+	{0x2CA5, 0x2CA5, sbLower},
+	{0x2CA6, 0x2CA6, sbUpper},
+	{0x2CA7, 0x2CA7, sbLower},
+	{0x2CA8, 0x2CA8, sbUpper},
+	{0x2CA9, 0x2CA9, sbLower},
+	{0x2CAA, 0x2CAA, sbUpper},
+	{0x2CAB, 0x2CAB, sbLower},
+	{0x2CAC, 0x2CAC, sbUpper},
+	{0x2CAD, 0x2CAD, sbLower},
+	{0x2CAE, 0x2CAE, sbUpper},
+	{0x2CAF, 0x2CAF, sbLower},
+	{0x2CB0, 0x2CB0, sbUpper},
+	{0x2CB1, 0x2CB1, sbLower},
+	{0x2CB2, 0x2CB2, sbUpper},
+	{0x2CB3, 0x2CB3, sbLower},
+	{0x2CB4, 0x2CB4, sbUpper},
+	{0x2CB5, 0x2CB5, sbLower},
+	{0x2CB6, 0x2CB6, sbUpper},
+	{0x2CB7, 0x2CB7, sbLower},
+	{0x2CB8, 0x2CB8, sbUpper},
+	{0x2CB9, 0x2CB9, sbLower},
+	{0x2CBA, 0x2CBA, sbUpper},
+	{0x2CBB, 0x2CBB, sbLower},
+	{0x2CBC, 0x2CBC, sbUpper},
+	{0x2CBD, 0x2CBD, sbLower},
+	{0x2CBE, 0x2CBE, sbUpper},
+	{0x2CBF, 0x2CBF, sbLower},
+	{0x2CC0, 0x2CC0, sbUpper},
+	{0x2CC1, 0x2CC1, sbLower},
+	{0x2CC2, 0x2CC2, sbUpper},
+	{0x2CC3, 0x2CC3, sbLower},
+	{0x2CC4, 0x2CC4, sbUpper},
+	{0x2CC5, 0x2CC5, sbLower},
+	{0x2CC6, 0x2CC6, sbUpper},
+	{0x2CC7, 0x2CC7, sbLower},
+	{0x2CC8, 0x2CC8, sbUpper},
+	{0x2CC9, 0x2CC9, sbLower},
+	{0x2CCA, 0x2CCA, sbUpper},
+	{0x2CCB, 0x2CCB, sbLower},
+	{0x2CCC, 0x2CCC, sbUpper},
+	{0x2CCD, 0x2CCD, sbLower},
+	{0x2CCE, 0x2CCE, sbUpper},
+	{0x2CCF, 0x2CCF, sbLower},
+	{0x2CD0, 0x2CD0, sbUpper},
+	{0x2CD1, 0x2CD1, sbLower},
+	{0x2CD2, 0x2CD2, sbUpper},
+	{0x2CD3, 0x2CD3, sbLower},
+	{0x2CD4, 0x2CD4, sbUpper},
+	{0x2CD5, 0x2CD5, sbLower},
+	{0x2CD6, 0x2CD6, sbUpper},
+	{0x2CD7, 0x2CD7, sbLower},
+	{0x2CD8, 0x2CD8, sbUpper},
+	{0x2CD9, 0x2CD9, sbLower},
+	{0x2CDA, 0x2CDA, sbUpper},
+	{0x2CDB, 0x2CDB, sbLower},
+	{0x2CDC, 0x2CDC, sbUpper},
+	{0x2CDD, 0x2CDD, sbLower},
+	{0x2CDE, 0x2CDE, sbUpper},
+	{0x2CDF, 0x2CDF, sbLower},
+	{0x2CE0, 0x2CE0, sbUpper},
+	{0x2CE1, 0x2CE1, sbLower},
+	{0x2CE2, 0x2CE2, sbUpper},
+	{0x2CE3, 0x2CE4, sbLower},
+	{0x2CEB, 0x2CEB, sbUpper},
+	{0x2CEC, 0x2CEC, sbLower},
+	{0x2CED, 0x2CED, sbUpper},
+	{0x2CEE, 0x2CEE, sbLower},
+	{0x2CEF, 0x2CF1, sbExtend},
+	{0x2CF2, 0x2CF2, sbUpper},
+	{0x2CF3, 0x2CF3, sbLower},
+	{0x2D00, 0x2D25, sbLower},
+	{0x2D27, 0x2D27, sbLower},
+	{0x2D2D, 0x2D2D, sbLower},
+	{0x2D30, 0x2D67, sbOLetter},
+	{0x2D6F, 0x2D6F, sbOLetter},
+	{0x2D7F, 0x2D7F, sbExtend},
+	{0x2D80, 0x2D96, sbOLetter},
+	{0x2DA0, 0x2DA6, sbOLetter},
+	{0x2DA8, 0x2DAE, sbOLetter},
+	{0x2DB0, 0x2DB6, sbOLetter},
+	{0x2DB8, 0x2DBE, sbOLetter},
+	{0x2DC0, 0x2DC6, sbOLetter},
+	{0x2DC8, 0x2DCE, sbOLetter},
+	{0x2DD0, 0x2DD6, sbOLetter},
+	{0x2DD8, 0x2DDE, sbOLetter},
+	{0x2DE0, 0x2DFF, sbExtend},
+	{0x2E00, 0x2E0D, sbClose},
+	{0x2E1C, 0x2E1D, sbClose},
+	{0x2E20, 0x2E29, sbClose},
+	{0x2E2E, 0x2E2E, sbSTerm},
+	{0x2E2F, 0x2E2F, sbOLetter},
+	{0x2E3C, 0x2E3C, sbSTerm},
+	{0x2E42, 0x2E42, sbClose},
+	{0x2E53, 0x2E54, sbSTerm},
+	{0x2E55, 0x2E5C, sbClose},
+	{0x3000, 0x3000, sbSp},
+	{0x3001, 0x3001, sbSContinue},
+	{0x3002, 0x3002, sbSTerm},
+	{0x3005, 0x3007, sbOLetter},
+	{0x3008, 0x3011, sbClose},
+	{0x3014, 0x301B, sbClose},
+	{0x301D, 0x301F, sbClose},
+	{0x3021, 0x3029, sbOLetter},
+	{0x302A, 0x302F, sbExtend},
+	{0x3031, 0x3035, sbOLetter},
+	{0x3038, 0x303C, sbOLetter},
+	{0x3041, 0x3096, sbOLetter},
+	{0x3099, 0x309A, sbExtend},
+	{0x309D, 0x309F, sbOLetter},
+	{0x30A1, 0x30FA, sbOLetter},
+	{0x30FC, 0x30FF, sbOLetter},
+	{0x3105, 0x312F, sbOLetter},
+	{0x3131, 0x318E, sbOLetter},
+	{0x31A0, 0x31BF, sbOLetter},
+	{0x31F0, 0x31FF, sbOLetter},
+	{0x3400, 0x4DBF, sbOLetter},
+	{0x4E00, 0xA48C, sbOLetter},
+	{0xA4D0, 0xA4FD, sbOLetter},
+	{0xA4FF, 0xA4FF, sbSTerm},
+	{0xA500, 0xA60C, sbOLetter},
+	{0xA60E, 0xA60F, sbSTerm},
+	{0xA610, 0xA61F, sbOLetter},
+	{0xA620, 0xA629, sbNumeric},
+	{0xA62A, 0xA62B, sbOLetter},
+	{0xA640, 0xA640, sbUpper},
+	{0xA641, 0xA641, sbLower},
+	{0xA642, 0xA642, sbUpper},
+	{0xA643, 0xA643, sbLower},
+	{0xA644, 0xA644, sbUpper},
+	{0xA645, 0xA645, sbLower},
+	{0xA646, 0xA646, sbUpper},
+	{0xA647, 0xA647, sbLower},
+	{0xA648, 0xA648, sbUpper},
+	{0xA649, 0xA649, sbLower},
+	{0xA64A, 0xA64A, sbUpper},
+	{0xA64B, 0xA64B, sbLower},
+	{0xA64C, 0xA64C, sbUpper},
+	{0xA64D, 0xA64D, sbLower},
+	{0xA64E, 0xA64E, sbUpper},
+	{0xA64F, 0xA64F, sbLower},
+	{0xA650, 0xA650, sbUpper},
+	{0xA651, 0xA651, sbLower},
+	{0xA652, 0xA652, sbUpper},
+	{0xA653, 0xA653, sbLower},
+	{0xA654, 0xA654, sbUpper},
+	{0xA655, 0xA655, sbLower},
+	{0xA656, 0xA656, sbUpper},
+	{0xA657, 0xA657, sbLower},
+	{0xA658, 0xA658, sbUpper},
+	{0xA659, 0xA659, sbLower},
+	{0xA65A, 0xA65A, sbUpper},
+	{0xA65B, 0xA65B, sbLower},
+	{0xA65C, 0xA65C, sbUpper},
+	{0xA65D, 0xA65D, sbLower},
+	{0xA65E, 0xA65E, sbUpper},
+	{0xA65F, 0xA65F, sbLower},
+	{0xA660, 0xA660, sbUpper},
+	{0xA661, 0xA661, sbLower},
+	{0xA662, 0xA662, sbUpper},
+	{0xA663, 0xA663, sbLower},
+	{0xA664, 0xA664, sbUpper},
+	{0xA665, 0xA665, sbLower},
+	{0xA666, 0xA666, sbUpper},
+	{0xA667, 0xA667, sbLower},
+	{0xA668, 0xA668, sbUpper},
+	{0xA669, 0xA669, sbLower},
+	{0xA66A, 0xA66A, sbUpper},
+	{0xA66B, 0xA66B, sbLower},
+	{0xA66C, 0xA66C, sbUpper},
+	{0xA66D, 0xA66D, sbLower},
+	{0xA66E, 0xA66E, sbOLetter},
+	{0xA66F, 0xA672, sbExtend},
+	{0xA674, 0xA67D, sbExtend},
+	{0xA67F, 0xA67F, sbOLetter},
+	{0xA680, 0xA680, sbUpper},
+	{0xA681, 0xA681, sbLower},
+	{0xA682, 0xA682, sbUpper},
+	{0xA683, 0xA683, sbLower},
+	{0xA684, 0xA684, sbUpper},
+	{0xA685, 0xA685, sbLower},
+	{0xA686, 0xA686, sbUpper},
+	{0xA687, 0xA687, sbLower},
+	{0xA688, 0xA688, sbUpper},
+	{0xA689, 0xA689, sbLower},
+	{0xA68A, 0xA68A, sbUpper},
+	{0xA68B, 0xA68B, sbLower},
+	{0xA68C, 0xA68C, sbUpper},
+	{0xA68D, 0xA68D, sbLower},
+	{0xA68E, 0xA68E, sbUpper},
+	{0xA68F, 0xA68F, sbLower},
+	{0xA690, 0xA690, sbUpper},
+	{0xA691, 0xA691, sbLower},
+	{0xA692, 0xA692, sbUpper},
+	{0xA693, 0xA693, sbLower},
+	{0xA694, 0xA694, sbUpper},
+	{0xA695, 0xA695, sbLower},
+	{0xA696, 0xA696, sbUpper},
+	{0xA697, 0xA697, sbLower},
+	{0xA698, 0xA698, sbUpper},
+	{0xA699, 0xA699, sbLower},
+	{0xA69A, 0xA69A, sbUpper},
+	{0xA69B, 0xA69D, sbLower},
+	{0xA69E, 0xA69F, sbExtend},
+	{0xA6A0, 0xA6EF, sbOLetter},
+	{0xA6F0, 0xA6F1, sbExtend},
+	{0xA6F3, 0xA6F3, sbSTerm},
+	{0xA6F7, 0xA6F7, sbSTerm},
+	{0xA717, 0xA71F, sbOLetter},
+	{0xA722, 0xA722, sbUpper},
+	{0xA723, 0xA723, sbLower},
+	{0xA724, 0xA724, sbUpper},
+	{0xA725, 0xA725, sbLower},
+	{0xA726, 0xA726, sbUpper},
+	{0xA727, 0xA727, sbLower},
+	{0xA728, 0xA728, sbUpper},
+	{0xA729, 0xA729, sbLower},
+	{0xA72A, 0xA72A, sbUpper},
+	{0xA72B, 0xA72B, sbLower},
+	{0xA72C, 0xA72C, sbUpper},
+	{0xA72D, 0xA72D, sbLower},
+	{0xA72E, 0xA72E, sbUpper},
+	{0xA72F, 0xA731, sbLower},
+	{0xA732, 0xA732, sbUpper},
+	{0xA733, 0xA733, sbLower},
+	{0xA734, 0xA734, sbUpper},
+	{0xA735, 0xA735, sbLower},
+	{0xA736, 0xA736, sbUpper},
+	{0xA737, 0xA737, sbLower},
+	{0xA738, 0xA738, sbUpper},
+	{0xA739, 0xA739, sbLower},
+	{0xA73A, 0xA73A, sbUpper},
+	{0xA73B, 0xA73B, sbLower},
+	{0xA73C, 0xA73C, sbUpper},
+	{0xA73D, 0xA73D, sbLower},
+	{0xA73E, 0xA73E, sbUpper},
+	{0xA73F, 0xA73F, sbLower},
+	{0xA740, 0xA740, sbUpper},
+	{0xA741, 0xA741, sbLower},
+	{0xA742, 0xA742, sbUpper},
+	{0xA743, 0xA743, sbLower},
+	{0xA744, 0xA744, sbUpper},
+	{0xA745, 0xA745, sbLower},
+	{0xA746, 0xA746, sbUpper},
+	{0xA747, 0xA747, sbLower},
+	{0xA748, 0xA748, sbUpper},
+	{0xA749, 0xA749, sbLower},
+	{0xA74A, 0xA74A, sbUpper},
+	{0xA74B, 0xA74B, sbLower},
+	{0xA74C, 0xA74C, sbUpper},
+	{0xA74D, 0xA74D, sbLower},
+	{0xA74E, 0xA74E, sbUpper},
+	{0xA74F, 0xA74F, sbLower},
+	{0xA750, 0xA750, sbUpper},
+	{0xA751, 0xA751, sbLower},
+	{0xA752, 0xA752, sbUpper},
+	{0xA753, 0xA753, sbLower},
+	{0xA754, 0xA754, sbUpper},
+	{0xA755, 0xA755, sbLower},
+	{0xA756, 0xA756, sbUpper},
+	{0xA757, 0xA757, sbLower},
+	{0xA758, 0xA758, sbUpper},
+	{0xA759, 0xA759, sbLower},
+	{0xA75A, 0xA75A, sbUpper},
+	{0xA75B, 0xA75B, sbLower},
+	{0xA75C, 0xA75C, sbUpper},
+	{0xA75D, 0xA75D, sbLower},
+	{0xA75E, 0xA75E, sbUpper},
+	{0xA75F, 0xA75F, sbLower},
+	{0xA760, 0xA760, sbUpper},
+	{0xA761, 0xA761, sbLower},
+	{0xA762, 0xA762, sbUpper},
+	{0xA763, 0xA763, sbLower},
+	{0xA764, 0xA764, sbUpper},
+	{0xA765, 0xA765, sbLower},
+	{0xA766, 0xA766, sbUpper},
+	{0xA767, 0xA767, sbLower},
+	{0xA768, 0xA768, sbUpper},
+	{0xA769, 0xA769, sbLower},
+	{0xA76A, 0xA76A, sbUpper},
+	{0xA76B, 0xA76B, sbLower},
+	{0xA76C, 0xA76C, sbUpper},
+	{0xA76D, 0xA76D, sbLower},
+	{0xA76E, 0xA76E, sbUpper},
+	{0xA76F, 0xA778, sbLower},
+	{0xA779, 0xA779, sbUpper},
+	{0xA77A, 0xA77A, sbLower},
+	{0xA77B, 0xA77B, sbUpper},
+	{0xA77C, 0xA77C, sbLower},
+	{0xA77D, 0xA77E, sbUpper},
+	{0xA77F, 0xA77F, sbLower},
+	{0xA780, 0xA780, sbUpper},
+	{0xA781, 0xA781, sbLower},
+	{0xA782, 0xA782, sbUpper},
+	{0xA783, 0xA783, sbLower},
+	{0xA784, 0xA784, sbUpper},
+	{0xA785, 0xA785, sbLower},
+	{0xA786, 0xA786, sbUpper},
+	{0xA787, 0xA787, sbLower},
+	{0xA788, 0xA788, sbOLetter},
+	{0xA78B, 0xA78B, sbUpper},
+	{0xA78C, 0xA78C, sbLower},
+	{0xA78D, 0xA78D, sbUpper},
+	{0xA78E, 0xA78E, sbLower},
+	{0xA78F, 0xA78F, sbOLetter},
+	{0xA790, 0xA790, sbUpper},
+	{0xA791, 0xA791, sbLower},
+	{0xA792, 0xA792, sbUpper},
+	{0xA793, 0xA795, sbLower},
+	{0xA796, 0xA796, sbUpper},
+	{0xA797, 0xA797, sbLower},
+	{0xA798, 0xA798, sbUpper},
+	{0xA799, 0xA799, sbLower},
+	{0xA79A, 0xA79A, sbUpper},
+	{0xA79B, 0xA79B, sbLower},
+	{0xA79C, 0xA79C, sbUpper},
+	{0xA79D, 0xA79D, sbLower},
+	{0xA79E, 0xA79E, sbUpper},
+	{0xA79F, 0xA79F, sbLower},
+	{0xA7A0, 0xA7A0, sbUpper},
+	{0xA7A1, 0xA7A1, sbLower},
+	{0xA7A2, 0xA7A2, sbUpper},
+	{0xA7A3, 0xA7A3, sbLower},
+	{0xA7A4, 0xA7A4, sbUpper},
+	{0xA7A5, 0xA7A5, sbLower},
+	{0xA7A6, 0xA7A6, sbUpper},
+	{0xA7A7, 0xA7A7, sbLower},
+	{0xA7A8, 0xA7A8, sbUpper},
+	{0xA7A9, 0xA7A9, sbLower},
+	{0xA7AA, 0xA7AE, sbUpper},
+	{0xA7AF, 0xA7AF, sbLower},
+	{0xA7B0, 0xA7B4, sbUpper},
+	{0xA7B5, 0xA7B5, sbLower},
+	{0xA7B6, 0xA7B6, sbUpper},
+	{0xA7B7, 0xA7B7, sbLower},
+	{0xA7B8, 0xA7B8, sbUpper},
+	{0xA7B9, 0xA7B9, sbLower},
+	{0xA7BA, 0xA7BA, sbUpper},
+	{0xA7BB, 0xA7BB, sbLower},
+	{0xA7BC, 0xA7BC, sbUpper},
+	{0xA7BD, 0xA7BD, sbLower},
+	{0xA7BE, 0xA7BE, sbUpper},
+	{0xA7BF, 0xA7BF, sbLower},
+	{0xA7C0, 0xA7C0, sbUpper},
+	{0xA7C1, 0xA7C1, sbLower},
+	{0xA7C2, 0xA7C2, sbUpper},
+	{0xA7C3, 0xA7C3, sbLower},
+	{0xA7C4, 0xA7C7, sbUpper},
+	{0xA7C8, 0xA7C8, sbLower},
+	{0xA7C9, 0xA7C9, sbUpper},
+	{0xA7CA, 0xA7CA, sbLower},
+	{0xA7D0, 0xA7D0, sbUpper},
+	{0xA7D1, 0xA7D1, sbLower},
+	{0xA7D3, 0xA7D3, sbLower},
+	{0xA7D5, 0xA7D5, sbLower},
+	{0xA7D6, 0xA7D6, sbUpper},
+	{0xA7D7, 0xA7D7, sbLower},
+	{0xA7D8, 0xA7D8, sbUpper},
+	{0xA7D9, 0xA7D9, sbLower},
+	{0xA7F2, 0xA7F4, sbLower},
+	{0xA7F5, 0xA7F5, sbUpper},
+	{0xA7F6, 0xA7F6, sbLower},
+	{0xA7F7, 0xA7F7, sbOLetter},
+	{0xA7F8, 0xA7FA, sbLower},
+	{0xA7FB, 0xA801, sbOLetter},
+	{0xA802, 0xA802, sbExtend},
+	{0xA803, 0xA805, sbOLetter},
+	{0xA806, 0xA806, sbExtend},
+	{0xA807, 0xA80A, sbOLetter},
+	{0xA80B, 0xA80B, sbExtend},
+	{0xA80C, 0xA822, sbOLetter},
+	{0xA823, 0xA827, sbExtend},
+	{0xA82C, 0xA82C, sbExtend},
+	{0xA840, 0xA873, sbOLetter},
+	{0xA876, 0xA877, sbSTerm},
+	{0xA880, 0xA881, sbExtend},
+	{0xA882, 0xA8B3, sbOLetter},
+	{0xA8B4, 0xA8C5, sbExtend},
+	{0xA8CE, 0xA8CF, sbSTerm},
+	{0xA8D0, 0xA8D9, sbNumeric},
+	{0xA8E0, 0xA8F1, sbExtend},
+	{0xA8F2, 0xA8F7, sbOLetter},
+	{0xA8FB, 0xA8FB, sbOLetter},
+	{0xA8FD, 0xA8FE, sbOLetter},
+	{0xA8FF, 0xA8FF, sbExtend},
+	{0xA900, 0xA909, sbNumeric},
+	{0xA90A, 0xA925, sbOLetter},
+	{0xA926, 0xA92D, sbExtend},
+	{0xA92F, 0xA92F, sbSTerm},
+	{0xA930, 0xA946, sbOLetter},
+	{0xA947, 0xA953, sbExtend},
+	{0xA960, 0xA97C, sbOLetter},
+	{0xA980, 0xA983, sbExtend},
+	{0xA984, 0xA9B2, sbOLetter},
+	{0xA9B3, 0xA9C0, sbExtend},
+	{0xA9C8, 0xA9C9, sbSTerm},
+	{0xA9CF, 0xA9CF, sbOLetter},
+	{0xA9D0, 0xA9D9, sbNumeric},
+	{0xA9E0, 0xA9E4, sbOLetter},
+	{0xA9E5, 0xA9E5, sbExtend},
+	{0xA9E6, 0xA9EF, sbOLetter},
+	{0xA9F0, 0xA9F9, sbNumeric},
+	{0xA9FA, 0xA9FE, sbOLetter},
+	{0xAA00, 0xAA28, sbOLetter},
+	{0xAA29, 0xAA36, sbExtend},
+	{0xAA40, 0xAA42, sbOLetter},
+	{0xAA43, 0xAA43, sbExtend},
+	{0xAA44, 0xAA4B, sbOLetter},
+	{0xAA4C, 0xAA4D, sbExtend},
+	{0xAA50, 0xAA59, sbNumeric},
+	{0xAA5D, 0xAA5F, sbSTerm},
+	{0xAA60, 0xAA76, sbOLetter},
+	{0xAA7A, 0xAA7A, sbOLetter},
+	{0xAA7B, 0xAA7D, sbExtend},
+	{0xAA7E, 0xAAAF, sbOLetter},
+	{0xAAB0, 0xAAB0, sbExtend},
+	{0xAAB1, 0xAAB1, sbOLetter},
+	{0xAAB2, 0xAAB4, sbExtend},
+	{0xAAB5, 0xAAB6, sbOLetter},
+	{0xAAB7, 0xAAB8, sbExtend},
+	{0xAAB9, 0xAABD, sbOLetter},
+	{0xAABE, 0xAABF, sbExtend},
+	{0xAAC0, 0xAAC0, sbOLetter},
+	{0xAAC1, 0xAAC1, sbExtend},
+	{0xAAC2, 0xAAC2, sbOLetter},
+	{0xAADB, 0xAADD, sbOLetter},
+	{0xAAE0, 0xAAEA, sbOLetter},
+	{0xAAEB, 0xAAEF, sbExtend},
+	{0xAAF0, 0xAAF1, sbSTerm},
+	{0xAAF2, 0xAAF4, sbOLetter},
+	{0xAAF5, 0xAAF6, sbExtend},
+	{0xAB01, 0xAB06, sbOLetter},
+	{0xAB09, 0xAB0E, sbOLetter},
+	{0xAB11, 0xAB16, sbOLetter},
+	{0xAB20, 0xAB26, sbOLetter},
+	{0xAB28, 0xAB2E, sbOLetter},
+	{0xAB30, 0xAB5A, sbLower},
+	{0xAB5C, 0xAB69, sbLower},
+	{0xAB70, 0xABBF, sbLower},
+	{0xABC0, 0xABE2, sbOLetter},
+	{0xABE3, 0xABEA, sbExtend},
+	{0xABEB, 0xABEB, sbSTerm},
+	{0xABEC, 0xABED, sbExtend},
+	{0xABF0, 0xABF9, sbNumeric},
+	{0xAC00, 0xD7A3, sbOLetter},
+	{0xD7B0, 0xD7C6, sbOLetter},
+	{0xD7CB, 0xD7FB, sbOLetter},
+	{0xF900, 0xFA6D, sbOLetter},
+	{0xFA70, 0xFAD9, sbOLetter},
+	{0xFB00, 0xFB06, sbLower},
+	{0xFB13, 0xFB17, sbLower},
+	{0xFB1D, 0xFB1D, sbOLetter},
+	{0xFB1E, 0xFB1E, sbExtend},
+	{0xFB1F, 0xFB28, sbOLetter},
+	{0xFB2A, 0xFB36, sbOLetter},
+	{0xFB38, 0xFB3C, sbOLetter},
+	{0xFB3E, 0xFB3E, sbOLetter},
+	{0xFB40, 0xFB41, sbOLetter},
+	{0xFB43, 0xFB44, sbOLetter},
+	{0xFB46, 0xFBB1, sbOLetter},
+	{0xFBD3, 0xFD3D, sbOLetter},
+	{0xFD3E, 0xFD3F, sbClose},
+	{0xFD50, 0xFD8F, sbOLetter},
+	{0xFD92, 0xFDC7, sbOLetter},
+	{0xFDF0, 0xFDFB, sbOLetter},
+	{0xFE00, 0xFE0F, sbExtend},
+	{0xFE10, 0xFE11, sbSContinue},
+	{0xFE13, 0xFE13, sbSContinue},
+	{0xFE17, 0xFE18, sbClose},
+	{0xFE20, 0xFE2F, sbExtend},
+	{0xFE31, 0xFE32, sbSContinue},
+	{0xFE35, 0xFE44, sbClose},
+	{0xFE47, 0xFE48, sbClose},
+	{0xFE50, 0xFE51, sbSContinue},
+	{0xFE52, 0xFE52, sbATerm},
+	{0xFE55, 0xFE55, sbSContinue},
+	{0xFE56, 0xFE57, sbSTerm},
+	{0xFE58, 0xFE58, sbSContinue},
+	{0xFE59, 0xFE5E, sbClose},
+	{0xFE63, 0xFE63, sbSContinue},
+	{0xFE70, 0xFE74, sbOLetter},
+	{0xFE76, 0xFEFC, sbOLetter},
+	{0xFEFF, 0xFEFF, sbFormat},
+	{0xFF01, 0xFF01, sbSTerm},
+	{0xFF08, 0xFF09, sbClose},
+	{0xFF0C, 0xFF0D, sbSContinue},
+	{0xFF0E, 0xFF0E, sbATerm},
+	{0xFF10, 0xFF19, sbNumeric},
+	{0xFF1A, 0xFF1A, sbSContinue},
+	{0xFF1F, 0xFF1F, sbSTerm},
+	{0xFF21, 0xFF3A, sbUpper},
+	{0xFF3B, 0xFF3B, sbClose},
+	{0xFF3D, 0xFF3D, sbClose},
+	{0xFF41, 0xFF5A, sbLower},
+	{0xFF5B, 0xFF5B, sbClose},
+	{0xFF5D, 0xFF5D, sbClose},
+	{0xFF5F, 0xFF60, sbClose},
+	{0xFF61, 0xFF61, sbSTerm},
+	{0xFF62, 0xFF63, sbClose},
+	{0xFF64, 0xFF64, sbSContinue},
+	{0xFF66, 0xFF9D, sbOLetter},
+	{0xFF9E, 0xFF9F, sbExtend},
+	{0xFFA0, 0xFFBE, sbOLetter},
+	{0xFFC2, 0xFFC7, sbOLetter},
+	{0xFFCA, 0xFFCF, sbOLetter},
+	{0xFFD2, 0xFFD7, sbOLetter},
+	{0xFFDA, 0xFFDC, sbOLetter},
+	{0xFFF9, 0xFFFB, sbFormat},
+	{0x10000, 0x1000B, sbOLetter},
+	{0x1000D, 0x10026, sbOLetter},
+	{0x10028, 0x1003A, sbOLetter},
+	{0x1003C, 0x1003D, sbOLetter},
+	{0x1003F, 0x1004D, sbOLetter},
+	{0x10050, 0x1005D, sbOLetter},
+	{0x10080, 0x100FA, sbOLetter},
+	{0x10140, 0x10174, sbOLetter},
+	{0x101FD, 0x101FD, sbExtend},
+	{0x10280, 0x1029C, sbOLetter},
+	{0x102A0, 0x102D0, sbOLetter},
+	{0x102E0, 0x102E0, sbExtend},
+	{0x10300, 0x1031F, sbOLetter},
+	{0x1032D, 0x1034A, sbOLetter},
+	{0x10350, 0x10375, sbOLetter},
+	{0x10376, 0x1037A, sbExtend},
+	{0x10380, 0x1039D, sbOLetter},
+	{0x103A0, 0x103C3, sbOLetter},
+	{0x103C8, 0x103CF, sbOLetter},
+	{0x103D1, 0x103D5, sbOLetter},
+	{0x10400, 0x10427, sbUpper},
+	{0x10428, 0x1044F, sbLower},
+	{0x10450, 0x1049D, sbOLetter},
+	{0x104A0, 0x104A9, sbNumeric},
+	{0x104B0, 0x104D3, sbUpper},
+	{0x104D8, 0x104FB, sbLower},
+	{0x10500, 0x10527, sbOLetter},
+	{0x10530, 0x10563, sbOLetter},
+	{0x10570, 0x1057A, sbUpper},
+	{0x1057C, 0x1058A, sbUpper},
+	{0x1058C, 0x10592, sbUpper},
+	{0x10594, 0x10595, sbUpper},
+	{0x10597, 0x105A1, sbLower},
+	{0x105A3, 0x105B1, sbLower},
+	{0x105B3, 0x105B9, sbLower},
+	{0x105BB, 0x105BC, sbLower},
+	{0x10600, 0x10736, sbOLetter},
+	{0x10740, 0x10755, sbOLetter},
+	{0x10760, 0x10767, sbOLetter},
+	{0x10780, 0x10780, sbLower},
+	{0x10781, 0x10782, sbOLetter},
+	{0x10783, 0x10785, sbLower},
+	{0x10787, 0x107B0, sbLower},
+	{0x107B2, 0x107BA, sbLower},
+	{0x10800, 0x10805, sbOLetter},
+	{0x10808, 0x10808, sbOLetter},
+	{0x1080A, 0x10835, sbOLetter},
+	{0x10837, 0x10838, sbOLetter},
+	{0x1083C, 0x1083C, sbOLetter},
+	{0x1083F, 0x10855, sbOLetter},
+	{0x10860, 0x10876, sbOLetter},
+	{0x10880, 0x1089E, sbOLetter},
+	{0x108E0, 0x108F2, sbOLetter},
+	{0x108F4, 0x108F5, sbOLetter},
+	{0x10900, 0x10915, sbOLetter},
+	{0x10920, 0x10939, sbOLetter},
+	{0x10980, 0x109B7, sbOLetter},
+	{0x109BE, 0x109BF, sbOLetter},
+	{0x10A00, 0x10A00, sbOLetter},
+	{0x10A01, 0x10A03, sbExtend},
+	{0x10A05, 0x10A06, sbExtend},
+	{0x10A0C, 0x10A0F, sbExtend},
+	{0x10A10, 0x10A13, sbOLetter},
+	{0x10A15, 0x10A17, sbOLetter},
+	{0x10A19, 0x10A35, sbOLetter},
+	{0x10A38, 0x10A3A, sbExtend},
+	{0x10A3F, 0x10A3F, sbExtend},
+	{0x10A56, 0x10A57, sbSTerm},
+	{0x10A60, 0x10A7C, sbOLetter},
+	{0x10A80, 0x10A9C, sbOLetter},
+	{0x10AC0, 0x10AC7, sbOLetter},
+	{0x10AC9, 0x10AE4, sbOLetter},
+	{0x10AE5, 0x10AE6, sbExtend},
+	{0x10B00, 0x10B35, sbOLetter},
+	{0x10B40, 0x10B55, sbOLetter},
+	{0x10B60, 0x10B72, sbOLetter},
+	{0x10B80, 0x10B91, sbOLetter},
+	{0x10C00, 0x10C48, sbOLetter},
+	{0x10C80, 0x10CB2, sbUpper},
+	{0x10CC0, 0x10CF2, sbLower},
+	{0x10D00, 0x10D23, sbOLetter},
+	{0x10D24, 0x10D27, sbExtend},
+	{0x10D30, 0x10D39, sbNumeric},
+	{0x10E80, 0x10EA9, sbOLetter},
+	{0x10EAB, 0x10EAC, sbExtend},
+	{0x10EB0, 0x10EB1, sbOLetter},
+	{0x10EFD, 0x10EFF, sbExtend},
+	{0x10F00, 0x10F1C, sbOLetter},
+	{0x10F27, 0x10F27, sbOLetter},
+	{0x10F30, 0x10F45, sbOLetter},
+	{0x10F46, 0x10F50, sbExtend},
+	{0x10F55, 0x10F59, sbSTerm},
+	{0x10F70, 0x10F81, sbOLetter},
+	{0x10F82, 0x10F85, sbExtend},
+	{0x10F86, 0x10F89, sbSTerm},
+	{0x10FB0, 0x10FC4, sbOLetter},
+	{0x10FE0, 0x10FF6, sbOLetter},
+	{0x11000, 0x11002, sbExtend},
+	{0x11003, 0x11037, sbOLetter},
+	{0x11038, 0x11046, sbExtend},
+	{0x11047, 0x11048, sbSTerm},
+	{0x11066, 0x1106F, sbNumeric},
+	{0x11070, 0x11070, sbExtend},
+	{0x11071, 0x11072, sbOLetter},
+	{0x11073, 0x11074, sbExtend},
+	{0x11075, 0x11075, sbOLetter},
+	{0x1107F, 0x11082, sbExtend},
+	{0x11083, 0x110AF, sbOLetter},
+	{0x110B0, 0x110BA, sbExtend},
+	{0x110BD, 0x110BD, sbFormat},
+	{0x110BE, 0x110C1, sbSTerm},
+	{0x110C2, 0x110C2, sbExtend},
+	{0x110CD, 0x110CD, sbFormat},
+	{0x110D0, 0x110E8, sbOLetter},
+	{0x110F0, 0x110F9, sbNumeric},
+	{0x11100, 0x11102, sbExtend},
+	{0x11103, 0x11126, sbOLetter},
+	{0x11127, 0x11134, sbExtend},
+	{0x11136, 0x1113F, sbNumeric},
+	{0x11141, 0x11143, sbSTerm},
+	{0x11144, 0x11144, sbOLetter},
+	{0x11145, 0x11146, sbExtend},
+	{0x11147, 0x11147, sbOLetter},
+	{0x11150, 0x11172, sbOLetter},
+	{0x11173, 0x11173, sbExtend},
+	{0x11176, 0x11176, sbOLetter},
+	{0x11180, 0x11182, sbExtend},
+	{0x11183, 0x111B2, sbOLetter},
+	{0x111B3, 0x111C0, sbExtend},
+	{0x111C1, 0x111C4, sbOLetter},
+	{0x111C5, 0x111C6, sbSTerm},
+	{0x111C9, 0x111CC, sbExtend},
+	{0x111CD, 0x111CD, sbSTerm},
+	{0x111CE, 0x111CF, sbExtend},
+	{0x111D0, 0x111D9, sbNumeric},
+	{0x111DA, 0x111DA, sbOLetter},
+	{0x111DC, 0x111DC, sbOLetter},
+	{0x111DE, 0x111DF, sbSTerm},
+	{0x11200, 0x11211, sbOLetter},
+	{0x11213, 0x1122B, sbOLetter},
+	{0x1122C, 0x11237, sbExtend},
+	{0x11238, 0x11239, sbSTerm},
+	{0x1123B, 0x1123C, sbSTerm},
+	{0x1123E, 0x1123E, sbExtend},
+	{0x1123F, 0x11240, sbOLetter},
+	{0x11241, 0x11241, sbExtend},
+	{0x11280, 0x11286, sbOLetter},
+	{0x11288, 0x11288, sbOLetter},
+	{0x1128A, 0x1128D, sbOLetter},
+	{0x1128F, 0x1129D, sbOLetter},
+	{0x1129F, 0x112A8, sbOLetter},
+	{0x112A9, 0x112A9, sbSTerm},
+	{0x112B0, 0x112DE, sbOLetter},
+	{0x112DF, 0x112EA, sbExtend},
+	{0x112F0, 0x112F9, sbNumeric},
+	{0x11300, 0x11303, sbExtend},
+	{0x11305, 0x1130C, sbOLetter},
+	{0x1130F, 0x11310, sbOLetter},
+	{0x11313, 0x11328, sbOLetter},
+	{0x1132A, 0x11330, sbOLetter},
+	{0x11332, 0x11333, sbOLetter},
+	{0x11335, 0x11339, sbOLetter},
+	{0x1133B, 0x1133C, sbExtend},
+	{0x1133D, 0x1133D, sbOLetter},
+	{0x1133E, 0x11344, sbExtend},
+	{0x11347, 0x11348, sbExtend},
+	{0x1134B, 0x1134D, sbExtend},
+	{0x11350, 0x11350, sbOLetter},
+	{0x11357, 0x11357, sbExtend},
+	{0x1135D, 0x11361, sbOLetter},
+	{0x11362, 0x11363, sbExtend},
+	{0x11366, 0x1136C, sbExtend},
+	{0x11370, 0x11374, sbExtend},
+	{0x11400, 0x11434, sbOLetter},
+	{0x11435, 0x11446, sbExtend},
+	{0x11447, 0x1144A, sbOLetter},
+	{0x1144B, 0x1144C, sbSTerm},
+	{0x11450, 0x11459, sbNumeric},
+	{0x1145E, 0x1145E, sbExtend},
+	{0x1145F, 0x11461, sbOLetter},
+	{0x11480, 0x114AF, sbOLetter},
+	{0x114B0, 0x114C3, sbExtend},
+	{0x114C4, 0x114C5, sbOLetter},
+	{0x114C7, 0x114C7, sbOLetter},
+	{0x114D0, 0x114D9, sbNumeric},
+	{0x11580, 0x115AE, sbOLetter},
+	{0x115AF, 0x115B5, sbExtend},
+	{0x115B8, 0x115C0, sbExtend},
+	{0x115C2, 0x115C3, sbSTerm},
+	{0x115C9, 0x115D7, sbSTerm},
+	{0x115D8, 0x115DB, sbOLetter},
+	{0x115DC, 0x115DD, sbExtend},
+	{0x11600, 0x1162F, sbOLetter},
+	{0x11630, 0x11640, sbExtend},
+	{0x11641, 0x11642, sbSTerm},
+	{0x11644, 0x11644, sbOLetter},
+	{0x11650, 0x11659, sbNumeric},
+	{0x11680, 0x116AA, sbOLetter},
+	{0x116AB, 0x116B7, sbExtend},
+	{0x116B8, 0x116B8, sbOLetter},
+	{0x116C0, 0x116C9, sbNumeric},
+	{0x11700, 0x1171A, sbOLetter},
+	{0x1171D, 0x1172B, sbExtend},
+	{0x11730, 0x11739, sbNumeric},
+	{0x1173C, 0x1173E, sbSTerm},
+	{0x11740, 0x11746, sbOLetter},
+	{0x11800, 0x1182B, sbOLetter},
+	{0x1182C, 0x1183A, sbExtend},
+	{0x118A0, 0x118BF, sbUpper},
+	{0x118C0, 0x118DF, sbLower},
+	{0x118E0, 0x118E9, sbNumeric},
+	{0x118FF, 0x11906, sbOLetter},
+	{0x11909, 0x11909, sbOLetter},
+	{0x1190C, 0x11913, sbOLetter},
+	{0x11915, 0x11916, sbOLetter},
+	{0x11918, 0x1192F, sbOLetter},
+	{0x11930, 0x11935, sbExtend},
+	{0x11937, 0x11938, sbExtend},
+	{0x1193B, 0x1193E, sbExtend},
+	{0x1193F, 0x1193F, sbOLetter},
+	{0x11940, 0x11940, sbExtend},
+	{0x11941, 0x11941, sbOLetter},
+	{0x11942, 0x11943, sbExtend},
+	{0x11944, 0x11944, sbSTerm},
+	{0x11946, 0x11946, sbSTerm},
+	{0x11950, 0x11959, sbNumeric},
+	{0x119A0, 0x119A7, sbOLetter},
+	{0x119AA, 0x119D0, sbOLetter},
+	{0x119D1, 0x119D7, sbExtend},
+	{0x119DA, 0x119E0, sbExtend},
+	{0x119E1, 0x119E1, sbOLetter},
+	{0x119E3, 0x119E3, sbOLetter},
+	{0x119E4, 0x119E4, sbExtend},
+	{0x11A00, 0x11A00, sbOLetter},
+	{0x11A01, 0x11A0A, sbExtend},
+	{0x11A0B, 0x11A32, sbOLetter},
+	{0x11A33, 0x11A39, sbExtend},
+	{0x11A3A, 0x11A3A, sbOLetter},
+	{0x11A3B, 0x11A3E, sbExtend},
+	{0x11A42, 0x11A43, sbSTerm},
+	{0x11A47, 0x11A47, sbExtend},
+	{0x11A50, 0x11A50, sbOLetter},
+	{0x11A51, 0x11A5B, sbExtend},
+	{0x11A5C, 0x11A89, sbOLetter},
+	{0x11A8A, 0x11A99, sbExtend},
+	{0x11A9B, 0x11A9C, sbSTerm},
+	{0x11A9D, 0x11A9D, sbOLetter},
+	{0x11AB0, 0x11AF8, sbOLetter},
+	{0x11C00, 0x11C08, sbOLetter},
+	{0x11C0A, 0x11C2E, sbOLetter},
+	{0x11C2F, 0x11C36, sbExtend},
+	{0x11C38, 0x11C3F, sbExtend},
+	{0x11C40, 0x11C40, sbOLetter},
+	{0x11C41, 0x11C42, sbSTerm},
+	{0x11C50, 0x11C59, sbNumeric},
+	{0x11C72, 0x11C8F, sbOLetter},
+	{0x11C92, 0x11CA7, sbExtend},
+	{0x11CA9, 0x11CB6, sbExtend},
+	{0x11D00, 0x11D06, sbOLetter},
+	{0x11D08, 0x11D09, sbOLetter},
+	{0x11D0B, 0x11D30, sbOLetter},
+	{0x11D31, 0x11D36, sbExtend},
+	{0x11D3A, 0x11D3A, sbExtend},
+	{0x11D3C, 0x11D3D, sbExtend},
+	{0x11D3F, 0x11D45, sbExtend},
+	{0x11D46, 0x11D46, sbOLetter},
+	{0x11D47, 0x11D47, sbExtend},
+	{0x11D50, 0x11D59, sbNumeric},
+	{0x11D60, 0x11D65, sbOLetter},
+	{0x11D67, 0x11D68, sbOLetter},
+	{0x11D6A, 0x11D89, sbOLetter},
+	{0x11D8A, 0x11D8E, sbExtend},
+	{0x11D90, 0x11D91, sbExtend},
+	{0x11D93, 0x11D97, sbExtend},
+	{0x11D98, 0x11D98, sbOLetter},
+	{0x11DA0, 0x11DA9, sbNumeric},
+	{0x11EE0, 0x11EF2, sbOLetter},
+	{0x11EF3, 0x11EF6, sbExtend},
+	{0x11EF7, 0x11EF8, sbSTerm},
+	{0x11F00, 0x11F01, sbExtend},
+	{0x11F02, 0x11F02, sbOLetter},
+	{0x11F03, 0x11F03, sbExtend},
+	{0x11F04, 0x11F10, sbOLetter},
+	{0x11F12, 0x11F33, sbOLetter},
+	{0x11F34, 0x11F3A, sbExtend},
+	{0x11F3E, 0x11F42, sbExtend},
+	{0x11F43, 0x11F44, sbSTerm},
+	{0x11F50, 0x11F59, sbNumeric},
+	{0x11FB0, 0x11FB0, sbOLetter},
+	{0x12000, 0x12399, sbOLetter},
+	{0x12400, 0x1246E, sbOLetter},
+	{0x12480, 0x12543, sbOLetter},
+	{0x12F90, 0x12FF0, sbOLetter},
+	{0x13000, 0x1342F, sbOLetter},
+	{0x13430, 0x1343F, sbFormat},
+	{0x13440, 0x13440, sbExtend},
+	{0x13441, 0x13446, sbOLetter},
+	{0x13447, 0x13455, sbExtend},
+	{0x14400, 0x14646, sbOLetter},
+	{0x16800, 0x16A38, sbOLetter},
+	{0x16A40, 0x16A5E, sbOLetter},
+	{0x16A60, 0x16A69, sbNumeric},
+	{0x16A6E, 0x16A6F, sbSTerm},
+	{0x16A70, 0x16ABE, sbOLetter},
+	{0x16AC0, 0x16AC9, sbNumeric},
+	{0x16AD0, 0x16AED, sbOLetter},
+	{0x16AF0, 0x16AF4, sbExtend},
+	{0x16AF5, 0x16AF5, sbSTerm},
+	{0x16B00, 0x16B2F, sbOLetter},
+	{0x16B30, 0x16B36, sbExtend},
+	{0x16B37, 0x16B38, sbSTerm},
+	{0x16B40, 0x16B43, sbOLetter},
+	{0x16B44, 0x16B44, sbSTerm},
+	{0x16B50, 0x16B59, sbNumeric},
+	{0x16B63, 0x16B77, sbOLetter},
+	{0x16B7D, 0x16B8F, sbOLetter},
+	{0x16E40, 0x16E5F, sbUpper},
+	{0x16E60, 0x16E7F, sbLower},
+	{0x16E98, 0x16E98, sbSTerm},
+	{0x16F00, 0x16F4A, sbOLetter},
+	{0x16F4F, 0x16F4F, sbExtend},
+	{0x16F50, 0x16F50, sbOLetter},
+	{0x16F51, 0x16F87, sbExtend},
+	{0x16F8F, 0x16F92, sbExtend},
+	{0x16F93, 0x16F9F, sbOLetter},
+	{0x16FE0, 0x16FE1, sbOLetter},
+	{0x16FE3, 0x16FE3, sbOLetter},
+	{0x16FE4, 0x16FE4, sbExtend},
+	{0x16FF0, 0x16FF1, sbExtend},
+	{0x17000, 0x187F7, sbOLetter},
+	{0x18800, 0x18CD5, sbOLetter},
+	{0x18D00, 0x18D08, sbOLetter},
+	{0x1AFF0, 0x1AFF3, sbOLetter},
+	{0x1AFF5, 0x1AFFB, sbOLetter},
+	{0x1AFFD, 0x1AFFE, sbOLetter},
+	{0x1B000, 0x1B122, sbOLetter},
+	{0x1B132, 0x1B132, sbOLetter},
+	{0x1B150, 0x1B152, sbOLetter},
+	{0x1B155, 0x1B155, sbOLetter},
+	{0x1B164, 0x1B167, sbOLetter},
+	{0x1B170, 0x1B2FB, sbOLetter},
+	{0x1BC00, 0x1BC6A, sbOLetter},
+	{0x1BC70, 0x1BC7C, sbOLetter},
+	{0x1BC80, 0x1BC88, sbOLetter},
+	{0x1BC90, 0x1BC99, sbOLetter},
+	{0x1BC9D, 0x1BC9E, sbExtend},
+	{0x1BC9F, 0x1BC9F, sbSTerm},
+	{0x1BCA0, 0x1BCA3, sbFormat},
+	{0x1CF00, 0x1CF2D, sbExtend},
+	{0x1CF30, 0x1CF46, sbExtend},
+	{0x1D165, 0x1D169, sbExtend},
+	{0x1D16D, 0x1D172, sbExtend},
+	{0x1D173, 0x1D17A, sbFormat},
+	{0x1D17B, 0x1D182, sbExtend},
+	{0x1D185, 0x1D18B, sbExtend},
+	{0x1D1AA, 0x1D1AD, sbExtend},
+	{0x1D242, 0x1D244, sbExtend},
+	{0x1D400, 0x1D419, sbUpper},
+	{0x1D41A, 0x1D433, sbLower},
+	{0x1D434, 0x1D44D, sbUpper},
+	{0x1D44E, 0x1D454, sbLower},
+	{0x1D456, 0x1D467, sbLower},
+	{0x1D468, 0x1D481, sbUpper},
+	{0x1D482, 0x1D49B, sbLower},
+	{0x1D49C, 0x1D49C, sbUpper},
+	{0x1D49E, 0x1D49F, sbUpper},
+	{0x1D4A2, 0x1D4A2, sbUpper},
+	{0x1D4A5, 0x1D4A6, sbUpper},
+	{0x1D4A9, 0x1D4AC, sbUpper},
+	{0x1D4AE, 0x1D4B5, sbUpper},
+	{0x1D4B6, 0x1D4B9, sbLower},
+	{0x1D4BB, 0x1D4BB, sbLower},
+	{0x1D4BD, 0x1D4C3, sbLower},
+	{0x1D4C5, 0x1D4CF, sbLower},
+	{0x1D4D0, 0x1D4E9, sbUpper},
+	{0x1D4EA, 0x1D503, sbLower},
+	{0x1D504, 0x1D505, sbUpper},
+	{0x1D507, 0x1D50A, sbUpper},
+	{0x1D50D, 0x1D514, sbUpper},
+	{0x1D516, 0x1D51C, sbUpper},
+	{0x1D51E, 0x1D537, sbLower},
+	{0x1D538, 0x1D539, sbUpper},
+	{0x1D53B, 0x1D53E, sbUpper},
+	{0x1D540, 0x1D544, sbUpper},
+	{0x1D546, 0x1D546, sbUpper},
+	{0x1D54A, 0x1D550, sbUpper},
+	{0x1D552, 0x1D56B, sbLower},
+	{0x1D56C, 0x1D585, sbUpper},
+	{0x1D586, 0x1D59F, sbLower},
+	{0x1D5A0, 0x1D5B9, sbUpper},
+	{0x1D5BA, 0x1D5D3, sbLower},
+	{0x1D5D4, 0x1D5ED, sbUpper},
+	{0x1D5EE, 0x1D607, sbLower},
+	{0x1D608, 0x1D621, sbUpper},
+	{0x1D622, 0x1D63B, sbLower},
+	{0x1D63C, 0x1D655, sbUpper},
+	{0x1D656, 0x1D66F, sbLower},
+	{0x1D670, 0x1D689, sbUpper},
+	{0x1D68A, 0x1D6A5, sbLower},
+	{0x1D6A8, 0x1D6C0, sbUpper},
+	{0x1D6C2, 0x1D6DA, sbLower},
+	{0x1D6DC, 0x1D6E1, sbLower},
+	{0x1D6E2, 0x1D6FA, sbUpper},
+	{0x1D6FC, 0x1D714, sbLower},
+	{0x1D716, 0x1D71B, sbLower},
+	{0x1D71C, 0x1D734, sbUpper},
+	{0x1D736, 0x1D74E, sbLower},
+	{0x1D750, 0x1D755, sbLower},
+	{0x1D756, 0x1D76E, sbUpper},
+	{0x1D770, 0x1D788, sbLower},
+	{0x1D78A, 0x1D78F, sbLower},
+	{0x1D790, 0x1D7A8, sbUpper},
+	{0x1D7AA, 0x1D7C2, sbLower},
+	{0x1D7C4, 0x1D7C9, sbLower},
+	{0x1D7CA, 0x1D7CA, sbUpper},
+	{0x1D7CB, 0x1D7CB, sbLower},
+	{0x1D7CE, 0x1D7FF, sbNumeric},
+	{0x1DA00, 0x1DA36, sbExtend},
+	{0x1DA3B, 0x1DA6C, sbExtend},
+	{0x1DA75, 0x1DA75, sbExtend},
+	{0x1DA84, 0x1DA84, sbExtend},
+	{0x1DA88, 0x1DA88, sbSTerm},
+	{0x1DA9B, 0x1DA9F, sbExtend},
+	{0x1DAA1, 0x1DAAF, sbExtend},
+	{0x1DF00, 0x1DF09, sbLower},
+	{0x1DF0A, 0x1DF0A, sbOLetter},
+	{0x1DF0B, 0x1DF1E, sbLower},
+	{0x1DF25, 0x1DF2A, sbLower},
+	{0x1E000, 0x1E006, sbExtend},
+	{0x1E008, 0x1E018, sbExtend},
+	{0x1E01B, 0x1E021, sbExtend},
+	{0x1E023, 0x1E024, sbExtend},
+	{0x1E026, 0x1E02A, sbExtend},
+	{0x1E030, 0x1E06D, sbLower},
+	{0x1E08F, 0x1E08F, sbExtend},
+	{0x1E100, 0x1E12C, sbOLetter},
+	{0x1E130, 0x1E136, sbExtend},
+	{0x1E137, 0x1E13D, sbOLetter},
+	{0x1E140, 0x1E149, sbNumeric},
+	{0x1E14E, 0x1E14E, sbOLetter},
+	{0x1E290, 0x1E2AD, sbOLetter},
+	{0x1E2AE, 0x1E2AE, sbExtend},
+	{0x1E2C0, 0x1E2EB, sbOLetter},
+	{0x1E2EC, 0x1E2EF, sbExtend},
+	{0x1E2F0, 0x1E2F9, sbNumeric},
+	{0x1E4D0, 0x1E4EB, sbOLetter},
+	{0x1E4EC, 0x1E4EF, sbExtend},
+	{0x1E4F0, 0x1E4F9, sbNumeric},
+	{0x1E7E0, 0x1E7E6, sbOLetter},
+	{0x1E7E8, 0x1E7EB, sbOLetter},
+	{0x1E7ED, 0x1E7EE, sbOLetter},
+	{0x1E7F0, 0x1E7FE, sbOLetter},
+	{0x1E800, 0x1E8C4, sbOLetter},
+	{0x1E8D0, 0x1E8D6, sbExtend},
+	{0x1E900, 0x1E921, sbUpper},
+	{0x1E922, 0x1E943, sbLower},
+	{0x1E944, 0x1E94A, sbExtend},
+	{0x1E94B, 0x1E94B, sbOLetter},
+	{0x1E950, 0x1E959, sbNumeric},
+	{0x1EE00, 0x1EE03, sbOLetter},
+	{0x1EE05, 0x1EE1F, sbOLetter},
+	{0x1EE21, 0x1EE22, sbOLetter},
+	{0x1EE24, 0x1EE24, sbOLetter},
+	{0x1EE27, 0x1EE27, sbOLetter},
+	{0x1EE29, 0x1EE32, sbOLetter},
+	{0x1EE34, 0x1EE37, sbOLetter},
+	{0x1EE39, 0x1EE39, sbOLetter},
+	{0x1EE3B, 0x1EE3B, sbOLetter},
+	{0x1EE42, 0x1EE42, sbOLetter},
+	{0x1EE47, 0x1EE47, sbOLetter},
+	{0x1EE49, 0x1EE49, sbOLetter},
+	{0x1EE4B, 0x1EE4B, sbOLetter},
+	{0x1EE4D, 0x1EE4F, sbOLetter},
+	{0x1EE51, 0x1EE52, sbOLetter},
+	{0x1EE54, 0x1EE54, sbOLetter},
+	{0x1EE57, 0x1EE57, sbOLetter},
+	{0x1EE59, 0x1EE59, sbOLetter},
+	{0x1EE5B, 0x1EE5B, sbOLetter},
+	{0x1EE5D, 0x1EE5D, sbOLetter},
+	{0x1EE5F, 0x1EE5F, sbOLetter},
+	{0x1EE61, 0x1EE62, sbOLetter},
+	{0x1EE64, 0x1EE64, sbOLetter},
+	{0x1EE67, 0x1EE6A, sbOLetter},
+	{0x1EE6C, 0x1EE72, sbOLetter},
+	{0x1EE74, 0x1EE77, sbOLetter},
+	{0x1EE79, 0x1EE7C, sbOLetter},
+	{0x1EE7E, 0x1EE7E, sbOLetter},
+	{0x1EE80, 0x1EE89, sbOLetter},
+	{0x1EE8B, 0x1EE9B, sbOLetter},
+	{0x1EEA1, 0x1EEA3, sbOLetter},
+	{0x1EEA5, 0x1EEA9, sbOLetter},
+	{0x1EEAB, 0x1EEBB, sbOLetter},
+	{0x1F130, 0x1F149, sbUpper},
+	{0x1F150, 0x1F169, sbUpper},
+	{0x1F170, 0x1F189, sbUpper},
+	{0x1F676, 0x1F678, sbClose},
+	{0x1FBF0, 0x1FBF9, sbNumeric},
+	{0x20000, 0x2A6DF, sbOLetter},
+	{0x2A700, 0x2B739, sbOLetter},
+	{0x2B740, 0x2B81D, sbOLetter},
+	{0x2B820, 0x2CEA1, sbOLetter},
+	{0x2CEB0, 0x2EBE0, sbOLetter},
+	{0x2F800, 0x2FA1D, sbOLetter},
+	{0x30000, 0x3134A, sbOLetter},
+	{0x31350, 0x323AF, sbOLetter},
+	{0xE0001, 0xE0001, sbFormat},
+	{0xE0020, 0xE007F, sbExtend},
+	{0xE0100, 0xE01EF, sbExtend},
+}
@@ -0,0 +1,58 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sentences(it *SentenceIterator) []Segment {
+	var a []Segment
+	for it.Next() {
+		a = append(a, it.Sentence())
+	}
+	return a
+}
+
+func TestSentenceBreakTest(t *testing.T) {
+	testBreakFile(t, "internal/ucd/SentenceBreakTest.txt",
+		func(s string) []Segment { return sentences(NewSentenceIterator(s)) },
+		func(u []uint16) []Segment { return sentences(NewSentenceIteratorUTF16(u)) })
+}
+
+func TestSentenceIterator(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp []string
+	}{
+		{"", nil},
+		{"One. Two? Three!", []string{"One. ", "Two? ", "Three!"}},
+		{"Mr. smith went to Washington. He said \"Hi.\" Then left.",
+			[]string{"Mr. smith went to Washington. ", "He said \"Hi.\" ", "Then left."}},
+		{"Pi is 3.14. 日本語。次", []string{"Pi is 3.14. ", "日本語。", "次"}},
+		{"Line one\nLine two", []string{"Line one\n", "Line two"}},
+	}
+	for _, x := range tests {
+		var got []string
+		for _, g := range sentences(NewSentenceIterator(x.s)) {
+			got = append(got, x.s[g.Start:g.End])
+		}
+		if !reflect.DeepEqual(got, x.exp) {
+			t.Errorf("NewSentenceIterator(%q) = %q; want: %q", x.s, got, x.exp)
+		}
+		u := StringToUTF16(x.s)
+		var got16 []string
+		for _, g := range sentences(NewSentenceIteratorUTF16(u)) {
+			got16 = append(got16, UTF16ToString(u[g.StartUTF16:g.EndUTF16]))
+		}
+		if !reflect.DeepEqual(got16, x.exp) {
+			t.Errorf("NewSentenceIteratorUTF16(%q) = %q; want: %q", x.s, got16, x.exp)
+		}
+	}
+}
+
+func BenchmarkSentenceIterator_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for it := NewSentenceIterator(SixtyFourUnicodeChars); it.Next(); {
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Package sourcemap decodes and encodes the "mappings" of version 3 source
+// maps and converts their columns between UTF-16 code units, which source
+// maps use, and byte offsets.
+package sourcemap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// A Map is a version 3 source map.
+type Map struct {
+	Version        int       `json:"version"`
+	File           string    `json:"file,omitempty"`
+	SourceRoot     string    `json:"sourceRoot,omitempty"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent,omitempty"`
+	Names          []string  `json:"names"`
+	Mappings       string    `json:"mappings"`
+}
+
+// A Segment maps a column of a generated line to an optional position in an
+// original source. Unlike the encoded form all fields are absolute values.
+// Source, OrigLine and OrigColumn are only meaningful if HasSource is true,
+// and Name only if HasName is true.
+type Segment struct {
+	GenColumn  int
+	Source     int
+	OrigLine   int
+	OrigColumn int
+	Name       int
+	HasSource  bool
+	HasName    bool
+}
+
+// Mappings are the segments of each line of generated code.
+type Mappings [][]Segment
+
+const base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+var base64Values = func() (a [256]int8) {
+	for i := range a {
+		a[i] = -1
+	}
+	for i := 0; i < len(base64Chars); i++ {
+		a[base64Chars[i]] = int8(i)
+	}
+	return a
+}()
+
+const (
+	vlqShift = 5
+	vlqMask  = 1<<vlqShift - 1
+	vlqCont  = 1 << vlqShift
+)
+
+var errVLQOverflow = errors.New("sourcemap: VLQ value overflows 32 bits")
+
+// decodeVLQ decodes the base64 VLQ value at the start of s and returns it
+// and the number of bytes consumed.
+func decodeVLQ(s string) (int, int, error) {
+	var v uint64
+	shift := uint(0)
+	for i := 0; i < len(s); i++ {
+		d := base64Values[s[i]]
+		if d < 0 {
+			return 0, 0, fmt.Errorf("sourcemap: invalid base64 VLQ character %q", s[i])
+		}
+		v |= uint64(d&vlqMask) << shift
+		if shift > 30 || v > 1<<32 {
+			return 0, 0, errVLQOverflow
+		}
+		if d&vlqCont == 0 {
+			n := int(v >> 1)
+			if v&1 != 0 {
+				n = -n
+			}
+			return n, i + 1, nil
+		}
+		shift += vlqShift
+	}
+	return 0, 0, errors.New("sourcemap: unterminated base64 VLQ value")
+}
+
+// appendVLQ appends the base64 VLQ encoding of n to b.
+func appendVLQ(b []byte, n int) []byte {
+	var v uint64
+	if n < 0 {
+		v = uint64(-n)<<1 | 1
+	} else {
+		v = uint64(n) << 1
+	}
+	for {
+		d := v & vlqMask
+		v >>= vlqShift
+		if v != 0 {
+			d |= vlqCont
+		}
+		b = append(b, base64Chars[d])
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+// DecodeMappings decodes the "mappings" field of a source map.
+func DecodeMappings(s string) (Mappings, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var (
+		lines Mappings
+		prev  [5]int // previous field values, GenColumn resets every line
+	)
+	for n, line := range strings.Split(s, ";") {
+		var segs []Segment
+		prev[0] = 0
+		for _, f := range strings.Split(line, ",") {
+			if f == "" {
+				continue
+			}
+			k := 0
+			for ; len(f) > 0; k++ {
+				if k == len(prev) {
+					return nil, fmt.Errorf("sourcemap: segment longer than 5 fields at line %d", n)
+				}
+				v, size, err := decodeVLQ(f)
+				if err != nil {
+					return nil, fmt.Errorf("%w at line %d", err, n)
+				}
+				if prev[k] += v; prev[k] < 0 {
+					return nil, fmt.Errorf("sourcemap: negative segment value at line %d", n)
+				}
+				f = f[size:]
+			}
+			if k != 1 && k != 4 && k != 5 {
+				return nil, fmt.Errorf("sourcemap: invalid segment length %d at line %d", k, n)
+			}
+			x := Segment{GenColumn: prev[0]}
+			if k >= 4 {
+				x.HasSource = true
+				x.Source, x.OrigLine, x.OrigColumn = prev[1], prev[2], prev[3]
+			}
+			if k == 5 {
+				x.HasName = true
+				x.Name = prev[4]
+			}
+			segs = append(segs, x)
+		}
+		lines = append(lines, segs)
+	}
+	return lines, nil
+}
+
+// Encode returns the encoded "mappings" field of m.
+func (m Mappings) Encode() string {
+	var (
+		b      []byte
+		fields [5]int
+	)
+	for i, line := range m {
+		if i > 0 {
+			b = append(b, ';')
+		}
+		fields[0] = 0
+		for j, x := range line {
+			if j > 0 {
+				b = append(b, ',')
+			}
+			b = appendVLQ(b, x.GenColumn-fields[0])
+			fields[0] = x.GenColumn
+			if !x.HasSource {
+				continue
+			}
+			b = appendVLQ(b, x.Source-fields[1])
+			b = appendVLQ(b, x.OrigLine-fields[2])
+			b = appendVLQ(b, x.OrigColumn-fields[3])
+			fields[1], fields[2], fields[3] = x.Source, x.OrigLine, x.OrigColumn
+			if x.HasName {
+				b = appendVLQ(b, x.Name-fields[4])
+				fields[4] = x.Name
+			}
+		}
+	}
+	return string(b)
+}
+
+// A text is a source indexed by line.
+type text struct {
+	s     string
+	lines []int // byte offset of the start of each line
+}
+
+func newText(s string) *text {
+	lines := []int{0}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+			lines = append(lines, i+1)
+		case '\n':
+			lines = append(lines, i+1)
+		}
+	}
+	return &text{s: s, lines: lines}
+}
+
+// line returns line n excluding the line terminator.
+func (t *text) line(n int) (string, bool) {
+	if n < 0 || n >= len(t.lines) {
+		return "", false
+	}
+	end := len(t.s)
+	if n+1 < len(t.lines) {
+		end = t.lines[n+1]
+	}
+	line := t.s[t.lines[n]:end]
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), true
+}
+
+// convert converts column col of line n from UTF-16 code units to bytes or,
+// if toUTF16 is true, from bytes to UTF-16 code units.
+func (t *text) convert(n, col int, toUTF16 bool) (int, error) {
+	line, ok := t.line(n)
+	if !ok {
+		return 0, fmt.Errorf("line %d out of range [0, %d)", n, len(t.lines))
+	}
+	if toUTF16 {
+		if col > len(line) {
+			return 0, fmt.Errorf("byte column %d of line %d out of range [0, %d]",
+				col, n, len(line))
+		}
+		return utfconv.UTF16Index(line, col), nil
+	}
+	i := utfconv.ByteIndexUTF16(line, col)
+	if i < 0 {
+		return 0, fmt.Errorf("UTF-16 column %d of line %d out of range [0, %d]",
+			col, n, utfconv.UTF16EncodedLenString(line))
+	}
+	return i, nil
+}
+
+// ToByteColumns returns a copy of m with the generated columns converted
+// from UTF-16 code units to byte offsets of the lines of generated, and the
+// original columns converted to byte offsets of the lines of sources, which
+// is indexed by Segment.Source. Segments that refer to a source that is out
+// of range, or nil, keep their original column.
+func (m Mappings) ToByteColumns(generated string, sources []*string) (Mappings, error) {
+	return m.convert(generated, sources, false)
+}
+
+// ToUTF16Columns is the inverse of ToByteColumns, it returns a copy of m
+// with generated and original byte columns converted to UTF-16 code units.
+func (m Mappings) ToUTF16Columns(generated string, sources []*string) (Mappings, error) {
+	return m.convert(generated, sources, true)
+}
+
+func (m Mappings) convert(generated string, sources []*string, toUTF16 bool) (Mappings, error) {
+	gen := newText(generated)
+	texts := make([]*text, len(sources))
+	out := make(Mappings, len(m))
+	for i, line := range m {
+		if line == nil {
+			continue
+		}
+		out[i] = make([]Segment, len(line))
+		for j, x := range line {
+			col, err := gen.convert(i, x.GenColumn, toUTF16)
+			if err != nil {
+				return nil, fmt.Errorf("sourcemap: generated code: %w", err)
+			}
+			x.GenColumn = col
+			if x.HasSource && 0 <= x.Source && x.Source < len(sources) && sources[x.Source] != nil {
+				if texts[x.Source] == nil {
+					texts[x.Source] = newText(*sources[x.Source])
+				}
+				col, err := texts[x.Source].convert(x.OrigLine, x.OrigColumn, toUTF16)
+				if err != nil {
+					return nil, fmt.Errorf("sourcemap: source %d: %w", x.Source, err)
+				}
+				x.OrigColumn = col
+			}
+			out[i][j] = x
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,169 @@
+package sourcemap
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestVLQ(t *testing.T) {
+	tests := []struct {
+		n int
+		s string
+	}{
+		{0, "A"},
+		{1, "C"},
+		{-1, "D"},
+		{2, "E"},
+		{15, "e"},
+		{-15, "f"},
+		{16, "gB"},
+		{1000, "w+B"},
+		{-1000, "x+B"},
+		{1<<31 - 1, "+/////D"},
+	}
+	for _, x := range tests {
+		if s := string(appendVLQ(nil, x.n)); s != x.s {
+			t.Errorf("appendVLQ(%d) = %q; want: %q", x.n, s, x.s)
+		}
+		n, size, err := decodeVLQ(x.s + ",")
+		if err != nil || n != x.n || size != len(x.s) {
+			t.Errorf("decodeVLQ(%q) = %d, %d, %v; want: %d, %d", x.s, n, size, err, x.n, len(x.s))
+		}
+	}
+	for _, s := range []string{"", "g", "!", "gggggggggB"} {
+		if _, _, err := decodeVLQ(s); err == nil {
+			t.Errorf("decodeVLQ(%q): expected error", s)
+		}
+	}
+}
+
+func TestDecodeMappings(t *testing.T) {
+	const mappings = "AAAA,IAAM;;AACA,EAAEC,G;"
+	want := Mappings{
+		{
+			{GenColumn: 0, HasSource: true},
+			{GenColumn: 4, OrigColumn: 6, HasSource: true},
+		},
+		nil,
+		{
+			{GenColumn: 0, OrigLine: 1, OrigColumn: 6, HasSource: true},
+			{GenColumn: 2, OrigLine: 1, OrigColumn: 8, Name: 1, HasSource: true, HasName: true},
+			{GenColumn: 5},
+		},
+		nil,
+	}
+	m, err := DecodeMappings(mappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("DecodeMappings(%q) = %+v; want: %+v", mappings, m, want)
+	}
+	if s := m.Encode(); s != mappings {
+		t.Errorf("Encode() = %q; want: %q", s, mappings)
+	}
+}
+
+func TestDecodeMappingsErrors(t *testing.T) {
+	for _, s := range []string{
+		"AA",     // 2 fields
+		"AAAAAA", // 6 fields
+		"D",      // negative column
+		"A!",     // invalid character
+		"Ag",     // unterminated
+	} {
+		if _, err := DecodeMappings(s); err == nil {
+			t.Errorf("DecodeMappings(%q): expected error", s)
+		}
+	}
+}
+
+func TestTextLine(t *testing.T) {
+	tx := newText("a\r\nb\rc\n\r\nd")
+	want := []string{"a", "b", "c", "", "d"}
+	for n, w := range want {
+		if line, ok := tx.line(n); !ok || line != w {
+			t.Errorf("line(%d) = %q, %t; want: %q", n, line, ok, w)
+		}
+	}
+	if _, ok := tx.line(len(want)); ok {
+		t.Errorf("line(%d): expected false", len(want))
+	}
+}
+
+func TestConvertColumns(t *testing.T) {
+	// U+1F600 is 4 bytes and 2 UTF-16 code units, 日 is 3 bytes and 1 unit.
+	generated := "var s=\"\U0001F600\";f(s)\r\n日(1)"
+	source := "let s = \"\U0001F600\"\nf(s) // 日\n日(1)"
+	sources := []*string{&source, nil}
+
+	utf16 := Mappings{
+		{
+			{GenColumn: 0, Source: 0, OrigLine: 0, OrigColumn: 0, HasSource: true},
+			{GenColumn: 10, Source: 0, OrigLine: 1, OrigColumn: 0, HasSource: true},
+			{GenColumn: 12, Source: 1, OrigLine: 7, OrigColumn: 7, HasSource: true},
+		},
+		{
+			{GenColumn: 1, Source: 0, OrigLine: 2, OrigColumn: 1, HasSource: true},
+			{GenColumn: 4},
+		},
+	}
+	bytes := Mappings{
+		{
+			{GenColumn: 0, Source: 0, OrigLine: 0, OrigColumn: 0, HasSource: true},
+			{GenColumn: 12, Source: 0, OrigLine: 1, OrigColumn: 0, HasSource: true},
+			{GenColumn: 14, Source: 1, OrigLine: 7, OrigColumn: 7, HasSource: true},
+		},
+		{
+			{GenColumn: 3, Source: 0, OrigLine: 2, OrigColumn: 3, HasSource: true},
+			{GenColumn: 6},
+		},
+	}
+	m, err := utf16.ToByteColumns(generated, sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, bytes) {
+		t.Errorf("ToByteColumns() = %+v; want: %+v", m, bytes)
+	}
+	m, err = bytes.ToUTF16Columns(generated, sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, utf16) {
+		t.Errorf("ToUTF16Columns() = %+v; want: %+v", m, utf16)
+	}
+
+	bad := Mappings{{{GenColumn: 100}}}
+	if _, err := bad.ToByteColumns(generated, sources); err == nil {
+		t.Error("ToByteColumns: expected error for out of range column")
+	}
+	bad = Mappings{{{GenColumn: 0, OrigLine: 10, HasSource: true}}}
+	if _, err := bad.ToUTF16Columns(generated, sources); err == nil {
+		t.Error("ToUTF16Columns: expected error for out of range line")
+	}
+}
+
+func TestMapJSON(t *testing.T) {
+	const data = `{"version":3,"file":"out.js","sources":["in.ts"],"names":["s"],"mappings":"AAAAA"}`
+	var m Map
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatal(err)
+	}
+	mappings, err := DecodeMappings(m.Mappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Mappings{{{HasSource: true, HasName: true}}}
+	if !reflect.DeepEqual(mappings, want) {
+		t.Errorf("DecodeMappings(%q) = %+v; want: %+v", m.Mappings, mappings, want)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("json.Marshal() = %s; want: %s", b, data)
+	}
+}
@@ -0,0 +1,121 @@
+package utfconv
+
+// A Truncator shortens text to a budget of UTF-16 code units, as imposed by
+// APIs such as Windows path fields, Active Directory attributes and
+// NVARCHAR(n) columns. Surrogate pairs are never split. The zero value
+// truncates at the last code point that fits.
+type Truncator struct {
+	// Graphemes prevents splitting extended grapheme clusters, such as a
+	// base character and its combining marks, emoji ZWJ sequences and
+	// flags, as defined by Unicode Standard Annex #29.
+	Graphemes bool
+
+	// Ellipsis, if not empty, is appended to truncated text. The budget
+	// includes the ellipsis, if it alone exceeds the budget it is omitted.
+	Ellipsis string
+}
+
+// TruncateUTF16 returns the longest prefix of s that can be encoded in at
+// most maxUnits UTF-16 code units without splitting a surrogate pair.
+func TruncateUTF16(s string, maxUnits int) string {
+	return Truncator{}.TruncateUTF16(s, maxUnits)
+}
+
+// TruncateUTF16Slice returns the longest prefix of s that is at most
+// maxUnits code units long and does not split a surrogate pair. The result
+// shares the underlying array of s.
+func TruncateUTF16Slice(s []uint16, maxUnits int) []uint16 {
+	return Truncator{}.TruncateUTF16Slice(s, maxUnits)
+}
+
+// TruncateUTF16 returns s truncated to encode in at most maxUnits UTF-16
+// code units, including the ellipsis. If s fits it is returned unchanged.
+func (t Truncator) TruncateUTF16(s string, maxUnits int) string {
+	if maxUnits < 0 {
+		maxUnits = 0
+	}
+	if UTF16EncodedLenString(s) <= maxUnits {
+		return s
+	}
+	ellipsis := t.Ellipsis
+	n := UTF16EncodedLenString(ellipsis)
+	if n > maxUnits {
+		ellipsis = ""
+		n = 0
+	}
+	// s does not fit so the index is always in range
+	i := ByteIndexUTF16(s, maxUnits-n)
+	if t.Graphemes {
+		i = graphemeStart(s, i)
+	}
+	return s[:i] + ellipsis
+}
+
+// TruncateUTF16Slice returns s truncated to at most maxUnits code units,
+// including the ellipsis. If s fits it is returned unchanged, otherwise
+// without an ellipsis the result shares the underlying array of s.
+func (t Truncator) TruncateUTF16Slice(s []uint16, maxUnits int) []uint16 {
+	if maxUnits < 0 {
+		maxUnits = 0
+	}
+	if len(s) <= maxUnits {
+		return s
+	}
+	ellipsis := StringToUTF16(t.Ellipsis)
+	if len(ellipsis) > maxUnits {
+		ellipsis = nil
+	}
+	i := maxUnits - len(ellipsis)
+	if isSurrogatePair(s, i) {
+		i--
+	}
+	if t.Graphemes {
+		i = graphemeStartUTF16(s, i)
+	}
+	if len(ellipsis) == 0 {
+		return s[:i]
+	}
+	a := make([]uint16, i+len(ellipsis))
+	copy(a[copy(a, s[:i]):], ellipsis)
+	return a
+}
+
+// graphemeStart returns the start of the grapheme cluster of s that
+// contains the code point at byte offset i, which must be less than len(s).
+func graphemeStart(s string, i int) int {
+	var st graphemeState
+	start := 0
+	for j, r := range s {
+		if j > i {
+			break
+		}
+		if st.step(graphemeProp(r)) {
+			start = j
+		}
+	}
+	return start
+}
+
+// graphemeStartUTF16 is like graphemeStart but i is an offset into the
+// UTF-16 slice s, unpaired surrogates are treated as U+FFFD.
+func graphemeStartUTF16(s []uint16, i int) int {
+	var st graphemeState
+	start := 0
+	for j := 0; j <= i; {
+		r, n := DecodeRuneUTF16(s[j:])
+		if st.step(graphemeProp(r)) {
+			start = j
+		}
+		j += n
+	}
+	return start
+}
+
+// isSurrogatePair reports whether s[i-1] and s[i] form a surrogate pair.
+func isSurrogatePair(s []uint16, i int) bool {
+	if i <= 0 || i >= len(s) {
+		return false
+	}
+	_, n := DecodeRuneUTF16(s[i-1 : i+1])
+	return n == 2
+}
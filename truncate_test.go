@@ -0,0 +1,86 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+var truncateTests = []struct {
+	t   Truncator
+	in  string
+	max int
+	out string
+}{
+	{Truncator{}, "abc", 3, "abc"},
+	{Truncator{}, "abc", 2, "ab"},
+	{Truncator{}, "abc", 0, ""},
+	{Truncator{}, "abc", -1, ""},
+	{Truncator{}, "日本語", 2, "日本"},
+	{Truncator{}, "a\U0001F600b", 2, "a"},
+	{Truncator{}, "a\U0001F600b", 3, "a\U0001F600"},
+	{Truncator{}, "\xffab", 2, "\xffa"},
+
+	// ellipsis
+	{Truncator{Ellipsis: "…"}, "abc", 3, "abc"},
+	{Truncator{Ellipsis: "…"}, "abcd", 3, "ab…"},
+	{Truncator{Ellipsis: "…"}, "abcd", 1, "…"},
+	{Truncator{Ellipsis: "..."}, "abcd", 2, "ab"},
+	{Truncator{Ellipsis: "…"}, "a\U0001F600b", 3, "a…"},
+
+	// grapheme clusters
+	{Truncator{}, "ae\u0301", 2, "ae"},
+	{Truncator{Graphemes: true}, "ae\u0301", 2, "a"},
+	{Truncator{Graphemes: true}, "ae\u0301\u0302b", 3, "a"},
+	{Truncator{Graphemes: true}, "ae\u0301b", 3, "ae\u0301"},
+	{Truncator{Graphemes: true}, "a\r\n", 2, "a"},
+	{Truncator{Graphemes: true}, "x\U0001F44D\U0001F3FD", 3, "x"},
+	{Truncator{Graphemes: true}, "x\U0001F468\u200D\U0001F469\u200D\U0001F467", 6, "x"},
+	{Truncator{Graphemes: true}, "❤\uFE0F", 1, ""},
+	// flags are pairs of regional indicators
+	{Truncator{Graphemes: true}, "\U0001F1FA\U0001F1F8\U0001F1EB\U0001F1F7", 6, "\U0001F1FA\U0001F1F8"},
+	{Truncator{Graphemes: true}, "\U0001F1FA\U0001F1F8\U0001F1EB\U0001F1F7", 4, "\U0001F1FA\U0001F1F8"},
+	// Hangul: 한 as L V T jamo
+	{Truncator{Graphemes: true}, "a\u1112\u1161\u11AB", 3, "a"},
+	{Truncator{Graphemes: true, Ellipsis: "…"}, "ae\u0301bc", 3, "a…"},
+}
+
+func TestTruncateUTF16(t *testing.T) {
+	for _, x := range truncateTests {
+		s := x.t.TruncateUTF16(x.in, x.max)
+		if s != x.out {
+			t.Errorf("%+v.TruncateUTF16(%q, %d) = %q; want: %q", x.t, x.in, x.max, s, x.out)
+		}
+		if n := UTF16EncodedLenString(s); x.max >= 0 && n > x.max {
+			t.Errorf("%+v.TruncateUTF16(%q, %d): length %d exceeds budget", x.t, x.in, x.max, n)
+		}
+	}
+}
+
+func TestTruncateUTF16Slice(t *testing.T) {
+	for _, x := range truncateTests {
+		u := x.t.TruncateUTF16Slice(utf16.Encode([]rune(x.in)), x.max)
+		exp := utf16.Encode([]rune(x.out))
+		if !reflect.DeepEqual(u, exp) && !(len(u) == 0 && len(exp) == 0) {
+			t.Errorf("%+v.TruncateUTF16Slice(%q, %d) = %q; want: %q", x.t, x.in, x.max,
+				string(utf16.Decode(u)), x.out)
+		}
+	}
+}
+
+func TestTruncateUTF16Lone(t *testing.T) {
+	// lone surrogates are kept as is
+	s := []uint16{'a', 0xd800, 'b', 0xdc00}
+	if u := TruncateUTF16Slice(s, 2); !reflect.DeepEqual(u, s[:2]) {
+		t.Errorf("TruncateUTF16Slice(%v, 2) = %v; want: %v", s, u, s[:2])
+	}
+	if u := TruncateUTF16Slice(s, 4); !reflect.DeepEqual(u, s) {
+		t.Errorf("TruncateUTF16Slice(%v, 4) = %v; want: %v", s, u, s)
+	}
+	if u := TruncateUTF16Slice([]uint16{0xd800, 0xdc00}, 1); len(u) != 0 {
+		t.Errorf("TruncateUTF16Slice: split surrogate pair: %v", u)
+	}
+	if s := TruncateUTF16("\U0001F600", 1); s != "" {
+		t.Errorf("TruncateUTF16: split surrogate pair: %q", s)
+	}
+}
@@ -450,8 +450,14 @@ Loop:
 }
 
 func StringToUTF16(s string) []uint16 {
+	return stringToUTF16(s, 0)
+}
+
+// stringToUTF16 returns the UTF-16 encoding of s followed by extra zero
+// code units.
+func stringToUTF16(s string, extra int) []uint16 {
 	na, ok := encodedLenString(s)
-	a := make([]uint16, na)
+	a := make([]uint16, na+extra)
 	if ok && na == len(s) {
 		for i := 0; i < len(s); i++ {
 			a[i] = uint16(s[i])
@@ -477,5 +483,5 @@ func StringToUTF16(s string) []uint16 {
 			n++
 		}
 	}
-	return a[:n]
+	return a[:n+extra]
 }
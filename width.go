@@ -0,0 +1,156 @@
+package utfconv
+
+import "unicode/utf8"
+
+//go:generate go run gen_width.go
+
+// Variation selectors that request text and emoji presentation.
+const (
+	vs15 = 0xFE0E
+	vs16 = 0xFE0F
+)
+
+// runeWidth returns the width of r with grapheme cluster break property p
+// in a monospace font.
+func runeWidth(r rune, p breakProperty) int {
+	switch p {
+	case gbControl, gbCR, gbLF, gbExtend, gbZWJ:
+		return 0
+	case gbRegionalIndicator:
+		return 2
+	}
+	if r < runeSelf {
+		return 1
+	}
+	if w := lookupProp(wideRanges[:], r); w != 0 {
+		return int(w)
+	}
+	return 1
+}
+
+// widthCounter computes the width of text one code point at a time.
+type widthCounter struct {
+	st      graphemeState
+	started bool
+	first   breakProperty // property of the first code point of the cluster
+	n       int           // width of the preceding grapheme clusters
+	cur     int           // width of the current grapheme cluster
+}
+
+// add adds r to the text and reports whether it starts a grapheme cluster.
+func (c *widthCounter) add(r rune) bool {
+	// printable ASCII starts a cluster unless it follows a Prepend (GB9b)
+	if ' ' <= r && r < 0x7F && c.st.prev != gbPrepend {
+		c.started = true
+		c.n += c.cur
+		c.first, c.cur = gbOther, 1
+		c.st = graphemeState{prev: gbOther}
+		return true
+	}
+	p := graphemeProp(r)
+	if c.st.step(p) || !c.started {
+		c.started = true
+		c.n += c.cur
+		c.first, c.cur = p, runeWidth(r, p)
+		return true
+	}
+	switch {
+	case c.first == gbExtendedPictographic && r == vs15:
+		c.cur = 1
+	case c.first == gbExtendedPictographic && r == vs16:
+		c.cur = 2
+	case p == gbSpacingMark:
+		c.cur += runeWidth(r, p)
+	}
+	return false
+}
+
+func (c *widthCounter) width() int { return c.n + c.cur }
+
+// Width returns the number of columns the UTF-16 slice s occupies in a
+// monospace font, such as a terminal. Unpaired surrogates are treated as
+// U+FFFD.
+//
+// The width of a grapheme cluster is the width of its first code point plus
+// that of any spacing marks. Control characters, combining marks and other
+// zero width characters have width 0. East Asian Wide and Fullwidth
+// characters, regional indicator pairs and emoji with default emoji
+// presentation have width 2, as do pictographs followed by U+FE0F. Other
+// characters, including those of East Asian Ambiguous width, have width 1.
+func Width(s []uint16) int {
+	var c widthCounter
+	for i := 0; i < len(s); {
+		r, n := DecodeRuneUTF16(s[i:])
+		c.add(r)
+		i += n
+	}
+	return c.width()
+}
+
+// WidthString returns the number of columns the string s occupies in a
+// monospace font. Invalid UTF-8 is treated as U+FFFD. See Width.
+func WidthString(s string) int {
+	var c widthCounter
+	for i := 0; i < len(s); {
+		r, n := rune(s[i]), 1
+		if r >= runeSelf {
+			r, n = utf8.DecodeRuneInString(s[i:])
+		}
+		c.add(r)
+		i += n
+	}
+	return c.width()
+}
+
+// TruncateWidth returns s truncated to at most maxWidth columns, including
+// tail, which is appended if s is truncated. Grapheme clusters are never
+// split. If tail alone is wider than maxWidth it is omitted. If s fits it is
+// returned unchanged, otherwise without a tail the result shares the
+// underlying array of s.
+func TruncateWidth(s []uint16, maxWidth int, tail string) []uint16 {
+	tw := WidthString(tail)
+	if tw > maxWidth {
+		tail, tw = "", 0
+	}
+	var c widthCounter
+	end := 0 // end of the clusters that fit with tail
+	for i := 0; i < len(s); {
+		r, n := DecodeRuneUTF16(s[i:])
+		if c.add(r) && c.n <= maxWidth-tw {
+			end = i
+		}
+		if c.width() > maxWidth {
+			if tail == "" {
+				return s[:end]
+			}
+			return append(s[:end:end], StringToUTF16(tail)...)
+		}
+		i += n
+	}
+	return s
+}
+
+// TruncateWidthString returns s truncated to at most maxWidth columns,
+// including tail. See TruncateWidth.
+func TruncateWidthString(s string, maxWidth int, tail string) string {
+	tw := WidthString(tail)
+	if tw > maxWidth {
+		tail, tw = "", 0
+	}
+	var c widthCounter
+	end := 0
+	for i := 0; i < len(s); {
+		r, n := rune(s[i]), 1
+		if r >= runeSelf {
+			r, n = utf8.DecodeRuneInString(s[i:])
+		}
+		if c.add(r) && c.n <= maxWidth-tw {
+			end = i
+		}
+		if c.width() > maxWidth {
+			return s[:end] + tail
+		}
+		i += n
+	}
+	return s
+}
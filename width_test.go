@@ -0,0 +1,152 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+var widthTests = []struct {
+	s   string
+	exp int
+}{
+	{"", 0},
+	{"abc", 3},
+	{"a\tb\n", 2},
+	{"\x00\x7f", 0},
+	{"日本語", 6},
+	{"ｱｲｳ", 3},      // halfwidth katakana
+	{"ＡＢＣ", 6},      // fullwidth latin
+	{"\u3000", 2},   // ideographic space
+	{"e\u0301", 1},  // combining acute accent
+	{"\u0301", 0},   // lone combining mark
+	{"a\u200Bb", 2}, // zero width space
+	{"a\u00ADb", 2}, // soft hyphen
+	{"क\u093F", 2},  // spacing mark
+	{"한", 2},
+	{"한국어", 6},
+	{"\u1112\u1161\u11AB", 2}, // conjoining jamo
+	{"\u06001", 1},            // prepend
+	{"±", 1},                  // ambiguous
+	{"\U0001F600", 2},
+	{"\U0001F44D\U0001F3FD", 2},
+	{"\U0001F468\u200D\U0001F469\u200D\U0001F467", 2},
+	{"\U0001F1FA\U0001F1F8", 2},
+	{"\U0001F1FA", 2},
+	{"❤", 1},
+	{"❤\uFE0F", 2},
+	{"⌚\uFE0E", 1},
+	{"\U00020000", 2}, // CJK extension B
+	{"\U0002B73F", 2}, // unassigned in a wide block
+	{"\xff", 1},
+}
+
+func TestWidth(t *testing.T) {
+	for _, x := range widthTests {
+		if n := WidthString(x.s); n != x.exp {
+			t.Errorf("WidthString(%q) = %d; want: %d", x.s, n, x.exp)
+		}
+		if n := Width(utf16.Encode([]rune(x.s))); n != x.exp {
+			t.Errorf("Width(%q) = %d; want: %d", x.s, n, x.exp)
+		}
+	}
+}
+
+func TestWidthSurrogates(t *testing.T) {
+	tests := []struct {
+		s   []uint16
+		exp int
+	}{
+		{[]uint16{0xD800}, 1},
+		{[]uint16{'a', 0xDC00, 'b'}, 3},
+		{[]uint16{0xD83D, 0xDE00}, 2},
+		{[]uint16{0xDE00, 0xD83D}, 2},
+	}
+	for _, x := range tests {
+		if n := Width(x.s); n != x.exp {
+			t.Errorf("Width(%04X) = %d; want: %d", x.s, n, x.exp)
+		}
+	}
+}
+
+var truncateWidthTests = []struct {
+	in   string
+	max  int
+	tail string
+	out  string
+}{
+	{"abc", 3, "", "abc"},
+	{"abc", 2, "", "ab"},
+	{"abc", 0, "", ""},
+	{"abc", -1, "", ""},
+	{"日本語", 6, "", "日本語"},
+	{"日本語", 5, "", "日本"},
+	{"日本語", 3, "", "日"},
+	{"a日本", 2, "", "a"},
+	{"ae\u0301b", 2, "", "ae\u0301"},
+	{"ae\u0301\u0302b", 2, "", "ae\u0301\u0302"},
+	{"a\U0001F468\u200D\U0001F469b", 2, "", "a"},
+	{"a\U0001F468\u200D\U0001F469b", 3, "", "a\U0001F468\u200D\U0001F469"},
+	{"a❤\uFE0F", 2, "", "a"},
+	{"한a", 2, "", "한"},
+
+	// tail
+	{"abc", 3, "…", "abc"},
+	{"abcd", 3, "…", "ab…"},
+	{"abcd", 1, "…", "…"},
+	{"abcd", 2, "...", "ab"},
+	{"日本語", 5, "…", "日本…"},
+	{"日本語", 4, "…", "日…"},
+	{"a日本語", 4, "…", "a日…"},
+}
+
+func TestTruncateWidth(t *testing.T) {
+	for _, x := range truncateWidthTests {
+		s := TruncateWidthString(x.in, x.max, x.tail)
+		if s != x.out {
+			t.Errorf("TruncateWidthString(%q, %d, %q) = %q; want: %q", x.in, x.max, x.tail, s, x.out)
+		}
+		if n := WidthString(s); x.max >= 0 && n > x.max {
+			t.Errorf("TruncateWidthString(%q, %d, %q): width %d exceeds maximum", x.in, x.max, x.tail, n)
+		}
+		u := TruncateWidth(utf16.Encode([]rune(x.in)), x.max, x.tail)
+		exp := utf16.Encode([]rune(x.out))
+		if !reflect.DeepEqual(u, exp) && !(len(u) == 0 && len(exp) == 0) {
+			t.Errorf("TruncateWidth(%q, %d, %q) = %q; want: %q", x.in, x.max, x.tail,
+				string(utf16.Decode(u)), x.out)
+		}
+	}
+}
+
+func TestTruncateWidthShared(t *testing.T) {
+	s := utf16.Encode([]rune("abcdef"))
+	u := TruncateWidth(s, 3, "")
+	if &u[0] != &s[0] {
+		t.Error("TruncateWidth: expected result to share the array of s")
+	}
+	u = TruncateWidth(s, 3, ".")
+	if &u[0] == &s[0] {
+		t.Error("TruncateWidth: result with tail shares the array of s")
+	}
+	if s[2] != 'c' {
+		t.Errorf("TruncateWidth: modified s: %q", string(utf16.Decode(s)))
+	}
+}
+
+func BenchmarkWidthString_SixtyFourASCII(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		WidthString(SixtyFourASCIIChars)
+	}
+}
+
+func BenchmarkWidthString_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		WidthString(SixtyFourUnicodeChars)
+	}
+}
+
+func BenchmarkWidth_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Width(SixtyFourUnicodeCharsUTF16)
+	}
+}
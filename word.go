@@ -0,0 +1,163 @@
+package utfconv
+
+// Word_Break property values.
+const (
+	wbOther breakProperty = iota
+	wbCR
+	wbLF
+	wbNewline
+	wbExtend
+	wbZWJ
+	wbRegionalIndicator
+	wbFormat
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+)
+
+// wordProp returns the word break property of r.
+func wordProp(r rune) breakProperty {
+	if r < runeSelf {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+			return wbALetter
+		case '0' <= r && r <= '9':
+			return wbNumeric
+		}
+	}
+	return lookupProp(wordRanges[:], r)
+}
+
+// wordIgnored reports whether rule WB4 ignores code points with property p.
+func wordIgnored(p breakProperty) bool {
+	return p == wbExtend || p == wbFormat || p == wbZWJ
+}
+
+func isAHLetter(p breakProperty) bool { return p == wbALetter || p == wbHebrewLetter }
+
+func isMidNumLetQ(p breakProperty) bool { return p == wbMidNumLet || p == wbSingleQuote }
+
+// wordState holds the context needed to apply the word boundary rules of
+// UAX #29 to the next code point.
+type wordState struct {
+	raw   breakProperty // previous code point
+	prev  breakProperty // previous code point ignoring Extend, Format and ZWJ
+	prev2 breakProperty // code point before prev
+	ri    bool          // text ends with an odd number of regional indicators
+}
+
+// A WordIterator iterates over the words of a string or a UTF-16 slice, as
+// defined by the word boundary rules of Unicode Standard Annex #29. Segments
+// between words, such as spaces and punctuation, are also returned. Invalid
+// UTF-8 and unpaired surrogates are treated as U+FFFD.
+type WordIterator struct {
+	segmenter
+	st wordState
+}
+
+// NewWordIterator returns a WordIterator over the string s.
+func NewWordIterator(s string) *WordIterator {
+	it := &WordIterator{st: wordState{raw: sot, prev: sot, prev2: sot}}
+	it.init(s, nil, false, it.breakBefore)
+	return it
+}
+
+// NewWordIteratorUTF16 returns a WordIterator over the UTF-16 slice s.
+func NewWordIteratorUTF16(s []uint16) *WordIterator {
+	it := &WordIterator{st: wordState{raw: sot, prev: sot, prev2: sot}}
+	it.init("", s, true, it.breakBefore)
+	return it
+}
+
+// Next advances the iterator to the next segment and reports whether there
+// is one.
+func (it *WordIterator) Next() bool { return it.next() }
+
+// Word returns the position of the current segment.
+func (it *WordIterator) Word() Segment { return it.seg }
+
+// lookahead returns the property of the first code point after the one at
+// native offset i that is not ignored by rule WB4.
+func (it *WordIterator) lookahead(i int) breakProperty {
+	_, n := it.decode(i)
+	for i += n; i < it.len(); i += n {
+		var r rune
+		r, n = it.decode(i)
+		if p := wordProp(r); !wordIgnored(p) {
+			return p
+		}
+	}
+	return wbOther
+}
+
+func (it *WordIterator) breakBefore(r rune, i int) bool {
+	p := wordProp(r)
+	st := &it.st
+	raw, prev, prev2, ri := st.raw, st.prev, st.prev2, st.ri
+	st.raw = p
+	if !wordIgnored(p) || raw == sot || raw == wbCR || raw == wbLF || raw == wbNewline {
+		st.prev2, st.prev = prev, p
+		st.ri = p == wbRegionalIndicator && !ri
+	}
+
+	switch {
+	case raw == wbCR && p == wbLF: // WB3
+		return false
+	case raw == wbCR || raw == wbLF || raw == wbNewline: // WB3a
+		return true
+	case p == wbCR || p == wbLF || p == wbNewline: // WB3b
+		return true
+	case raw == wbZWJ && graphemeProp(r) == gbExtendedPictographic: // WB3c
+		return false
+	case raw == wbWSegSpace && p == wbWSegSpace: // WB3d
+		return false
+	case wordIgnored(p): // WB4
+		return false
+	case isAHLetter(prev) && isAHLetter(p): // WB5
+		return false
+	case isAHLetter(prev) && (p == wbMidLetter || isMidNumLetQ(p)) &&
+		isAHLetter(it.lookahead(i)): // WB6
+		return false
+	case isAHLetter(prev2) && (prev == wbMidLetter || isMidNumLetQ(prev)) &&
+		isAHLetter(p): // WB7
+		return false
+	case prev == wbHebrewLetter && p == wbSingleQuote: // WB7a
+		return false
+	case prev == wbHebrewLetter && p == wbDoubleQuote &&
+		it.lookahead(i) == wbHebrewLetter: // WB7b
+		return false
+	case prev2 == wbHebrewLetter && prev == wbDoubleQuote && p == wbHebrewLetter: // WB7c
+		return false
+	case prev == wbNumeric && p == wbNumeric: // WB8
+		return false
+	case isAHLetter(prev) && p == wbNumeric: // WB9
+		return false
+	case prev == wbNumeric && isAHLetter(p): // WB10
+		return false
+	case prev2 == wbNumeric && (prev == wbMidNum || isMidNumLetQ(prev)) &&
+		p == wbNumeric: // WB11
+		return false
+	case prev == wbNumeric && (p == wbMidNum || isMidNumLetQ(p)) &&
+		it.lookahead(i) == wbNumeric: // WB12
+		return false
+	case prev == wbKatakana && p == wbKatakana: // WB13
+		return false
+	case (isAHLetter(prev) || prev == wbNumeric || prev == wbKatakana ||
+		prev == wbExtendNumLet) && p == wbExtendNumLet: // WB13a
+		return false
+	case prev == wbExtendNumLet && (isAHLetter(p) || p == wbNumeric ||
+		p == wbKatakana): // WB13b
+		return false
+	case ri && p == wbRegionalIndicator: // WB15, WB16
+		return false
+	}
+	return true // WB999
+}
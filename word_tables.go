@@ -0,0 +1,1061 @@
+// Code generated by gen_segment.go; DO NOT EDIT.
+
+package utfconv
+
+// wordRanges are the Word_Break property values of Unicode 15.0.0
+// sorted by code point.
+var wordRanges = [...]propRange{
+	{0x000A, 0x000A, wbLF},
+	{0x000B, 0x000C, wbNewline},
+	{0x000D, 0x000D, wbCR},
+	{0x0020, 0x0020, wbWSegSpace},
+	{0x0022, 0x0022, wbDoubleQuote},
+	{0x0027, 0x0027, wbSingleQuote},
+	{0x002C, 0x002C, wbMidNum},
+	{0x002E, 0x002E, wbMidNumLet},
+	{0x0030, 0x0039, wbNumeric},
+	{0x003A, 0x003A, wbMidLetter},
+	{0x003B, 0x003B, wbMidNum},
+	{0x0041, 0x005A, wbALetter},
+	{0x005F, 0x005F, wbExtendNumLet},
+	{0x0061, 0x007A, wbALetter},
+	{0x0085, 0x0085, wbNewline},
+	{0x00AA, 0x00AA, wbALetter},
+	{0x00AD, 0x00AD, wbFormat},
+	{0x00B5, 0x00B5, wbALetter},
+	{0x00B7, 0x00B7, wbMidLetter},
+	{0x00BA, 0x00BA, wbALetter},
+	{0x00C0, 0x00D6, wbALetter},
+	{0x00D8, 0x00F6, wbALetter},
+	{0x00F8, 0x02D7, wbALetter},
+	{0x02DE, 0x02FF, wbALetter},
+	{0x0300, 0x036F, wbExtend},
+	{0x0370, 0x0374, wbALetter},
+	{0x0376, 0x0377, wbALetter},
+	{0x037A, 0x037D, wbALetter},
+	{0x037E, 0x037E, wbMidNum},
+	{0x037F, 0x037F, wbALetter},
+	{0x0386, 0x0386, wbALetter},
+	{0x0387, 0x0387, wbMidLetter},
+	{0x0388, 0x038A, wbALetter},
+	{0x038C, 0x038C, wbALetter},
+	{0x038E, 0x03A1, wbALetter},
+	{0x03A3, 0x03F5, wbALetter},
+	{0x03F7, 0x0481, wbALetter},
+	{0x0483, 0x0489, wbExtend},
+	{0x048A, 0x052F, wbALetter},
+	{0x0531, 0x0556, wbALetter},
+	{0x0559, 0x055C, wbALetter},
+	{0x055E, 0x055E, wbALetter},
+	{0x055F, 0x055F, wbMidLetter},
+	{0x0560, 0x0588, wbALetter},
+	{0x0589, 0x0589, wbMidNum},
+	{0x058A, 0x058A, wbALetter},
+	{0x0591, 0x05BD, wbExtend},
+	{0x05BF, 0x05BF, wbExtend},
+	{0x05C1, 0x05C2, wbExtend},
+	{0x05C4, 0x05C5, wbExtend},
+	{0x05C7, 0x05C7, wbExtend},
+	{0x05D0, 0x05EA, wbHebrewLetter},
+	{0x05EF, 0x05F2, wbHebrewLetter},
+	{0x05F3, 0x05F3, wbALetter},
+	{0x05F4, 0x05F4, wbMidLetter},
+	{0x0600, 0x0605, wbFormat},
+	{0x060C, 0x060D, wbMidNum},
+	{0x0610, 0x061A, wbExtend},
+	{0x061C, 0x061C, wbFormat},
+	{0x0620, 0x064A, wbALetter},
+	{0x064B, 0x065F, wbExtend},
+	{0x0660, 0x0669, wbNumeric},
+	{0x066B, 0x066B, wbNumeric},
+	{0x066C, 0x066C, wbMidNum},
+	{0x066E, 0x066F, wbALetter},
+	{0x0670, 0x0670, wbExtend},
+	{0x0671, 0x06D3, wbALetter},
+	{0x06D5, 0x06D5, wbALetter},
+	{0x06D6, 0x06DC, wbExtend},
+	{0x06DD, 0x06DD, wbFormat},
+	{0x06DF, 0x06E4, wbExtend},
+	{0x06E5, 0x06E6, wbALetter},
+	{0x06E7, 0x06E8, wbExtend},
+	{0x06EA, 0x06ED, wbExtend},
+	{0x06EE, 0x06EF, wbALetter},
+	{0x06F0, 0x06F9, wbNumeric},
+	{0x06FA, 0x06FC, wbALetter},
+	{0x06FF, 0x06FF, wbALetter},
+	{0x070F, 0x070F, wbFormat},
+	{0x0710, 0x0710, wbALetter},
+	{0x0711, 0x0711, wbExtend},
+	{0x0712, 0x072F, wbALetter},
+	{0x0730, 0x074A, wbExtend},
+	{0x074D, 0x07A5, wbALetter},
+	{0x07A6, 0x07B0, wbExtend},
+	{0x07B1, 0x07B1, wbALetter},
+	{0x07C0, 0x07C9, wbNumeric},
+	{0x07CA, 0x07EA, wbALetter},
+	{0x07EB, 0x07F3, wbExtend},
+	{0x07F4, 0x07F5, wbALetter},
+	{0x07F8, 0x07F8, wbMidNum},
+	{0x07FA, 0x07FA, wbALetter},
+	{0x07FD, 0x07FD, wbExtend},
+	{0x0800, 0x0815, wbALetter},
+	{0x0816, 0x0819, wbExtend},
+	{0x081A, 0x081A, wbALetter},
+	{0x081B, 0x0823, wbExtend},
+	{0x0824, 0x0824, wbALetter},
+	{0x0825, 0x0827, wbExtend},
+	{0x0828, 0x0828, wbALetter},
+	{0x0829, 0x082D, wbExtend},
+	{0x0840, 0x0858, wbALetter},
+	{0x0859, 0x085B, wbExtend},
+	{0x0860, 0x086A, wbALetter},
+	{0x0870, 0x0887, wbALetter},
+	{0x0889, 0x088E, wbALetter},
+	{0x0890, 0x0891, wbFormat},
+	{0x0898, 0x089F, wbExtend},
+	{0x08A0, 0x08C9, wbALetter},
+	{0x08CA, 0x08E1, wbExtend},
+	{0x08E2, 0x08E2, wbFormat},
+	{0x08E3, 0x0903, wbExtend},
+	{0x0904, 0x0939, wbALetter},
+	{0x093A, 0x093C, wbExtend},
+	{0x093D, 0x093D, wbALetter},
+	{0x093E, 0x094F, wbExtend},
+	{0x0950, 0x0950, wbALetter},
+	{0x0951, 0x0957, wbExtend},
+	{0x0958, 0x0961, wbALetter},
+	{0x0962, 0x0963, wbExtend},
+	{0x0966, 0x096F, wbNumeric},
+	{0x0971, 0x0980, wbALetter},
+	{0x0981, 0x0983, wbExtend},
+	{0x0985, 0x098C, wbALetter},
+	{0x098F, 0x0990, wbALetter},
+	{0x0993, 0x09A8, wbALetter},
+	{0x09AA, 0x09B0, wbALetter},
+	{0x09B2, 0x09B2, wbALetter},
+	{0x09B6, 0x09B9, wbALetter},
+	{0x09BC, 0x09BC, wbExtend},
+	{0x09BD, 0x09BD, wbALetter},
+	{0x09BE, 0x09C4, wbExtend},
+	{0x09C7, 0x09C8, wbExtend},
+	{0x09CB, 0x09CD, wbExtend},
+	{0x09CE, 0x09CE, wbALetter},
+	{0x09D7, 0x09D7, wbExtend},
+	{0x09DC, 0x09DD, wbALetter},
+	{0x09DF, 0x09E1, wbALetter},
+	{0x09E2, 0x09E3, wbExtend},
+	{0x09E6, 0x09EF, wbNumeric},
+	{0x09F0, 0x09F1, wbALetter},
+	{0x09FC, 0x09FC, wbALetter},
+	{0x09FE, 0x09FE, wbExtend},
+	{0x0A01, 0x0A03, wbExtend},
+	{0x0A05, 0x0A0A, wbALetter},
+	{0x0A0F, 0x0A10, wbALetter},
+	{0x0A13, 0x0A28, wbALetter},
+	{0x0A2A, 0x0A30, wbALetter},
+	{0x0A32, 0x0A33, wbALetter},
+	{0x0A35, 0x0A36, wbALetter},
+	{0x0A38, 0x0A39, wbALetter},
+	{0x0A3C, 0x0A3C, wbExtend},
+	{0x0A3E, 0x0A42, wbExtend},
+	{0x0A47, 0x0A48, wbExtend},
+	{0x0A4B, 0x0A4D, wbExtend},
+	{0x0A51, 0x0A51, wbExtend},
+	{0x0A59, 0x0A5C, wbALetter},
+	{0x0A5E, 0x0A5E, wbALetter},
+	{0x0A66, 0x0A6F, wbNumeric},
+	{0x0A70, 0x0A71, wbExtend},
+	{0x0A72, 0x0A74, wbALetter},
+	{0x0A75, 0x0A75, wbExtend},
+	{0x0A81, 0x0A83, wbExtend},
+	{0x0A85, 0x0A8D, wbALetter},
+	{0x0A8F, 0x0A91, wbALetter},
+	{0x0A93, 0x0AA8, wbALetter},
+	{0x0AAA, 0x0AB0, wbALetter},
+	{0x0AB2, 0x0AB3, wbALetter},
+	{0x0AB5, 0x0AB9, wbALetter},
+	{0x0ABC, 0x0ABC, wbExtend},
+	{0x0ABD, 0x0ABD, wbALetter},
+	{0x0ABE, 0x0AC5, wbExtend},
+	{0x0AC7, 0x0AC9, wbExtend},
+	{0x0ACB, 0x0ACD, wbExtend},
+	{0x0AD0, 0x0AD0, wbALetter},
+	{0x0AE0, 0x0AE1, wbALetter},
+	{0x0AE2, 0x0AE3, wbExtend},
+	{0x0AE6, 0x0AEF, wbNumeric},
+	{0x0AF9, 0x0AF9, wbALetter},
+	{0x0AFA, 0x0AFF, wbExtend},
+	{0x0B01, 0x0B03, wbExtend},
+	{0x0B05, 0x0B0C, wbALetter},
+	{0x0B0F, 0x0B10, wbALetter},
+	{0x0B13, 0x0B28, wbALetter},
+	{0x0B2A, 0x0B30, wbALetter},
+	{0x0B32, 0x0B33, wbALetter},
+	{0x0B35, 0x0B39, wbALetter},
+	{0x0B3C, 0x0B3C, wbExtend},
+	{0x0B3D, 0x0B3D, wbALetter},
+	{0x0B3E, 0x0B44, wbExtend},
+	{0x0B47, 0x0B48, wbExtend},
+	{0x0B4B, 0x0B4D, wbExtend},
+	{0x0B55, 0x0B57, wbExtend},
+	{0x0B5C, 0x0B5D, wbALetter},
+	{0x0B5F, 0x0B61, wbALetter},
+	{0x0B62, 0x0B63, wbExtend},
+	{0x0B66, 0x0B6F, wbNumeric},
+	{0x0B71, 0x0B71, wbALetter},
+	{0x0B82, 0x0B82, wbExtend},
+	{0x0B83, 0x0B83, wbALetter},
+	{0x0B85, 0x0B8A, wbALetter},
+	{0x0B8E, 0x0B90, wbALetter},
+	{0x0B92, 0x0B95, wbALetter},
+	{0x0B99, 0x0B9A, wbALetter},
+	{0x0B9C, 0x0B9C, wbALetter},
+	{0x0B9E, 0x0B9F, wbALetter},
+	{0x0BA3, 0x0BA4, wbALetter},
+	{0x0BA8, 0x0BAA, wbALetter},
+	{0x0BAE, 0x0BB9, wbALetter},
+	{0x0BBE, 0x0BC2, wbExtend},
+	{0x0BC6, 0x0BC8, wbExtend},
+	{0x0BCA, 0x0BCD, wbExtend},
+	{0x0BD0, 0x0BD0, wbALetter},
+	{0x0BD7, 0x0BD7, wbExtend},
+	{0x0BE6, 0x0BEF, wbNumeric},
+	{0x0C00, 0x0C04, wbExtend},
+	{0x0C05, 0x0C0C, wbALetter},
+	{0x0C0E, 0x0C10, wbALetter},
+	{0x0C12, 0x0C28, wbALetter},
+	{0x0C2A, 0x0C39, wbALetter},
+	{0x0C3C, 0x0C3C, wbExtend},
+	{0x0C3D, 0x0C3D, wbALetter},
+	{0x0C3E, 0x0C44, wbExtend},
+	{0x0C46, 0x0C48, wbExtend},
+	{0x0C4A, 0x0C4D, wbExtend},
+	{0x0C55, 0x0C56, wbExtend},
+	{0x0C58, 0x0C5A, wbALetter},
+	{0x0C5D, 0x0C5D, wbALetter},
+	{0x0C60, 0x0C61, wbALetter},
+	{0x0C62, 0x0C63, wbExtend},
+	{0x0C66, 0x0C6F, wbNumeric},
+	{0x0C80, 0x0C80, wbALetter},
+	{0x0C81, 0x0C83, wbExtend},
+	{0x0C85, 0x0C8C, wbALetter},
+	{0x0C8E, 0x0C90, wbALetter},
+	{0x0C92, 0x0CA8, wbALetter},
+	{0x0CAA, 0x0CB3, wbALetter},
+	{0x0CB5, 0x0CB9, wbALetter},
+	{0x0CBC, 0x0CBC, wbExtend},
+	{0x0CBD, 0x0CBD, wbALetter},
+	{0x0CBE, 0x0CC4, wbExtend},
+	{0x0CC6, 0x0CC8, wbExtend},
+	{0x0CCA, 0x0CCD, wbExtend},
+	{0x0CD5, 0x0CD6, wbExtend},
+	{0x0CDD, 0x0CDE, wbALetter},
+	{0x0CE0, 0x0CE1, wbALetter},
+	{0x0CE2, 0x0CE3, wbExtend},
+	{0x0CE6, 0x0CEF, wbNumeric},
+	{0x0CF1, 0x0CF2, wbALetter},
+	{0x0CF3, 0x0CF3, wbExtend},
+	{0x0D00, 0x0D03, wbExtend},
+	{0x0D04, 0x0D0C, wbALetter},
+	{0x0D0E, 0x0D10, wbALetter},
+	{0x0D12, 0x0D3A, wbALetter},
+	{0x0D3B, 0x0D3C, wbExtend},
+	{0x0D3D, 0x0D3D, wbALetter},
+	{0x0D3E, 0x0D44, wbExtend},
+	{0x0D46, 0x0D48, wbExtend},
+	{0x0D4A, 0x0D4D, wbExtend},
+	{0x0D4E, 0x0D4E, wbALetter},
+	{0x0D54, 0x0D56, wbALetter},
+	{0x0D57, 0x0D57, wbExtend},
+	{0x0D5F, 0x0D61, wbALetter},
+	{0x0D62, 0x0D63, wbExtend},
+	{0x0D66, 0x0D6F, wbNumeric},
+	{0x0D7A, 0x0D7F, wbALetter},
+	{0x0D81, 0x0D83, wbExtend},
+	{0x0D85, 0x0D96, wbALetter},
+	{0x0D9A, 0x0DB1, wbALetter},
+	{0x0DB3, 0x0DBB, wbALetter},
+	{0x0DBD, 0x0DBD, wbALetter},
+	{0x0DC0, 0x0DC6, wbALetter},
+	{0x0DCA, 0x0DCA, wbExtend},
+	{0x0DCF, 0x0DD4, wbExtend},
+	{0x0DD6, 0x0DD6, wbExtend},
+	{0x0DD8, 0x0DDF, wbExtend},
+	{0x0DE6, 0x0DEF, wbNumeric},
+	{0x0DF2, 0x0DF3, wbExtend},
+	{0x0E31, 0x0E31, wbExtend},
+	{0x0E34, 0x0E3A, wbExtend},
+	{0x0E47, 0x0E4E, wbExtend},
+	{0x0E50, 0x0E59, wbNumeric},
+	{0x0EB1, 0x0EB1, wbExtend},
+	{0x0EB4, 0x0EBC, wbExtend},
+	{0x0EC8, 0x0ECE, wbExtend},
+	{0x0ED0, 0x0ED9, wbNumeric},
+	{0x0F00, 0x0F00, wbALetter},
+	{0x0F18, 0x0F19, wbExtend},
+	{0x0F20, 0x0F29, wbNumeric},
+	{0x0F35, 0x0F35, wbExtend},
+	{0x0F37, 0x0F37, wbExtend},
+	{0x0F39, 0x0F39, wbExtend},
+	{0x0F3E, 0x0F3F, wbExtend},
+	{0x0F40, 0x0F47, wbALetter},
+	{0x0F49, 0x0F6C, wbALetter},
+	{0x0F71, 0x0F84, wbExtend},
+	{0x0F86, 0x0F87, wbExtend},
+	{0x0F88, 0x0F8C, wbALetter},
+	{0x0F8D, 0x0F97, wbExtend},
+	{0x0F99, 0x0FBC, wbExtend},
+	{0x0FC6, 0x0FC6, wbExtend},
+	{0x102B, 0x103E, wbExtend},
+	{0x1040, 0x1049, wbNumeric},
+	{0x1056, 0x1059, wbExtend},
+	{0x105E, 0x1060, wbExtend},
+	{0x1062, 0x1064, wbExtend},
+	{0x1067, 0x106D, wbExtend},
+	{0x1071, 0x1074, wbExtend},
+	{0x1082, 0x108D, wbExtend},
+	{0x108F, 0x108F, wbExtend},
+	{0x1090, 0x1099, wbNumeric},
+	{0x109A, 0x109D, wbExtend},
+	{0x10A0, 0x10C5, wbALetter},
+	{0x10C7, 0x10C7, wbALetter},
+	{0x10CD, 0x10CD, wbALetter},
+	{0x10D0, 0x10FA, wbALetter},
+	{0x10FC, 0x1248, wbALetter},
+	{0x124A, 0x124D, wbALetter},
+	{0x1250, 0x1256, wbALetter},
+	{0x1258, 0x1258, wbALetter},
+	{0x125A, 0x125D, wbALetter},
+	{0x1260, 0x1288, wbALetter},
+	{0x128A, 0x128D, wbALetter},
+	{0x1290, 0x12B0, wbALetter},
+	{0x12B2, 0x12B5, wbALetter},
+	{0x12B8, 0x12BE, wbALetter},
+	{0x12C0, 0x12C0, wbALetter},
+	{0x12C2, 0x12C5, wbALetter},
+	{0x12C8, 0x12D6, wbALetter},
+	{0x12D8, 0x1310, wbALetter},
+	{0x1312, 0x1315, wbALetter},
+	{0x1318, 0x135A, wbALetter},
+	{0x135D, 0x135F, wbExtend},
+	{0x1380, 0x138F, wbALetter},
+	{0x13A0, 0x13F5, wbALetter},
+	{0x13F8, 0x13FD, wbALetter},
+	{0x1401, 0x166C, wbALetter},
+	{0x166F, 0x167F, wbALetter},
+	{0x1680, 0x1680, wbWSegSpace},
+	{0x1681, 0x169A, wbALetter},
+	{0x16A0, 0x16EA, wbALetter},
+	{0x16EE, 0x16F8, wbALetter},
+	{0x1700, 0x1711, wbALetter},
+	{0x1712, 0x1715, wbExtend},
+	{0x171F, 0x1731, wbALetter},
+	{0x1732, 0x1734, wbExtend},
+	{0x1740, 0x1751, wbALetter},
+	{0x1752, 0x1753, wbExtend},
+	{0x1760, 0x176C, wbALetter},
+	{0x176E, 0x1770, wbALetter},
+	{0x1772, 0x1773, wbExtend},
+	{0x17B4, 0x17D3, wbExtend},
+	{0x17DD, 0x17DD, wbExtend},
+	{0x17E0, 0x17E9, wbNumeric},
+	{0x180B, 0x180D, wbExtend},
+	{0x180E, 0x180E, wbFormat},
+	{0x180F, 0x180F, wbExtend},
+	{0x1810, 0x1819, wbNumeric},
+	{0x1820, 0x1878, wbALetter},
+	{0x1880, 0x1884, wbALetter},
+	{0x1885, 0x1886, wbExtend},
+	{0x1887, 0x18A8, wbALetter},
+	{0x18A9, 0x18A9, wbExtend},
+	{0x18AA, 0x18AA, wbALetter},
+	{0x18B0, 0x18F5, wbALetter},
+	{0x1900, 0x191E, wbALetter},
+	{0x1920, 0x192B, wbExtend},
+	{0x1930, 0x193B, wbExtend},
+	{0x1946, 0x194F, wbNumeric},
+	{0x19D0, 0x19D9, wbNumeric},
+	{0x1A00, 0x1A16, wbALetter},
+	{0x1A17, 0x1A1B, wbExtend},
+	{0x1A55, 0x1A5E, wbExtend},
+	{0x1A60, 0x1A7C, wbExtend},
+	{0x1A7F, 0x1A7F, wbExtend},
+	{0x1A80, 0x1A89, wbNumeric},
+	{0x1A90, 0x1A99, wbNumeric},
+	{0x1AB0, 0x1ACE, wbExtend},
+	{0x1B00, 0x1B04, wbExtend},
+	{0x1B05, 0x1B33, wbALetter},
+	{0x1B34, 0x1B44, wbExtend},
+	{0x1B45, 0x1B4C, wbALetter},
+	{0x1B50, 0x1B59, wbNumeric},
+	{0x1B6B, 0x1B73, wbExtend},
+	{0x1B80, 0x1B82, wbExtend},
+	{0x1B83, 0x1BA0, wbALetter},
+	{0x1BA1, 0x1BAD, wbExtend},
+	{0x1BAE, 0x1BAF, wbALetter},
+	{0x1BB0, 0x1BB9, wbNumeric},
+	{0x1BBA, 0x1BE5, wbALetter},
+	{0x1BE6, 0x1BF3, wbExtend},
+	{0x1C00, 0x1C23, wbALetter},
+	{0x1C24, 0x1C37, wbExtend},
+	{0x1C40, 0x1C49, wbNumeric},
+	{0x1C4D, 0x1C4F, wbALetter},
+	{0x1C50, 0x1C59, wbNumeric},
+	{0x1C5A, 0x1C7D, wbALetter},
+	{0x1C80, 0x1C88, wbALetter},
+	{0x1C90, 0x1CBA, wbALetter},
+	{0x1CBD, 0x1CBF, wbALetter},
+	{0x1CD0, 0x1CD2, wbExtend},
+	{0x1CD4, 0x1CE8, wbExtend},
+	{0x1CE9, 0x1CEC, wbALetter},
+	{0x1CED, 0x1CED, wbExtend},
+	{0x1CEE, 0x1CF3, wbALetter},
+	{0x1CF4, 0x1CF4, wbExtend},
+	{0x1CF5, 0x1CF6, wbALetter},
+	{0x1CF7, 0x1CF9, wbExtend},
+	{0x1CFA, 0x1CFA, wbALetter},
+	{0x1D00, 0x1DBF, wbALetter},
+	{0x1DC0, 0x1DFF, wbExtend},
+	{0x1E00, 0x1F15, wbALetter},
+	{0x1F18, 0x1F1D, wbALetter},
+	{0x1F20, 0x1F45, wbALetter},
+	{0x1F48, 0x1F4D, wbALetter},
+	{0x1F50, 0x1F57, wbALetter},
+	{0x1F59, 0x1F59, wbALetter},
+	{0x1F5B, 0x1F5B, wbALetter},
+	{0x1F5D, 0x1F5D, wbALetter},
+	{0x1F5F, 0x1F7D, wbALetter},
+	{0x1F80, 0x1FB4, wbALetter},
+	{0x1FB6, 0x1FBC, wbALetter},
+	{0x1FBE, 0x1FBE, wbALetter},
+	{0x1FC2, 0x1FC4, wbALetter},
+	{0x1FC6, 0x1FCC, wbALetter},
+	{0x1FD0, 0x1FD3, wbALetter},
+	{0x1FD6, 0x1FDB, wbALetter},
+	{0x1FE0, 0x1FEC, wbALetter},
+	{0x1FF2, 0x1FF4, wbALetter},
+	{0x1FF6, 0x1FFC, wbALetter},
+	{0x2000, 0x2006, wbWSegSpace},
+	{0x2008, 0x200A, wbWSegSpace},
+	{0x200C, 0x200C, wbExtend},
+	{0x200D, 0x200D, wbZWJ},
+	{0x200E, 0x200F, wbFormat},
+	{0x2018, 0x2019, wbMidNumLet},
+	{0x2024, 0x2024, wbMidNumLet},
+	{0x2027, 0x2027, wbMidLetter},
+	{0x2028, 0x2029, wbNewline},
+	{0x202A, 0x202E, wbFormat},
+	{0x202F, 0x202F, wbExtendNumLet},
+	{0x203F, 0x2040, wbExtendNumLet},
+	{0x2044, 0x2044, wbMidNum},
+	{0x2054, 0x2054, wbExtendNumLet},
+	{0x205F, 0x205F, wbWSegSpace},
+	{0x2060, 0x2064, wbFormat},
+	{0x2066, 0x206F, wbFormat},
+	{0x2071, 0x2071, wbALetter},
+	{0x207F, 0x207F, wbALetter},
+	{0x2090, 0x209C, wbALetter},
+	{0x20D0, 0x20F0, wbExtend},
+	{0x2102, 0x2102, wbALetter},
+	{0x2107, 0x2107, wbALetter},
+	{0x210A, 0x2113, wbALetter},
+	{0x2115, 0x2115, wbALetter},
+	{0x2119, 0x211D, wbALetter},
+	{0x2124, 0x2124, wbALetter},
+	{0x2126, 0x2126, wbALetter},
+	{0x2128, 0x2128, wbALetter},
+	{0x212A, 0x212D, wbALetter},
+	{0x212F, 0x2139, wbALetter},
+	{0x213C, 0x213F, wbALetter},
+	{0x2145, 0x2149, wbALetter},
+	{0x214E, 0x214E, wbALetter},
+	{0x2160, 0x2188, wbALetter},
+	{0x24B6, 0x24E9, wbALetter},
+	{0x2C00, 0x2CE4, wbALetter},
+	{0x2CEB, 0x2CEE, wbALetter},
+	{0x2CEF, 0x2CF1, wbExtend},
+	{0x2CF2, 0x2CF3, wbALetter},
+	{0x2D00, 0x2D25, wbALetter},
+	{0x2D27, 0x2D27, wbALetter},
+	{0x2D2D, 0x2D2D, wbALetter},
+	{0x2D30, 0x2D67, wbALetter},
+	{0x2D6F, 0x2D6F, wbALetter},
+	{0x2D7F, 0x2D7F, wbExtend},
+	{0x2D80, 0x2D96, wbALetter},
+	{0x2DA0, 0x2DA6, wbALetter},
+	{0x2DA8, 0x2DAE, wbALetter},
+	{0x2DB0, 0x2DB6, wbALetter},
+	{0x2DB8, 0x2DBE, wbALetter},
+	{0x2DC0, 0x2DC6, wbALetter},
+	{0x2DC8, 0x2DCE, wbALetter},
+	{0x2DD0, 0x2DD6, wbALetter},
+	{0x2DD8, 0x2DDE, wbALetter},
+	{0x2DE0, 0x2DFF, wbExtend},
+	{0x2E2F, 0x2E2F, wbALetter},
+	{0x3000, 0x3000, wbWSegSpace},
+	{0x3005, 0x3005, wbALetter},
+	{0x302A, 0x302F, wbExtend},
+	{0x3031, 0x3035, wbKatakana},
+	{0x303B, 0x303C, wbALetter},
+	{0x3099, 0x309A, wbExtend},
+	{0x309B, 0x309C, wbKatakana},
+	{0x30A0, 0x30FA, wbKatakana},
+	{0x30FC, 0x30FF, wbKatakana},
+	{0x3105, 0x312F, wbALetter},
+	{0x3131, 0x318E, wbALetter},
+	{0x31A0, 0x31BF, wbALetter},
+	{0x31F0, 0x31FF, wbKatakana},
+	{0x32D0, 0x32FE, wbKatakana},
+	{0x3300, 0x3357, wbKatakana},
+	{0xA000, 0xA48C, wbALetter},
+	{0xA4D0, 0xA4FD, wbALetter},
+	{0xA500, 0xA60C, wbALetter},
+	{0xA610, 0xA61F, wbALetter},
+	{0xA620, 0xA629, wbNumeric},
+	{0xA62A, 0xA62B, wbALetter},
+	{0xA640, 0xA66E, wbALetter},
+	{0xA66F, 0xA672, wbExtend},
+	{0xA674, 0xA67D, wbExtend},
+	{0xA67F, 0xA69D, wbALetter},
+	{0xA69E, 0xA69F, wbExtend},
+	{0xA6A0, 0xA6EF, wbALetter},
+	{0xA6F0, 0xA6F1, wbExtend},
+	{0xA708, 0xA7CA, wbALetter},
+	{0xA7D0, 0xA7D1, wbALetter},
+	{0xA7D3, 0xA7D3, wbALetter},
+	{0xA7D5, 0xA7D9, wbALetter},
+	{0xA7F2, 0xA801, wbALetter},
+	{0xA802, 0xA802, wbExtend},
+	{0xA803, 0xA805, wbALetter},
+	{0xA806, 0xA806, wbExtend},
+	{0xA807, 0xA80A, wbALetter},
+	{0xA80B, 0xA80B, wbExtend},
+	{0xA80C, 0xA822, wbALetter},
+	{0xA823, 0xA827, wbExtend},
+	{0xA82C, 0xA82C, wbExtend},
+	{0xA840, 0xA873, wbALetter},
+	{0xA880, 0xA881, wbExtend},
+	{0xA882, 0xA8B3, wbALetter},
+	{0xA8B4, 0xA8C5, wbExtend},
+	{0xA8D0, 0xA8D9, wbNumeric},
+	{0xA8E0, 0xA8F1, wbExtend},
+	{0xA8F2, 0xA8F7, wbALetter},
+	{0xA8FB, 0xA8FB, wbALetter},
+	{0xA8FD, 0xA8FE, wbALetter},
+	{0xA8FF, 0xA8FF, wbExtend},
+	{0xA900, 0xA909, wbNumeric},
+	{0xA90A, 0xA925, wbALetter},
+	{0xA926, 0xA92D, wbExtend},
+	{0xA930, 0xA946, wbALetter},
+	{0xA947, 0xA953, wbExtend},
+	{0xA960, 0xA97C, wbALetter},
+	{0xA980, 0xA983, wbExtend},
+	{0xA984, 0xA9B2, wbALetter},
+	{0xA9B3, 0xA9C0, wbExtend},
+	{0xA9CF, 0xA9CF, wbALetter},
+	{0xA9D0, 0xA9D9, wbNumeric},
+	{0xA9E5, 0xA9E5, wbExtend},
+	{0xA9F0, 0xA9F9, wbNumeric},
+	{0xAA00, 0xAA28, wbALetter},
+	{0xAA29, 0xAA36, wbExtend},
+	{0xAA40, 0xAA42, wbALetter},
+	{0xAA43, 0xAA43, wbExtend},
+	{0xAA44, 0xAA4B, wbALetter},
+	{0xAA4C, 0xAA4D, wbExtend},
+	{0xAA50, 0xAA59, wbNumeric},
+	{0xAA7B, 0xAA7D, wbExtend},
+	{0xAAB0, 0xAAB0, wbExtend},
+	{0xAAB2, 0xAAB4, wbExtend},
+	{0xAAB7, 0xAAB8, wbExtend},
+	{0xAABE, 0xAABF, wbExtend},
+	{0xAAC1, 0xAAC1, wbExtend},
+	{0xAAE0, 0xAAEA, wbALetter},
+	{0xAAEB, 0xAAEF, wbExtend},
+	{0xAAF2, 0xAAF4, wbALetter},
+	{0xAAF5, 0xAAF6, wbExtend},
+	{0xAB01, 0xAB06, wbALetter},
+	{0xAB09, 0xAB0E, wbALetter},
+	{0xAB11, 0xAB16, wbALetter},
+	{0xAB20, 0xAB26, wbALetter},
+	{0xAB28, 0xAB2E, wbALetter},
+	{0xAB30, 0xAB69, wbALetter},
+	{0xAB70, 0xABE2, wbALetter},
+	{0xABE3, 0xABEA, wbExtend},
+	{0xABEC, 0xABED, wbExtend},
+	{0xABF0, 0xABF9, wbNumeric},
+	{0xAC00, 0xD7A3, wbALetter},
+	{0xD7B0, 0xD7C6, wbALetter},
+	{0xD7CB, 0xD7FB, wbALetter},
+	{0xFB00, 0xFB06, wbALetter},
+	{0xFB13, 0xFB17, wbALetter},
+	{0xFB1D, 0xFB1D, wbHebrewLetter},
+	{0xFB1E, 0xFB1E, wbExtend},
+	{0xFB1F, 0xFB28, wbHebrewLetter},
+	{0xFB2A, 0xFB36, wbHebrewLetter},
+	{0xFB38, 0xFB3C, wbHebrewLetter},
+	{0xFB3E, 0xFB3E, wbHebrewLetter},
+	{0xFB40, 0xFB41, wbHebrewLetter},
+	{0xFB43, 0xFB44, wbHebrewLetter},
+	{0xFB46, 0xFB4F, wbHebrewLetter},
+	{0xFB50, 0xFBB1, wbALetter},
+	{0xFBD3, 0xFD3D, wbALetter},
+	{0xFD50, 0xFD8F, wbALetter},
+	{0xFD92, 0xFDC7, wbALetter},
+	{0xFDF0, 0xFDFB, wbALetter},
+	{0xFE00, 0xFE0F, wbExtend},
+	{0xFE10, 0xFE10, wbMidNum},
+	{0xFE13, 0xFE13, wbMidLetter},
+	{0xFE14, 0xFE14, wbMidNum},
+	{0xFE20, 0xFE2F, wbExtend},
+	{0xFE33, 0xFE34, wbExtendNumLet},
+	{0xFE4D, 0xFE4F, wbExtendNumLet},
+	{0xFE50, 0xFE50, wbMidNum},
+	{0xFE52, 0xFE52, wbMidNumLet},
+	{0xFE54, 0xFE54, wbMidNum},
+	{0xFE55, 0xFE55, wbMidLetter},
+	{0xFE70, 0xFE74, wbALetter},
+	{0xFE76, 0xFEFC, wbALetter},
+	{0xFEFF, 0xFEFF, wbFormat},
+	{0xFF07, 0xFF07, wbMidNumLet},
+	{0xFF0C, 0xFF0C, wbMidNum},
+	{0xFF0E, 0xFF0E, wbMidNumLet},
+	{0xFF10, 0xFF19, wbNumeric},
+	{0xFF1A, 0xFF1A, wbMidLetter},
+	{0xFF1B, 0xFF1B, wbMidNum},
+	{0xFF21, 0xFF3A, wbALetter},
+	{0xFF3F, 0xFF3F, wbExtendNumLet},
+	{0xFF41, 0xFF5A, wbALetter},
+	{0xFF66, 0xFF9D, wbKatakana},
+	{0xFF9E, 0xFF9F, wbExtend},
+	{0xFFA0, 0xFFBE, wbALetter},
+	{0xFFC2, 0xFFC7, wbALetter},
+	{0xFFCA, 0xFFCF, wbALetter},
+	{0xFFD2, 0xFFD7, wbALetter},
+	{0xFFDA, 0xFFDC, wbALetter},
+	{0xFFF9, 0xFFFB, wbFormat},
+	{0x10000, 0x1000B, wbALetter},
+	{0x1000D, 0x10026, wbALetter},
+	{0x10028, 0x1003A, wbALetter},
+	{0x1003C, 0x1003D, wbALetter},
+	{0x1003F, 0x1004D, wbALetter},
+	{0x10050, 0x1005D, wbALetter},
+	{0x10080, 0x100FA, wbALetter},
+	{0x10140, 0x10174, wbALetter},
+	{0x101FD, 0x101FD, wbExtend},
+	{0x10280, 0x1029C, wbALetter},
+	{0x102A0, 0x102D0, wbALetter},
+	{0x102E0, 0x102E0, wbExtend},
+	{0x10300, 0x1031F, wbALetter},
+	{0x1032D, 0x1034A, wbALetter},
+	{0x10350, 0x10375, wbALetter},
+	{0x10376, 0x1037A, wbExtend},
+	{0x10380, 0x1039D, wbALetter},
+	{0x103A0, 0x103C3, wbALetter},
+	{0x103C8, 0x103CF, wbALetter},
+	{0x103D1, 0x103D5, wbALetter},
+	{0x10400, 0x1049D, wbALetter},
+	{0x104A0, 0x104A9, wbNumeric},
+	{0x104B0, 0x104D3, wbALetter},
+	{0x104D8, 0x104FB, wbALetter},
+	{0x10500, 0x10527, wbALetter},
+	{0x10530, 0x10563, wbALetter},
+	{0x10570, 0x1057A, wbALetter},
+	{0x1057C, 0x1058A, wbALetter},
+	{0x1058C, 0x10592, wbALetter},
+	{0x10594, 0x10595, wbALetter},
+	{0x10597, 0x105A1, wbALetter},
+	{0x105A3, 0x105B1, wbALetter},
+	{0x105B3, 0x105B9, wbALetter},
+	{0x105BB, 0x105BC, wbALetter},
+	{0x10600, 0x10736, wbALetter},
+	{0x10740, 0x10755, wbALetter},
+	{0x10760, 0x10767, wbALetter},
+	{0x10780, 0x10785, wbALetter},
+	{0x10787, 0x107B0, wbALetter},
+	{0x107B2, 0x107BA, wbALetter},
+	{0x10800, 0x10805, wbALetter},
+	{0x10808, 0x10808, wbALetter},
+	{0x1080A, 0x10835, wbALetter},
+	{0x10837, 0x10838, wbALetter},
+	{0x1083C, 0x1083C, wbALetter},
+	{0x1083F, 0x10855, wbALetter},
+	{0x10860, 0x10876, wbALetter},
+	{0x10880, 0x1089E, wbALetter},
+	{0x108E0, 0x108F2, wbALetter},
+	{0x108F4, 0x108F5, wbALetter},
+	{0x10900, 0x10915, wbALetter},
+	{0x10920, 0x10939, wbALetter},
+	{0x10980, 0x109B7, wbALetter},
+	{0x109BE, 0x109BF, wbALetter},
+	{0x10A00, 0x10A00, wbALetter},
+	{0x10A01, 0x10A03, wbExtend},
+	{0x10A05, 0x10A06, wbExtend},
+	{0x10A0C, 0x10A0F, wbExtend},
+	{0x10A10, 0x10A13, wbALetter},
+	{0x10A15, 0x10A17, wbALetter},
+	{0x10A19, 0x10A35, wbALetter},
+	{0x10A38, 0x10A3A, wbExtend},
+	{0x10A3F, 0x10A3F, wbExtend},
+	{0x10A60, 0x10A7C, wbALetter},
+	{0x10A80, 0x10A9C, wbALetter},
+	{0x10AC0, 0x10AC7, wbALetter},
+	{0x10AC9, 0x10AE4, wbALetter},
+	{0x10AE5, 0x10AE6, wbExtend},
+	{0x10B00, 0x10B35, wbALetter},
+	{0x10B40, 0x10B55, wbALetter},
+	{0x10B60, 0x10B72, wbALetter},
+	{0x10B80, 0x10B91, wbALetter},
+	{0x10C00, 0x10C48, wbALetter},
+	{0x10C80, 0x10CB2, wbALetter},
+	{0x10CC0, 0x10CF2, wbALetter},
+	{0x10D00, 0x10D23, wbALetter},
+	{0x10D24, 0x10D27, wbExtend},
+	{0x10D30, 0x10D39, wbNumeric},
+	{0x10E80, 0x10EA9, wbALetter},
+	{0x10EAB, 0x10EAC, wbExtend},
+	{0x10EB0, 0x10EB1, wbALetter},
+	{0x10EFD, 0x10EFF, wbExtend},
+	{0x10F00, 0x10F1C, wbALetter},
+	{0x10F27, 0x10F27, wbALetter},
+	{0x10F30, 0x10F45, wbALetter},
+	{0x10F46, 0x10F50, wbExtend},
+	{0x10F70, 0x10F81, wbALetter},
+	{0x10F82, 0x10F85, wbExtend},
+	{0x10FB0, 0x10FC4, wbALetter},
+	{0x10FE0, 0x10FF6, wbALetter},
+	{0x11000, 0x11002, wbExtend},
+	{0x11003, 0x11037, wbALetter},
+	{0x11038, 0x11046, wbExtend},
+	{0x11066, 0x1106F, wbNumeric},
+	{0x11070, 0x11070, wbExtend},
+	{0x11071, 0x11072, wbALetter},
+	{0x11073, 0x11074, wbExtend},
+	{0x11075, 0x11075, wbALetter},
+	{0x1107F, 0x11082, wbExtend},
+	{0x11083, 0x110AF, wbALetter},
+	{0x110B0, 0x110BA, wbExtend},
+	{0x110BD, 0x110BD, wbFormat},
+	{0x110C2, 0x110C2, wbExtend},
+	{0x110CD, 0x110CD, wbFormat},
+	{0x110D0, 0x110E8, wbALetter},
+	{0x110F0, 0x110F9, wbNumeric},
+	{0x11100, 0x11102, wbExtend},
+	{0x11103, 0x11126, wbALetter},
+	{0x11127, 0x11134, wbExtend},
+	{0x11136, 0x1113F, wbNumeric},
+	{0x11144, 0x11144, wbALetter},
+	{0x11145, 0x11146, wbExtend},
+	{0x11147, 0x11147, wbALetter},
+	{0x11150, 0x11172, wbALetter},
+	{0x11173, 0x11173, wbExtend},
+	{0x11176, 0x11176, wbALetter},
+	{0x11180, 0x11182, wbExtend},
+	{0x11183, 0x111B2, wbALetter},
+	{0x111B3, 0x111C0, wbExtend},
+	{0x111C1, 0x111C4, wbALetter},
+	{0x111C9, 0x111CC, wbExtend},
+	{0x111CE, 0x111CF, wbExtend},
+	{0x111D0, 0x111D9, wbNumeric},
+	{0x111DA, 0x111DA, wbALetter},
+	{0x111DC, 0x111DC, wbALetter},
+	{0x11200, 0x11211, wbALetter},
+	{0x11213, 0x1122B, wbALetter},
+	{0x1122C, 0x11237, wbExtend},
+	{0x1123E, 0x1123E, wbExtend},
+	{0x1123F, 0x11240, wbALetter},
+	{0x11241, 0x11241, wbExtend},
+	{0x11280, 0x11286, wbALetter},
+	{0x11288, 0x11288, wbALetter},
+	{0x1128A, 0x1128D, wbALetter},
+	{0x1128F, 0x1129D, wbALetter},
+	{0x1129F, 0x112A8, wbALetter},
+	{0x112B0, 0x112DE, wbALetter},
+	{0x112DF, 0x112EA, wbExtend},
+	{0x112F0, 0x112F9, wbNumeric},
+	{0x11300, 0x11303, wbExtend},
+	{0x11305, 0x1130C, wbALetter},
+	{0x1130F, 0x11310, wbALetter},
+	{0x11313, 0x11328, wbALetter},
+	{0x1132A, 0x11330, wbALetter},
+	{0x11332, 0x11333, wbALetter},
+	{0x11335, 0x11339, wbALetter},
+	{0x1133B, 0x1133C, wbExtend},
+	{0x1133D, 0x1133D, wbALetter},
+	{0x1133E, 0x11344, wbExtend},
+	{0x11347, 0x11348, wbExtend},
+	{0x1134B, 0x1134D, wbExtend},
+	{0x11350, 0x11350, wbALetter},
+	{0x11357, 0x11357, wbExtend},
+	{0x1135D, 0x11361, wbALetter},
+	{0x11362, 0x11363, wbExtend},
+	{0x11366, 0x1136C, wbExtend},
+	{0x11370, 0x11374, wbExtend},
+	{0x11400, 0x11434, wbALetter},
+	{0x11435, 0x11446, wbExtend},
+	{0x11447, 0x1144A, wbALetter},
+	{0x11450, 0x11459, wbNumeric},
+	{0x1145E, 0x1145E, wbExtend},
+	{0x1145F, 0x11461, wbALetter},
+	{0x11480, 0x114AF, wbALetter},
+	{0x114B0, 0x114C3, wbExtend},
+	{0x114C4, 0x114C5, wbALetter},
+	{0x114C7, 0x114C7, wbALetter},
+	{0x114D0, 0x114D9, wbNumeric},
+	{0x11580, 0x115AE, wbALetter},
+	{0x115AF, 0x115B5, wbExtend},
+	{0x115B8, 0x115C0, wbExtend},
+	{0x115D8, 0x115DB, wbALetter},
+	{0x115DC, 0x115DD, wbExtend},
+	{0x11600, 0x1162F, wbALetter},
+	{0x11630, 0x11640, wbExtend},
+	{0x11644, 0x11644, wbALetter},
+	{0x11650, 0x11659, wbNumeric},
+	{0x11680, 0x116AA, wbALetter},
+	{0x116AB, 0x116B7, wbExtend},
+	{0x116B8, 0x116B8, wbALetter},
+	{0x116C0, 0x116C9, wbNumeric},
+	{0x1171D, 0x1172B, wbExtend},
+	{0x11730, 0x11739, wbNumeric},
+	{0x11800, 0x1182B, wbALetter},
+	{0x1182C, 0x1183A, wbExtend},
+	{0x118A0, 0x118DF, wbALetter},
+	{0x118E0, 0x118E9, wbNumeric},
+	{0x118FF, 0x11906, wbALetter},
+	{0x11909, 0x11909, wbALetter},
+	{0x1190C, 0x11913, wbALetter},
+	{0x11915, 0x11916, wbALetter},
+	{0x11918, 0x1192F, wbALetter},
+	{0x11930, 0x11935, wbExtend},
+	{0x11937, 0x11938, wbExtend},
+	{0x1193B, 0x1193E, wbExtend},
+	{0x1193F, 0x1193F, wbALetter},
+	{0x11940, 0x11940, wbExtend},
+	{0x11941, 0x11941, wbALetter},
+	{0x11942, 0x11943, wbExtend},
+	{0x11950, 0x11959, wbNumeric},
+	{0x119A0, 0x119A7, wbALetter},
+	{0x119AA, 0x119D0, wbALetter},
+	{0x119D1, 0x119D7, wbExtend},
+	{0x119DA, 0x119E0, wbExtend},
+	{0x119E1, 0x119E1, wbALetter},
+	{0x119E3, 0x119E3, wbALetter},
+	{0x119E4, 0x119E4, wbExtend},
+	{0x11A00, 0x11A00, wbALetter},
+	{0x11A01, 0x11A0A, wbExtend},
+	{0x11A0B, 0x11A32, wbALetter},
+	{0x11A33, 0x11A39, wbExtend},
+	{0x11A3A, 0x11A3A, wbALetter},
+	{0x11A3B, 0x11A3E, wbExtend},
+	{0x11A47, 0x11A47, wbExtend},
+	{0x11A50, 0x11A50, wbALetter},
+	{0x11A51, 0x11A5B, wbExtend},
+	{0x11A5C, 0x11A89, wbALetter},
+	{0x11A8A, 0x11A99, wbExtend},
+	{0x11A9D, 0x11A9D, wbALetter},
+	{0x11AB0, 0x11AF8, wbALetter},
+	{0x11C00, 0x11C08, wbALetter},
+	{0x11C0A, 0x11C2E, wbALetter},
+	{0x11C2F, 0x11C36, wbExtend},
+	{0x11C38, 0x11C3F, wbExtend},
+	{0x11C40, 0x11C40, wbALetter},
+	{0x11C50, 0x11C59, wbNumeric},
+	{0x11C72, 0x11C8F, wbALetter},
+	{0x11C92, 0x11CA7, wbExtend},
+	{0x11CA9, 0x11CB6, wbExtend},
+	{0x11D00, 0x11D06, wbALetter},
+	{0x11D08, 0x11D09, wbALetter},
+	{0x11D0B, 0x11D30, wbALetter},
+	{0x11D31, 0x11D36, wbExtend},
+	{0x11D3A, 0x11D3A, wbExtend},
+	{0x11D3C, 0x11D3D, wbExtend},
+	{0x11D3F, 0x11D45, wbExtend},
+	{0x11D46, 0x11D46, wbALetter},
+	{0x11D47, 0x11D47, wbExtend},
+	{0x11D50, 0x11D59, wbNumeric},
+	{0x11D60, 0x11D65, wbALetter},
+	{0x11D67, 0x11D68, wbALetter},
+	{0x11D6A, 0x11D89, wbALetter},
+	{0x11D8A, 0x11D8E, wbExtend},
+	{0x11D90, 0x11D91, wbExtend},
+	{0x11D93, 0x11D97, wbExtend},
+	{0x11D98, 0x11D98, wbALetter},
+	{0x11DA0, 0x11DA9, wbNumeric},
+	{0x11EE0, 0x11EF2, wbALetter},
+	{0x11EF3, 0x11EF6, wbExtend},
+	{0x11F00, 0x11F01, wbExtend},
+	{0x11F02, 0x11F02, wbALetter},
+	{0x11F03, 0x11F03, wbExtend},
+	{0x11F04, 0x11F10, wbALetter},
+	{0x11F12, 0x11F33, wbALetter},
+	{0x11F34, 0x11F3A, wbExtend},
+	{0x11F3E, 0x11F42, wbExtend},
+	{0x11F50, 0x11F59, wbNumeric},
+	{0x11FB0, 0x11FB0, wbALetter},
+	{0x12000, 0x12399, wbALetter},
+	{0x12400, 0x1246E, wbALetter},
+	{0x12480, 0x12543, wbALetter},
+	{0x12F90, 0x12FF0, wbALetter},
+	{0x13000, 0x1342F, wbALetter},
+	{0x13430, 0x1343F, wbFormat},
+	{0x13440, 0x13440, wbExtend},
+	{0x13441, 0x13446, wbALetter},
+	{0x13447, 0x13455, wbExtend},
+	{0x14400, 0x14646, wbALetter},
+	{0x16800, 0x16A38, wbALetter},
+	{0x16A40, 0x16A5E, wbALetter},
+	{0x16A60, 0x16A69, wbNumeric},
+	{0x16A70, 0x16ABE, wbALetter},
+	{0x16AC0, 0x16AC9, wbNumeric},
+	{0x16AD0, 0x16AED, wbALetter},
+	{0x16AF0, 0x16AF4, wbExtend},
+	{0x16B00, 0x16B2F, wbALetter},
+	{0x16B30, 0x16B36, wbExtend},
+	{0x16B40, 0x16B43, wbALetter},
+	{0x16B50, 0x16B59, wbNumeric},
+	{0x16B63, 0x16B77, wbALetter},
+	{0x16B7D, 0x16B8F, wbALetter},
+	{0x16E40, 0x16E7F, wbALetter},
+	{0x16F00, 0x16F4A, wbALetter},
+	{0x16F4F, 0x16F4F, wbExtend},
+	{0x16F50, 0x16F50, wbALetter},
+	{0x16F51, 0x16F87, wbExtend},
+	{0x16F8F, 0x16F92, wbExtend},
+	{0x16F93, 0x16F9F, wbALetter},
+	{0x16FE0, 0x16FE1, wbALetter},
+	{0x16FE3, 0x16FE3, wbALetter},
+	{0x16FE4, 0x16FE4, wbExtend},
+	{0x16FF0, 0x16FF1, wbExtend},
+	{0x1AFF0, 0x1AFF3, wbKatakana},
+	{0x1AFF5, 0x1AFFB, wbKatakana},
+	{0x1AFFD, 0x1AFFE, wbKatakana},
+	{0x1B000, 0x1B000, wbKatakana},
+	{0x1B120, 0x1B122, wbKatakana},
+	{0x1B155, 0x1B155, wbKatakana},
+	{0x1B164, 0x1B167, wbKatakana},
+	{0x1BC00, 0x1BC6A, wbALetter},
+	{0x1BC70, 0x1BC7C, wbALetter},
+	{0x1BC80, 0x1BC88, wbALetter},
+	{0x1BC90, 0x1BC99, wbALetter},
+	{0x1BC9D, 0x1BC9E, wbExtend},
+	{0x1BCA0, 0x1BCA3, wbFormat},
+	{0x1CF00, 0x1CF2D, wbExtend},
+	{0x1CF30, 0x1CF46, wbExtend},
+	{0x1D165, 0x1D169, wbExtend},
+	{0x1D16D, 0x1D172, wbExtend},
+	{0x1D173, 0x1D17A, wbFormat},
+	{0x1D17B, 0x1D182, wbExtend},
+	{0x1D185, 0x1D18B, wbExtend},
+	{0x1D1AA, 0x1D1AD, wbExtend},
+	{0x1D242, 0x1D244, wbExtend},
+	{0x1D400, 0x1D454, wbALetter},
+	{0x1D456, 0x1D49C, wbALetter},
+	{0x1D49E, 0x1D49F, wbALetter},
+	{0x1D4A2, 0x1D4A2, wbALetter},
+	{0x1D4A5, 0x1D4A6, wbALetter},
+	{0x1D4A9, 0x1D4AC, wbALetter},
+	{0x1D4AE, 0x1D4B9, wbALetter},
+	{0x1D4BB, 0x1D4BB, wbALetter},
+	{0x1D4BD, 0x1D4C3, wbALetter},
+	{0x1D4C5, 0x1D505, wbALetter},
+	{0x1D507, 0x1D50A, wbALetter},
+	{0x1D50D, 0x1D514, wbALetter},
+	{0x1D516, 0x1D51C, wbALetter},
+	{0x1D51E, 0x1D539, wbALetter},
+	{0x1D53B, 0x1D53E, wbALetter},
+	{0x1D540, 0x1D544, wbALetter},
+	{0x1D546, 0x1D546, wbALetter},
+	{0x1D54A, 0x1D550, wbALetter},
+	{0x1D552, 0x1D6A5, wbALetter},
+	{0x1D6A8, 0x1D6C0, wbALetter},
+	{0x1D6C2, 0x1D6DA, wbALetter},
+	{0x1D6DC, 0x1D6FA, wbALetter},
+	{0x1D6FC, 0x1D714, wbALetter},
+	{0x1D716, 0x1D734, wbALetter},
+	{0x1D736, 0x1D74E, wbALetter},
+	{0x1D750, 0x1D76E, wbALetter},
+	{0x1D770, 0x1D788, wbALetter},
+	{0x1D78A, 0x1D7A8, wbALetter},
+	{0x1D7AA, 0x1D7C2, wbALetter},
+	{0x1D7C4, 0x1D7CB, wbALetter},
+	{0x1D7CE, 0x1D7FF, wbNumeric},
+	{0x1DA00, 0x1DA36, wbExtend},
+	{0x1DA3B, 0x1DA6C, wbExtend},
+	{0x1DA75, 0x1DA75, wbExtend},
+	{0x1DA84, 0x1DA84, wbExtend},
+	{0x1DA9B, 0x1DA9F, wbExtend},
+	{0x1DAA1, 0x1DAAF, wbExtend},
+	{0x1DF00, 0x1DF1E, wbALetter},
+	{0x1DF25, 0x1DF2A, wbALetter},
+	{0x1E000, 0x1E006, wbExtend},
+	{0x1E008, 0x1E018, wbExtend},
+	{0x1E01B, 0x1E021, wbExtend},
+	{0x1E023, 0x1E024, wbExtend},
+	{0x1E026, 0x1E02A, wbExtend},
+	{0x1E030, 0x1E06D, wbALetter},
+	{0x1E08F, 0x1E08F, wbExtend},
+	{0x1E100, 0x1E12C, wbALetter},
+	{0x1E130, 0x1E136, wbExtend},
+	{0x1E137, 0x1E13D, wbALetter},
+	{0x1E140, 0x1E149, wbNumeric},
+	{0x1E14E, 0x1E14E, wbALetter},
+	{0x1E290, 0x1E2AD, wbALetter},
+	{0x1E2AE, 0x1E2AE, wbExtend},
+	{0x1E2C0, 0x1E2EB, wbALetter},
+	{0x1E2EC, 0x1E2EF, wbExtend},
+	{0x1E2F0, 0x1E2F9, wbNumeric},
+	{0x1E4D0, 0x1E4EB, wbALetter},
+	{0x1E4EC, 0x1E4EF, wbExtend},
+	{0x1E4F0, 0x1E4F9, wbNumeric},
+	{0x1E7E0, 0x1E7E6, wbALetter},
+	{0x1E7E8, 0x1E7EB, wbALetter},
+	{0x1E7ED, 0x1E7EE, wbALetter},
+	{0x1E7F0, 0x1E7FE, wbALetter},
+	{0x1E800, 0x1E8C4, wbALetter},
+	{0x1E8D0, 0x1E8D6, wbExtend},
+	{0x1E900, 0x1E943, wbALetter},
+	{0x1E944, 0x1E94A, wbExtend},
+	{0x1E94B, 0x1E94B, wbALetter},
+	{0x1E950, 0x1E959, wbNumeric},
+	{0x1EE00, 0x1EE03, wbALetter},
+	{0x1EE05, 0x1EE1F, wbALetter},
+	{0x1EE21, 0x1EE22, wbALetter},
+	{0x1EE24, 0x1EE24, wbALetter},
+	{0x1EE27, 0x1EE27, wbALetter},
+	{0x1EE29, 0x1EE32, wbALetter},
+	{0x1EE34, 0x1EE37, wbALetter},
+	{0x1EE39, 0x1EE39, wbALetter},
+	{0x1EE3B, 0x1EE3B, wbALetter},
+	{0x1EE42, 0x1EE42, wbALetter},
+	{0x1EE47, 0x1EE47, wbALetter},
+	{0x1EE49, 0x1EE49, wbALetter},
+	{0x1EE4B, 0x1EE4B, wbALetter},
+	{0x1EE4D, 0x1EE4F, wbALetter},
+	{0x1EE51, 0x1EE52, wbALetter},
+	{0x1EE54, 0x1EE54, wbALetter},
+	{0x1EE57, 0x1EE57, wbALetter},
+	{0x1EE59, 0x1EE59, wbALetter},
+	{0x1EE5B, 0x1EE5B, wbALetter},
+	{0x1EE5D, 0x1EE5D, wbALetter},
+	{0x1EE5F, 0x1EE5F, wbALetter},
+	{0x1EE61, 0x1EE62, wbALetter},
+	{0x1EE64, 0x1EE64, wbALetter},
+	{0x1EE67, 0x1EE6A, wbALetter},
+	{0x1EE6C, 0x1EE72, wbALetter},
+	{0x1EE74, 0x1EE77, wbALetter},
+	{0x1EE79, 0x1EE7C, wbALetter},
+	{0x1EE7E, 0x1EE7E, wbALetter},
+	{0x1EE80, 0x1EE89, wbALetter},
+	{0x1EE8B, 0x1EE9B, wbALetter},
+	{0x1EEA1, 0x1EEA3, wbALetter},
+	{0x1EEA5, 0x1EEA9, wbALetter},
+	{0x1EEAB, 0x1EEBB, wbALetter},
+	{0x1F130, 0x1F149, wbALetter},
+	{0x1F150, 0x1F169, wbALetter},
+	{0x1F170, 0x1F189, wbALetter},
+	{0x1F1E6, 0x1F1FF, wbRegionalIndicator},
+	{0x1F3FB, 0x1F3FF, wbExtend},
+	{0x1FBF0, 0x1FBF9, wbNumeric},
+	{0xE0001, 0xE0001, wbFormat},
+	{0xE0020, 0xE007F, wbExtend},
+	{0xE0100, 0xE01EF, wbExtend},
+}
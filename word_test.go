@@ -0,0 +1,76 @@
+package utfconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func words(it *WordIterator) []Segment {
+	var a []Segment
+	for it.Next() {
+		a = append(a, it.Word())
+	}
+	return a
+}
+
+func TestWordBreakTest(t *testing.T) {
+	testBreakFile(t, "internal/ucd/WordBreakTest.txt",
+		func(s string) []Segment { return words(NewWordIterator(s)) },
+		func(u []uint16) []Segment { return words(NewWordIteratorUTF16(u)) })
+}
+
+func TestWordIterator(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp []string
+	}{
+		{"", nil},
+		{"Hello, world!", []string{"Hello", ",", " ", "world", "!"}},
+		{"can't 3.14 U.S.A. foo_bar", []string{"can't", " ", "3.14", " ", "U.S.A", ".", " ", "foo_bar"}},
+		{"日本語\U0001F600 x", []string{"日", "本", "語", "\U0001F600", " ", "x"}},
+		{"a\r\n\r\nb", []string{"a", "\r\n", "\r\n", "b"}},
+	}
+	for _, x := range tests {
+		var got []string
+		for _, g := range words(NewWordIterator(x.s)) {
+			got = append(got, x.s[g.Start:g.End])
+		}
+		if !reflect.DeepEqual(got, x.exp) {
+			t.Errorf("NewWordIterator(%q) = %q; want: %q", x.s, got, x.exp)
+		}
+		u := StringToUTF16(x.s)
+		var got16 []string
+		for _, g := range words(NewWordIteratorUTF16(u)) {
+			got16 = append(got16, UTF16ToString(u[g.StartUTF16:g.EndUTF16]))
+		}
+		if !reflect.DeepEqual(got16, x.exp) {
+			t.Errorf("NewWordIteratorUTF16(%q) = %q; want: %q", x.s, got16, x.exp)
+		}
+	}
+}
+
+func TestWordIteratorInvalid(t *testing.T) {
+	u := []uint16{'a', 0xd800, 'b'}
+	exp := []Segment{{0, 1, 0, 1}, {1, 4, 1, 2}, {4, 5, 2, 3}}
+	if got := words(NewWordIteratorUTF16(u)); !reflect.DeepEqual(got, exp) {
+		t.Errorf("NewWordIteratorUTF16(%#x) = %v; want: %v", u, got, exp)
+	}
+	exp = []Segment{{0, 1, 0, 1}, {1, 2, 1, 2}, {2, 3, 2, 3}}
+	if got := words(NewWordIterator("a\xffb")); !reflect.DeepEqual(got, exp) {
+		t.Errorf("NewWordIterator(%q) = %v; want: %v", "a\xffb", got, exp)
+	}
+}
+
+func BenchmarkWordIterator_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for it := NewWordIterator(SixtyFourUnicodeChars); it.Next(); {
+		}
+	}
+}
+
+func BenchmarkWordIteratorUTF16_SixtyFourUnicode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for it := NewWordIteratorUTF16(SixtyFourUnicodeCharsUTF16); it.Next(); {
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package wstrings
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/charlievieth/utfconv"
+)
+
+//go:generate go run gen_specialcase.go
+
+// A specialCase is a full case mapping from SpecialCasing.txt, which may map
+// one code point to several.
+type specialCase struct {
+	r                   rune
+	lower, title, upper []uint16
+}
+
+// lookupSpecial returns the special casing of r or nil if r has none.
+func lookupSpecial(r rune) *specialCase {
+	if r < specialCases[0].r || r > specialCases[len(specialCases)-1].r {
+		return nil
+	}
+	i := sort.Search(len(specialCases), func(i int) bool {
+		return specialCases[i].r >= r
+	})
+	if i < len(specialCases) && specialCases[i].r == r {
+		return &specialCases[i]
+	}
+	return nil
+}
+
+// nextRune decodes the first code point of s like utfconv.DecodeRuneUTF16,
+// except that an unpaired surrogate is returned as itself so that it is
+// mapped and compared by value instead of as U+FFFD.
+func nextRune(s []uint16) (rune, int) {
+	r, size := utfconv.DecodeRuneUTF16(s)
+	if r == utf8.RuneError && s[0] != utf8.RuneError {
+		return rune(s[0]), 1
+	}
+	return r, size
+}
+
+// mapCase returns a copy of s with all code points mapped to the case _case
+// using the special casings of SpecialCasing.txt that do not depend on the
+// language or context.
+func mapCase(s []uint16, _case int) []uint16 {
+	a := make([]uint16, 0, len(s))
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			switch {
+			case _case == unicode.LowerCase && 'A' <= c && c <= 'Z':
+				c += 'a' - 'A'
+			case _case != unicode.LowerCase && 'a' <= c && c <= 'z':
+				c -= 'a' - 'A'
+			}
+			a = append(a, c)
+			i++
+			continue
+		}
+		r, size := nextRune(s[i:])
+		i += size
+		if sc := lookupSpecial(r); sc != nil {
+			switch _case {
+			case unicode.UpperCase:
+				a = append(a, sc.upper...)
+			case unicode.LowerCase:
+				a = append(a, sc.lower...)
+			default:
+				a = append(a, sc.title...)
+			}
+			continue
+		}
+		if utf16.IsSurrogate(r) {
+			// an unpaired surrogate has no case, keep it as is
+			a = append(a, uint16(r))
+			continue
+		}
+		a = utfconv.AppendRuneUTF16(a, unicode.To(_case, r))
+	}
+	return a
+}
+
+// ToUpper returns a copy of s with all code points mapped to their upper
+// case. Code points with a special casing, such as 'ß' which maps to "SS",
+// may change the length of the result. Unpaired surrogates are unchanged.
+func ToUpper(s []uint16) []uint16 { return mapCase(s, unicode.UpperCase) }
+
+// ToLower returns a copy of s with all code points mapped to their lower
+// case. Code points with a special casing, such as 'İ' which maps to "i̇",
+// may change the length of the result. Unpaired surrogates are unchanged.
+// Mappings that depend on the language or context, such as the final form
+// of the Greek sigma, are not applied.
+func ToLower(s []uint16) []uint16 { return mapCase(s, unicode.LowerCase) }
+
+// ToTitle returns a copy of s with all code points mapped to their title
+// case. Code points with a special casing, such as 'ß' which maps to "Ss",
+// may change the length of the result. Unpaired surrogates are unchanged.
+func ToTitle(s []uint16) []uint16 { return mapCase(s, unicode.TitleCase) }
+
+// equalFoldRune reports whether sr and tr are equal under simple Unicode
+// case folding.
+func equalFoldRune(sr, tr rune) bool {
+	if sr == tr {
+		return true
+	}
+	if tr < sr {
+		sr, tr = tr, sr
+	}
+	if tr < utf8.RuneSelf {
+		return 'A' <= sr && sr <= 'Z' && tr == sr+'a'-'A'
+	}
+	r := unicode.SimpleFold(sr)
+	for r != sr && r < tr {
+		r = unicode.SimpleFold(r)
+	}
+	return r == tr
+}
+
+// EqualFold reports whether s and t are equal under simple Unicode case
+// folding, as strings.EqualFold does. Unpaired surrogates are only equal to
+// themselves.
+func EqualFold(s, t []uint16) bool {
+	for len(s) > 0 && len(t) > 0 {
+		sr, n := nextRune(s)
+		tr, m := nextRune(t)
+		if !equalFoldRune(sr, tr) {
+			return false
+		}
+		s = s[n:]
+		t = t[m:]
+	}
+	return len(s) == len(t)
+}
+
+// EqualFoldString reports whether u and s are equal under simple Unicode case
+// folding. It is the equivalent of strings.EqualFold(utfconv.UTF16ToString(u), s)
+// without converting u. Unpaired surrogates and invalid UTF-8 are treated as
+// U+FFFD.
+func EqualFoldString(u []uint16, s string) bool {
+	for len(u) > 0 && len(s) > 0 {
+		ur, n := utfconv.DecodeRuneUTF16(u)
+		sr, m := rune(s[0]), 1
+		if sr >= utf8.RuneSelf {
+			sr, m = utf8.DecodeRuneInString(s)
+		}
+		if !equalFoldRune(ur, sr) {
+			return false
+		}
+		u = u[n:]
+		s = s[m:]
+	}
+	return len(u) == 0 && len(s) == 0
+}
+
+// An UpcaseTable maps each UTF-16 code unit to its upper case. Windows
+// compares file system and registry names case-insensitively by upcasing
+// each code unit with such a table (NTFS stores a copy of it in the $UpCase
+// file of each volume), so supplementary characters and special casings,
+// such as 'ß' to "SS", are never mapped and lengths never change.
+type UpcaseTable [1 << 16]uint16
+
+// NewUpcaseTable returns an UpcaseTable built from the simple upper case
+// mappings of package unicode. The tables of different Windows versions
+// differ in a few code points, use ParseUpcaseTable to compare names exactly
+// as a given volume does.
+func NewUpcaseTable() *UpcaseTable {
+	t := new(UpcaseTable)
+	for c := range t {
+		t[c] = uint16(c)
+		if utf16.IsSurrogate(rune(c)) {
+			continue
+		}
+		if u := unicode.ToUpper(rune(c)); utfconv.RuneLenUTF16(u) == 1 {
+			t[c] = uint16(u)
+		}
+	}
+	return t
+}
+
+// ParseUpcaseTable parses an UpcaseTable stored as 65536 little-endian code
+// units, the format of the NTFS $UpCase file.
+func ParseUpcaseTable(b []byte) (*UpcaseTable, error) {
+	t := new(UpcaseTable)
+	if len(b) != 2*len(t) {
+		return nil, fmt.Errorf("wstrings: invalid upcase table length: %d", len(b))
+	}
+	for i := range t {
+		t[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+	}
+	return t, nil
+}
+
+// ToUpper returns a copy of s with each code unit mapped by t.
+func (t *UpcaseTable) ToUpper(s []uint16) []uint16 {
+	a := make([]uint16, len(s))
+	for i, c := range s {
+		a[i] = t[c]
+	}
+	return a
+}
+
+// EqualFold reports whether s and t are equal after mapping each code unit
+// by the table.
+func (t *UpcaseTable) EqualFold(s, u []uint16) bool {
+	if len(s) != len(u) {
+		return false
+	}
+	for i := range s {
+		if t[s[i]] != t[u[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualFoldString reports whether u and the UTF-16 encoding of s are equal
+// after mapping each code unit by the table. Invalid UTF-8 is treated as
+// U+FFFD.
+func (t *UpcaseTable) EqualFoldString(u []uint16, s string) bool {
+	j := 0
+	for i := 0; i < len(s); {
+		r, size := rune(s[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(s[i:])
+		}
+		i += size
+		if utfconv.RuneLenUTF16(r) == 2 {
+			r1, r2 := utf16.EncodeRune(r)
+			if len(u)-j < 2 || t[u[j]] != t[r1] || t[u[j+1]] != t[r2] {
+				return false
+			}
+			j += 2
+			continue
+		}
+		if j == len(u) || t[u[j]] != t[r] {
+			return false
+		}
+		j++
+	}
+	return j == len(u)
+}
+
+// Compare returns an integer comparing s and u by their code units after
+// mapping each by the table, the order Windows uses for case-insensitive
+// ordinal comparisons and for the entries of NTFS directory indexes.
+func (t *UpcaseTable) Compare(s, u []uint16) int {
+	for i := 0; i < len(s) && i < len(u); i++ {
+		if x, y := t[s[i]], t[u[i]]; x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(s) < len(u):
+		return -1
+	case len(s) > len(u):
+		return 1
+	}
+	return 0
+}
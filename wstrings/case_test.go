@@ -0,0 +1,252 @@
+package wstrings
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf16"
+)
+
+var caseTests = []struct {
+	s, upper, lower, title string
+}{
+	{"", "", "", ""},
+	{"abc XYZ 123", "ABC XYZ 123", "abc xyz 123", "ABC XYZ 123"},
+	{"straße", "STRASSE", "straße", "STRASsE"},
+	{"ﬃ", "FFI", "ﬃ", "Ffi"},
+	{"İstanbul", "İSTANBUL", "i\u0307stanbul", "İSTANBUL"},
+	{"ŉ", "ʼN", "ŉ", "ʼN"},
+	{"ǅ ǆ Ǆ", "Ǆ Ǆ Ǆ", "ǆ ǆ ǆ", "ǅ ǅ ǅ"},
+	{"ᾳ", "ΑΙ", "ᾳ", "ᾼ"},
+	{"ΣΟΦΟΣ", "ΣΟΦΟΣ", "σοφοσ", "ΣΟΦΟΣ"},
+	{"\U00010428\U00010400", "\U00010400\U00010400", "\U00010428\U00010428", "\U00010400\U00010400"},
+	{"日本語\U0001F600", "日本語\U0001F600", "日本語\U0001F600", "日本語\U0001F600"},
+}
+
+func TestCaseMapping(t *testing.T) {
+	for _, x := range caseTests {
+		if u := dec(ToUpper(enc(x.s))); u != x.upper {
+			t.Errorf("ToUpper(%q) = %q; want: %q", x.s, u, x.upper)
+		}
+		if u := dec(ToLower(enc(x.s))); u != x.lower {
+			t.Errorf("ToLower(%q) = %q; want: %q", x.s, u, x.lower)
+		}
+		if u := dec(ToTitle(enc(x.s))); u != x.title {
+			t.Errorf("ToTitle(%q) = %q; want: %q", x.s, u, x.title)
+		}
+	}
+}
+
+func TestCaseMappingSimple(t *testing.T) {
+	// Code points without a special casing map as package unicode does.
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if utf16.IsSurrogate(r) || lookupSpecial(r) != nil {
+			continue
+		}
+		s := enc(string(r))
+		if u := ToUpper(s); !reflect.DeepEqual(u, enc(string(unicode.ToUpper(r)))) {
+			t.Fatalf("ToUpper(%q) = %q; want: %q", r, dec(u), unicode.ToUpper(r))
+		}
+		if u := ToLower(s); !reflect.DeepEqual(u, enc(string(unicode.ToLower(r)))) {
+			t.Fatalf("ToLower(%q) = %q; want: %q", r, dec(u), unicode.ToLower(r))
+		}
+		if u := ToTitle(s); !reflect.DeepEqual(u, enc(string(unicode.ToTitle(r)))) {
+			t.Fatalf("ToTitle(%q) = %q; want: %q", r, dec(u), unicode.ToTitle(r))
+		}
+	}
+}
+
+func TestCaseMappingSurrogates(t *testing.T) {
+	s := []uint16{'a', 0xd800, 'b', 0xdc00, 0xd801, 0xdc28}
+	exp := []uint16{'A', 0xd800, 'B', 0xdc00, 0xd801, 0xdc00}
+	if u := ToUpper(s); !reflect.DeepEqual(u, exp) {
+		t.Errorf("ToUpper(%#x) = %#x; want: %#x", s, u, exp)
+	}
+}
+
+func TestLookupSpecial(t *testing.T) {
+	for i := 1; i < len(specialCases); i++ {
+		if specialCases[i-1].r >= specialCases[i].r {
+			t.Fatalf("specialCases: %U is not sorted", specialCases[i].r)
+		}
+	}
+	for _, sc := range specialCases {
+		if p := lookupSpecial(sc.r); p == nil || p.r != sc.r {
+			t.Errorf("lookupSpecial(%U) = %v; want: %v", sc.r, p, sc)
+		}
+	}
+	for _, r := range []rune{0, 'a', 0xDE, 0xE0, 0xFB18, unicode.MaxRune} {
+		if p := lookupSpecial(r); p != nil {
+			t.Errorf("lookupSpecial(%U) = %v; want: nil", r, p)
+		}
+	}
+}
+
+var equalFoldTests = []struct {
+	s, t string
+	out  bool
+}{
+	{"", "", true},
+	{"abc", "abc", true},
+	{"ABcd", "ABcd", true},
+	{"123abc", "123ABC", true},
+	{"αβδ", "ΑΒΔ", true},
+	{"abc", "xyz", false},
+	{"abc", "XYZ", false},
+	{"abc", "ab", false},
+	{"abcdefghijk", "abcdefghijX", false},
+	{"abcdefghijk", "abcdefghijK", true},
+	{"abcdefghijK", "abcdefghijK", true},
+	{"abcdefghijkz", "abcdefghijKy", false},
+	{"abcdefghijKz", "abcdefghijKy", false},
+	{"1", "2", false},
+	{"utf-8", "US-ASCII", false},
+	{"\U00010400", "\U00010428", true},
+	{"\U00010400a", "\U00010428B", false},
+	{"ß", "SS", false},
+	{"ǅ", "ǆ", true},
+	{"Σ", "ς", true},
+}
+
+func TestEqualFold(t *testing.T) {
+	for _, x := range equalFoldTests {
+		if strings.EqualFold(x.s, x.t) != x.out {
+			t.Fatalf("strings.EqualFold(%q, %q) != %t", x.s, x.t, x.out)
+		}
+		if out := EqualFold(enc(x.s), enc(x.t)); out != x.out {
+			t.Errorf("EqualFold(%q, %q) = %t; want: %t", x.s, x.t, out, x.out)
+		}
+		if out := EqualFold(enc(x.t), enc(x.s)); out != x.out {
+			t.Errorf("EqualFold(%q, %q) = %t; want: %t", x.t, x.s, out, x.out)
+		}
+		if out := EqualFoldString(enc(x.s), x.t); out != x.out {
+			t.Errorf("EqualFoldString(%q, %q) = %t; want: %t", x.s, x.t, out, x.out)
+		}
+		if out := EqualFoldString(enc(x.t), x.s); out != x.out {
+			t.Errorf("EqualFoldString(%q, %q) = %t; want: %t", x.t, x.s, out, x.out)
+		}
+	}
+}
+
+func TestEqualFoldSurrogates(t *testing.T) {
+	tests := []struct {
+		s, t []uint16
+		out  bool
+	}{
+		{[]uint16{0xd800}, []uint16{0xd800}, true},
+		{[]uint16{0xd800}, []uint16{0xdc00}, false},
+		{[]uint16{0xd800}, []uint16{0xfffd}, false},
+		{[]uint16{'a', 0xdc00}, []uint16{'A', 0xdc00}, true},
+		{[]uint16{0xd801, 0xdc00}, []uint16{0xd801, 0xdc28}, true},
+		{[]uint16{0xd801}, []uint16{0xd801, 0xdc28}, false},
+	}
+	for _, x := range tests {
+		if out := EqualFold(x.s, x.t); out != x.out {
+			t.Errorf("EqualFold(%#x, %#x) = %t; want: %t", x.s, x.t, out, x.out)
+		}
+	}
+	stringTests := []struct {
+		u   []uint16
+		s   string
+		out bool
+	}{
+		{[]uint16{0xd800}, "\uFFFD", true},
+		{[]uint16{'A', 0xdc00}, "a\xff", true},
+		{[]uint16{'a'}, "A\xff", false},
+		{[]uint16{0xd801, 0xdc00}, "\U00010428", true},
+	}
+	for _, x := range stringTests {
+		if out := EqualFoldString(x.u, x.s); out != x.out {
+			t.Errorf("EqualFoldString(%#x, %q) = %t; want: %t", x.u, x.s, out, x.out)
+		}
+	}
+}
+
+func TestUpcaseTable(t *testing.T) {
+	up := NewUpcaseTable()
+	tests := []struct {
+		s, t string
+		out  bool
+	}{
+		{"readme.TXT", "README.txt", true},
+		{"Straße", "STRASSE", false},
+		{"Straße", "STRAßE", true},
+		{"\U00010428", "\U00010400", false},
+		{"ǆ", "ǅ", true},
+		{"abc", "ab", false},
+	}
+	for _, x := range tests {
+		if out := up.EqualFold(enc(x.s), enc(x.t)); out != x.out {
+			t.Errorf("UpcaseTable.EqualFold(%q, %q) = %t; want: %t", x.s, x.t, out, x.out)
+		}
+		if out := up.EqualFoldString(enc(x.s), x.t); out != x.out {
+			t.Errorf("UpcaseTable.EqualFoldString(%q, %q) = %t; want: %t", x.s, x.t, out, x.out)
+		}
+	}
+	if u := dec(up.ToUpper(enc("straße \U00010428"))); u != "STRAßE \U00010428" {
+		t.Errorf("UpcaseTable.ToUpper = %q; want: %q", u, "STRAßE \U00010428")
+	}
+	if u := up.ToUpper([]uint16{0xd801, 0xdc28, 0xdc28}); !reflect.DeepEqual(u, []uint16{0xd801, 0xdc28, 0xdc28}) {
+		t.Errorf("UpcaseTable.ToUpper: surrogates were mapped: %#x", u)
+	}
+	if !up.EqualFoldString([]uint16{0xfffd}, "\xff") {
+		t.Error("UpcaseTable.EqualFoldString: invalid UTF-8 should equal U+FFFD")
+	}
+}
+
+func TestUpcaseTableCompare(t *testing.T) {
+	up := NewUpcaseTable()
+	tests := []struct {
+		s, t string
+		out  int
+	}{
+		{"", "", 0},
+		{"a", "B", -1},
+		{"B", "a", 1},
+		{"abc", "ABC", 0},
+		{"ab", "ABC", -1},
+		// '_' (0x5F) sorts after the upper case letters
+		{"_", "a", 1},
+		{"\U0001F600", "\uFFFF", -1},
+	}
+	for _, x := range tests {
+		if out := up.Compare(enc(x.s), enc(x.t)); out != x.out {
+			t.Errorf("UpcaseTable.Compare(%q, %q) = %d; want: %d", x.s, x.t, out, x.out)
+		}
+	}
+}
+
+func TestParseUpcaseTable(t *testing.T) {
+	up := NewUpcaseTable()
+	b := make([]byte, 2*len(up))
+	for i, c := range up {
+		b[2*i] = byte(c)
+		b[2*i+1] = byte(c >> 8)
+	}
+	p, err := ParseUpcaseTable(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *p != *up {
+		t.Error("ParseUpcaseTable: table does not match")
+	}
+	if _, err := ParseUpcaseTable(b[1:]); err == nil {
+		t.Error("ParseUpcaseTable: expected error for short table")
+	}
+}
+
+func BenchmarkEqualFold(b *testing.B) {
+	s := enc(strings.Repeat("Hello, 世界 ", 8))
+	u := enc(strings.Repeat("HELLO, 世界 ", 8))
+	for i := 0; i < b.N; i++ {
+		EqualFold(s, u)
+	}
+}
+
+func BenchmarkToUpper(b *testing.B) {
+	s := enc(strings.Repeat("Hello, 世界 ", 8))
+	for i := 0; i < b.N; i++ {
+		ToUpper(s)
+	}
+}
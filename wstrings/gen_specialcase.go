@@ -0,0 +1,90 @@
+//go:build ignore
+
+// This program generates specialcase_tables.go from the unconditional
+// mappings of SpecialCasing.txt. Run it with go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode/utf16"
+
+	"github.com/charlievieth/utfconv/internal/ucd"
+)
+
+var (
+	ucdDir = flag.String("ucd", "../internal/ucd", "directory containing SpecialCasing.txt")
+	output = flag.String("output", "specialcase_tables.go", "output file")
+)
+
+type mapping struct {
+	r                   rune
+	lower, title, upper []rune
+}
+
+func main() {
+	flag.Parse()
+	var maps []mapping
+	err := ucd.Parse(filepath.Join(*ucdDir, "SpecialCasing.txt"), func(f []string) error {
+		if len(f) < 4 {
+			return fmt.Errorf("expected at least 4 fields: %q", f)
+		}
+		if len(f) > 4 && f[4] != "" {
+			return nil // conditional mapping
+		}
+		var m mapping
+		var err error
+		if m.r, err = ucd.Rune(f[0]); err != nil {
+			return err
+		}
+		for i, p := range []*[]rune{&m.lower, &m.title, &m.upper} {
+			if *p, err = ucd.Runes(f[i+1]); err != nil {
+				return err
+			}
+		}
+		maps = append(maps, m)
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Slice(maps, func(i, j int) bool { return maps[i].r < maps[j].r })
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_specialcase.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package wstrings\n\n")
+	buf.WriteString("// specialCases are the unconditional mappings of SpecialCasing.txt\n")
+	buf.WriteString("// sorted by code point.\n")
+	buf.WriteString("var specialCases = [...]specialCase{\n")
+	for _, m := range maps {
+		fmt.Fprintf(&buf, "\t{0x%04X, %s, %s, %s},\n", m.r,
+			units(m.lower), units(m.title), units(m.upper))
+	}
+	buf.WriteString("}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func units(r []rune) string {
+	var buf bytes.Buffer
+	buf.WriteString("[]uint16{")
+	for i, c := range utf16.Encode(r) {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "0x%04X", c)
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
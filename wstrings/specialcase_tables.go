@@ -0,0 +1,111 @@
+// Code generated by gen_specialcase.go; DO NOT EDIT.
+
+package wstrings
+
+// specialCases are the unconditional mappings of SpecialCasing.txt
+// sorted by code point.
+var specialCases = [...]specialCase{
+	{0x00DF, []uint16{0x00DF}, []uint16{0x0053, 0x0073}, []uint16{0x0053, 0x0053}},
+	{0x0130, []uint16{0x0069, 0x0307}, []uint16{0x0130}, []uint16{0x0130}},
+	{0x0149, []uint16{0x0149}, []uint16{0x02BC, 0x004E}, []uint16{0x02BC, 0x004E}},
+	{0x01F0, []uint16{0x01F0}, []uint16{0x004A, 0x030C}, []uint16{0x004A, 0x030C}},
+	{0x0390, []uint16{0x0390}, []uint16{0x0399, 0x0308, 0x0301}, []uint16{0x0399, 0x0308, 0x0301}},
+	{0x03B0, []uint16{0x03B0}, []uint16{0x03A5, 0x0308, 0x0301}, []uint16{0x03A5, 0x0308, 0x0301}},
+	{0x0587, []uint16{0x0587}, []uint16{0x0535, 0x0582}, []uint16{0x0535, 0x0552}},
+	{0x1E96, []uint16{0x1E96}, []uint16{0x0048, 0x0331}, []uint16{0x0048, 0x0331}},
+	{0x1E97, []uint16{0x1E97}, []uint16{0x0054, 0x0308}, []uint16{0x0054, 0x0308}},
+	{0x1E98, []uint16{0x1E98}, []uint16{0x0057, 0x030A}, []uint16{0x0057, 0x030A}},
+	{0x1E99, []uint16{0x1E99}, []uint16{0x0059, 0x030A}, []uint16{0x0059, 0x030A}},
+	{0x1E9A, []uint16{0x1E9A}, []uint16{0x0041, 0x02BE}, []uint16{0x0041, 0x02BE}},
+	{0x1F50, []uint16{0x1F50}, []uint16{0x03A5, 0x0313}, []uint16{0x03A5, 0x0313}},
+	{0x1F52, []uint16{0x1F52}, []uint16{0x03A5, 0x0313, 0x0300}, []uint16{0x03A5, 0x0313, 0x0300}},
+	{0x1F54, []uint16{0x1F54}, []uint16{0x03A5, 0x0313, 0x0301}, []uint16{0x03A5, 0x0313, 0x0301}},
+	{0x1F56, []uint16{0x1F56}, []uint16{0x03A5, 0x0313, 0x0342}, []uint16{0x03A5, 0x0313, 0x0342}},
+	{0x1F80, []uint16{0x1F80}, []uint16{0x1F88}, []uint16{0x1F08, 0x0399}},
+	{0x1F81, []uint16{0x1F81}, []uint16{0x1F89}, []uint16{0x1F09, 0x0399}},
+	{0x1F82, []uint16{0x1F82}, []uint16{0x1F8A}, []uint16{0x1F0A, 0x0399}},
+	{0x1F83, []uint16{0x1F83}, []uint16{0x1F8B}, []uint16{0x1F0B, 0x0399}},
+	{0x1F84, []uint16{0x1F84}, []uint16{0x1F8C}, []uint16{0x1F0C, 0x0399}},
+	{0x1F85, []uint16{0x1F85}, []uint16{0x1F8D}, []uint16{0x1F0D, 0x0399}},
+	{0x1F86, []uint16{0x1F86}, []uint16{0x1F8E}, []uint16{0x1F0E, 0x0399}},
+	{0x1F87, []uint16{0x1F87}, []uint16{0x1F8F}, []uint16{0x1F0F, 0x0399}},
+	{0x1F88, []uint16{0x1F80}, []uint16{0x1F88}, []uint16{0x1F08, 0x0399}},
+	{0x1F89, []uint16{0x1F81}, []uint16{0x1F89}, []uint16{0x1F09, 0x0399}},
+	{0x1F8A, []uint16{0x1F82}, []uint16{0x1F8A}, []uint16{0x1F0A, 0x0399}},
+	{0x1F8B, []uint16{0x1F83}, []uint16{0x1F8B}, []uint16{0x1F0B, 0x0399}},
+	{0x1F8C, []uint16{0x1F84}, []uint16{0x1F8C}, []uint16{0x1F0C, 0x0399}},
+	{0x1F8D, []uint16{0x1F85}, []uint16{0x1F8D}, []uint16{0x1F0D, 0x0399}},
+	{0x1F8E, []uint16{0x1F86}, []uint16{0x1F8E}, []uint16{0x1F0E, 0x0399}},
+	{0x1F8F, []uint16{0x1F87}, []uint16{0x1F8F}, []uint16{0x1F0F, 0x0399}},
+	{0x1F90, []uint16{0x1F90}, []uint16{0x1F98}, []uint16{0x1F28, 0x0399}},
+	{0x1F91, []uint16{0x1F91}, []uint16{0x1F99}, []uint16{0x1F29, 0x0399}},
+	{0x1F92, []uint16{0x1F92}, []uint16{0x1F9A}, []uint16{0x1F2A, 0x0399}},
+	{0x1F93, []uint16{0x1F93}, []uint16{0x1F9B}, []uint16{0x1F2B, 0x0399}},
+	{0x1F94, []uint16{0x1F94}, []uint16{0x1F9C}, []uint16{0x1F2C, 0x0399}},
+	{0x1F95, []uint16{0x1F95}, []uint16{0x1F9D}, []uint16{0x1F2D, 0x0399}},
+	{0x1F96, []uint16{0x1F96}, []uint16{0x1F9E}, []uint16{0x1F2E, 0x0399}},
+	{0x1F97, []uint16{0x1F97}, []uint16{0x1F9F}, []uint16{0x1F2F, 0x0399}},
+	{0x1F98, []uint16{0x1F90}, []uint16{0x1F98}, []uint16{0x1F28, 0x0399}},
+	{0x1F99, []uint16{0x1F91}, []uint16{0x1F99}, []uint16{0x1F29, 0x0399}},
+	{0x1F9A, []uint16{0x1F92}, []uint16{0x1F9A}, []uint16{0x1F2A, 0x0399}},
+	{0x1F9B, []uint16{0x1F93}, []uint16{0x1F9B}, []uint16{0x1F2B, 0x0399}},
+	{0x1F9C, []uint16{0x1F94}, []uint16{0x1F9C}, []uint16{0x1F2C, 0x0399}},
+	{0x1F9D, []uint16{0x1F95}, []uint16{0x1F9D}, []uint16{0x1F2D, 0x0399}},
+	{0x1F9E, []uint16{0x1F96}, []uint16{0x1F9E}, []uint16{0x1F2E, 0x0399}},
+	{0x1F9F, []uint16{0x1F97}, []uint16{0x1F9F}, []uint16{0x1F2F, 0x0399}},
+	{0x1FA0, []uint16{0x1FA0}, []uint16{0x1FA8}, []uint16{0x1F68, 0x0399}},
+	{0x1FA1, []uint16{0x1FA1}, []uint16{0x1FA9}, []uint16{0x1F69, 0x0399}},
+	{0x1FA2, []uint16{0x1FA2}, []uint16{0x1FAA}, []uint16{0x1F6A, 0x0399}},
+	{0x1FA3, []uint16{0x1FA3}, []uint16{0x1FAB}, []uint16{0x1F6B, 0x0399}},
+	{0x1FA4, []uint16{0x1FA4}, []uint16{0x1FAC}, []uint16{0x1F6C, 0x0399}},
+	{0x1FA5, []uint16{0x1FA5}, []uint16{0x1FAD}, []uint16{0x1F6D, 0x0399}},
+	{0x1FA6, []uint16{0x1FA6}, []uint16{0x1FAE}, []uint16{0x1F6E, 0x0399}},
+	{0x1FA7, []uint16{0x1FA7}, []uint16{0x1FAF}, []uint16{0x1F6F, 0x0399}},
+	{0x1FA8, []uint16{0x1FA0}, []uint16{0x1FA8}, []uint16{0x1F68, 0x0399}},
+	{0x1FA9, []uint16{0x1FA1}, []uint16{0x1FA9}, []uint16{0x1F69, 0x0399}},
+	{0x1FAA, []uint16{0x1FA2}, []uint16{0x1FAA}, []uint16{0x1F6A, 0x0399}},
+	{0x1FAB, []uint16{0x1FA3}, []uint16{0x1FAB}, []uint16{0x1F6B, 0x0399}},
+	{0x1FAC, []uint16{0x1FA4}, []uint16{0x1FAC}, []uint16{0x1F6C, 0x0399}},
+	{0x1FAD, []uint16{0x1FA5}, []uint16{0x1FAD}, []uint16{0x1F6D, 0x0399}},
+	{0x1FAE, []uint16{0x1FA6}, []uint16{0x1FAE}, []uint16{0x1F6E, 0x0399}},
+	{0x1FAF, []uint16{0x1FA7}, []uint16{0x1FAF}, []uint16{0x1F6F, 0x0399}},
+	{0x1FB2, []uint16{0x1FB2}, []uint16{0x1FBA, 0x0345}, []uint16{0x1FBA, 0x0399}},
+	{0x1FB3, []uint16{0x1FB3}, []uint16{0x1FBC}, []uint16{0x0391, 0x0399}},
+	{0x1FB4, []uint16{0x1FB4}, []uint16{0x0386, 0x0345}, []uint16{0x0386, 0x0399}},
+	{0x1FB6, []uint16{0x1FB6}, []uint16{0x0391, 0x0342}, []uint16{0x0391, 0x0342}},
+	{0x1FB7, []uint16{0x1FB7}, []uint16{0x0391, 0x0342, 0x0345}, []uint16{0x0391, 0x0342, 0x0399}},
+	{0x1FBC, []uint16{0x1FB3}, []uint16{0x1FBC}, []uint16{0x0391, 0x0399}},
+	{0x1FC2, []uint16{0x1FC2}, []uint16{0x1FCA, 0x0345}, []uint16{0x1FCA, 0x0399}},
+	{0x1FC3, []uint16{0x1FC3}, []uint16{0x1FCC}, []uint16{0x0397, 0x0399}},
+	{0x1FC4, []uint16{0x1FC4}, []uint16{0x0389, 0x0345}, []uint16{0x0389, 0x0399}},
+	{0x1FC6, []uint16{0x1FC6}, []uint16{0x0397, 0x0342}, []uint16{0x0397, 0x0342}},
+	{0x1FC7, []uint16{0x1FC7}, []uint16{0x0397, 0x0342, 0x0345}, []uint16{0x0397, 0x0342, 0x0399}},
+	{0x1FCC, []uint16{0x1FC3}, []uint16{0x1FCC}, []uint16{0x0397, 0x0399}},
+	{0x1FD2, []uint16{0x1FD2}, []uint16{0x0399, 0x0308, 0x0300}, []uint16{0x0399, 0x0308, 0x0300}},
+	{0x1FD3, []uint16{0x1FD3}, []uint16{0x0399, 0x0308, 0x0301}, []uint16{0x0399, 0x0308, 0x0301}},
+	{0x1FD6, []uint16{0x1FD6}, []uint16{0x0399, 0x0342}, []uint16{0x0399, 0x0342}},
+	{0x1FD7, []uint16{0x1FD7}, []uint16{0x0399, 0x0308, 0x0342}, []uint16{0x0399, 0x0308, 0x0342}},
+	{0x1FE2, []uint16{0x1FE2}, []uint16{0x03A5, 0x0308, 0x0300}, []uint16{0x03A5, 0x0308, 0x0300}},
+	{0x1FE3, []uint16{0x1FE3}, []uint16{0x03A5, 0x0308, 0x0301}, []uint16{0x03A5, 0x0308, 0x0301}},
+	{0x1FE4, []uint16{0x1FE4}, []uint16{0x03A1, 0x0313}, []uint16{0x03A1, 0x0313}},
+	{0x1FE6, []uint16{0x1FE6}, []uint16{0x03A5, 0x0342}, []uint16{0x03A5, 0x0342}},
+	{0x1FE7, []uint16{0x1FE7}, []uint16{0x03A5, 0x0308, 0x0342}, []uint16{0x03A5, 0x0308, 0x0342}},
+	{0x1FF2, []uint16{0x1FF2}, []uint16{0x1FFA, 0x0345}, []uint16{0x1FFA, 0x0399}},
+	{0x1FF3, []uint16{0x1FF3}, []uint16{0x1FFC}, []uint16{0x03A9, 0x0399}},
+	{0x1FF4, []uint16{0x1FF4}, []uint16{0x038F, 0x0345}, []uint16{0x038F, 0x0399}},
+	{0x1FF6, []uint16{0x1FF6}, []uint16{0x03A9, 0x0342}, []uint16{0x03A9, 0x0342}},
+	{0x1FF7, []uint16{0x1FF7}, []uint16{0x03A9, 0x0342, 0x0345}, []uint16{0x03A9, 0x0342, 0x0399}},
+	{0x1FFC, []uint16{0x1FF3}, []uint16{0x1FFC}, []uint16{0x03A9, 0x0399}},
+	{0xFB00, []uint16{0xFB00}, []uint16{0x0046, 0x0066}, []uint16{0x0046, 0x0046}},
+	{0xFB01, []uint16{0xFB01}, []uint16{0x0046, 0x0069}, []uint16{0x0046, 0x0049}},
+	{0xFB02, []uint16{0xFB02}, []uint16{0x0046, 0x006C}, []uint16{0x0046, 0x004C}},
+	{0xFB03, []uint16{0xFB03}, []uint16{0x0046, 0x0066, 0x0069}, []uint16{0x0046, 0x0046, 0x0049}},
+	{0xFB04, []uint16{0xFB04}, []uint16{0x0046, 0x0066, 0x006C}, []uint16{0x0046, 0x0046, 0x004C}},
+	{0xFB05, []uint16{0xFB05}, []uint16{0x0053, 0x0074}, []uint16{0x0053, 0x0054}},
+	{0xFB06, []uint16{0xFB06}, []uint16{0x0053, 0x0074}, []uint16{0x0053, 0x0054}},
+	{0xFB13, []uint16{0xFB13}, []uint16{0x0544, 0x0576}, []uint16{0x0544, 0x0546}},
+	{0xFB14, []uint16{0xFB14}, []uint16{0x0544, 0x0565}, []uint16{0x0544, 0x0535}},
+	{0xFB15, []uint16{0xFB15}, []uint16{0x0544, 0x056B}, []uint16{0x0544, 0x053B}},
+	{0xFB16, []uint16{0xFB16}, []uint16{0x054E, 0x0576}, []uint16{0x054E, 0x0546}},
+	{0xFB17, []uint16{0xFB17}, []uint16{0x0544, 0x056D}, []uint16{0x0544, 0x053D}},
+}
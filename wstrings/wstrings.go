@@ -0,0 +1,328 @@
+// Package wstrings implements functions to manipulate UTF-16 encoded strings
+// stored as []uint16, such as those returned by Windows APIs, without first
+// converting them to Go strings.
+//
+// The functions mirror those of package strings. Functions that take a
+// needle come in two forms: one taking a []uint16 and one, with a String
+// suffix, taking a Go string. Matches never start or end between the two code
+// units of a surrogate pair.
+package wstrings
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charlievieth/utfconv"
+)
+
+// decodeLastRune returns the last rune of s and its length in code units,
+// the reverse of utfconv.DecodeRuneUTF16.
+func decodeLastRune(s []uint16) (rune, int) {
+	if n := len(s); n > 1 {
+		if r, size := utfconv.DecodeRuneUTF16(s[n-2:]); size == 2 {
+			return r, size
+		}
+		s = s[n-1:]
+	}
+	return utfconv.DecodeRuneUTF16(s)
+}
+
+// splitsPair reports whether index i of s falls between the two code units
+// of a surrogate pair.
+func splitsPair(s []uint16, i int) bool {
+	if i <= 0 || i >= len(s) {
+		return false
+	}
+	_, n := utfconv.DecodeRuneUTF16(s[i-1 : i+1])
+	return n == 2
+}
+
+func equal(a, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Index returns the index of the first instance of sep in s, or -1 if sep is
+// not present in s.
+func Index(s, sep []uint16) int {
+	n := len(sep)
+	if n == 0 {
+		return 0
+	}
+	c := sep[0]
+	for i := 0; i+n <= len(s); i++ {
+		if s[i] == c && equal(s[i:i+n], sep) && !splitsPair(s, i) && !splitsPair(s, i+n) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexString returns the index of the first instance of sep in s, or -1 if
+// sep is not present in s.
+func IndexString(s []uint16, sep string) int {
+	return Index(s, utfconv.StringToUTF16(sep))
+}
+
+// LastIndex returns the index of the last instance of sep in s, or -1 if sep
+// is not present in s.
+func LastIndex(s, sep []uint16) int {
+	n := len(sep)
+	if n == 0 {
+		return len(s)
+	}
+	for i := len(s) - n; i >= 0; i-- {
+		if equal(s[i:i+n], sep) && !splitsPair(s, i) && !splitsPair(s, i+n) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastIndexString returns the index of the last instance of sep in s, or -1
+// if sep is not present in s.
+func LastIndexString(s []uint16, sep string) int {
+	return LastIndex(s, utfconv.StringToUTF16(sep))
+}
+
+// IndexRune returns the index of the first instance of the Unicode code
+// point r in s, or -1 if it is not present. If r is utf8.RuneError, it
+// returns the first instance of U+FFFD or an unpaired surrogate.
+func IndexRune(s []uint16, r rune) int {
+	switch utfconv.RuneLenUTF16(r) {
+	case 1:
+		if r == utf8.RuneError {
+			for i := 0; i < len(s); {
+				c, n := utfconv.DecodeRuneUTF16(s[i:])
+				if c == utf8.RuneError {
+					return i
+				}
+				i += n
+			}
+			return -1
+		}
+		for i, c := range s {
+			if rune(c) == r {
+				return i
+			}
+		}
+		return -1
+	case 2:
+		return Index(s, utfconv.AppendRuneUTF16(nil, r))
+	}
+	return -1
+}
+
+// Contains reports whether sep is within s.
+func Contains(s, sep []uint16) bool { return Index(s, sep) >= 0 }
+
+// ContainsString reports whether sep is within s.
+func ContainsString(s []uint16, sep string) bool { return IndexString(s, sep) >= 0 }
+
+// ContainsRune reports whether the Unicode code point r is within s.
+func ContainsRune(s []uint16, r rune) bool { return IndexRune(s, r) >= 0 }
+
+// HasPrefix reports whether s begins with prefix.
+func HasPrefix(s, prefix []uint16) bool {
+	return len(s) >= len(prefix) && equal(s[:len(prefix)], prefix) &&
+		!splitsPair(s, len(prefix))
+}
+
+// HasPrefixString reports whether s begins with prefix.
+func HasPrefixString(s []uint16, prefix string) bool {
+	return HasPrefix(s, utfconv.StringToUTF16(prefix))
+}
+
+// HasSuffix reports whether s ends with suffix.
+func HasSuffix(s, suffix []uint16) bool {
+	i := len(s) - len(suffix)
+	return i >= 0 && equal(s[i:], suffix) && !splitsPair(s, i)
+}
+
+// HasSuffixString reports whether s ends with suffix.
+func HasSuffixString(s []uint16, suffix string) bool {
+	return HasSuffix(s, utfconv.StringToUTF16(suffix))
+}
+
+// explode splits s into its runes, up to n of them.
+func explode(s []uint16, n int) [][]uint16 {
+	if n < 0 || n > len(s) {
+		n = len(s)
+	}
+	a := make([][]uint16, 0, n)
+	for len(s) > 0 {
+		if len(a) == n-1 {
+			a = append(a, s)
+			break
+		}
+		_, size := utfconv.DecodeRuneUTF16(s)
+		a = append(a, s[:size:size])
+		s = s[size:]
+	}
+	return a
+}
+
+// SplitN slices s into subslices separated by sep and returns a slice of
+// the subslices between those separators. The count determines the number
+// of subslices to return, as with strings.SplitN. The subslices share the
+// underlying array of s.
+func SplitN(s, sep []uint16, n int) [][]uint16 {
+	if n == 0 {
+		return nil
+	}
+	if len(sep) == 0 {
+		return explode(s, n)
+	}
+	if n < 0 || n > count(s, sep)+1 {
+		n = count(s, sep) + 1
+	}
+	a := make([][]uint16, 0, n)
+	for len(a) < n-1 {
+		m := Index(s, sep)
+		if m < 0 {
+			break
+		}
+		a = append(a, s[:m:m])
+		s = s[m+len(sep):]
+	}
+	return append(a, s)
+}
+
+// Split slices s into all subslices separated by sep, see SplitN.
+func Split(s, sep []uint16) [][]uint16 { return SplitN(s, sep, -1) }
+
+// SplitString slices s into all subslices separated by sep, see SplitN.
+func SplitString(s []uint16, sep string) [][]uint16 {
+	return SplitN(s, utfconv.StringToUTF16(sep), -1)
+}
+
+// count returns the number of non-overlapping instances of sep in s.
+func count(s, sep []uint16) int {
+	n := 0
+	for {
+		i := Index(s, sep)
+		if i < 0 {
+			return n
+		}
+		n++
+		s = s[i+len(sep):]
+	}
+}
+
+// Count counts the number of non-overlapping instances of sep in s. If sep
+// is empty, Count returns 1 + the number of runes in s.
+func Count(s, sep []uint16) int {
+	if len(sep) == 0 {
+		n := 0
+		for len(s) > 0 {
+			_, size := utfconv.DecodeRuneUTF16(s)
+			s = s[size:]
+			n++
+		}
+		return n + 1
+	}
+	return count(s, sep)
+}
+
+// Fields splits s around each instance of one or more consecutive white
+// space characters, as defined by unicode.IsSpace.
+func Fields(s []uint16) [][]uint16 {
+	return FieldsFunc(s, unicode.IsSpace)
+}
+
+// FieldsFunc splits s at each run of code points c satisfying f(c).
+func FieldsFunc(s []uint16, f func(rune) bool) [][]uint16 {
+	var a [][]uint16
+	start := -1
+	for i := 0; i < len(s); {
+		r, size := utfconv.DecodeRuneUTF16(s[i:])
+		if f(r) {
+			if start >= 0 {
+				a = append(a, s[start:i:i])
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+		i += size
+	}
+	if start >= 0 {
+		a = append(a, s[start:])
+	}
+	return a
+}
+
+// TrimSpace returns a subslice of s with all leading and trailing white
+// space removed, as defined by unicode.IsSpace.
+func TrimSpace(s []uint16) []uint16 {
+	return TrimFunc(s, unicode.IsSpace)
+}
+
+// TrimFunc returns a subslice of s with all leading and trailing code points
+// c satisfying f(c) removed.
+func TrimFunc(s []uint16, f func(rune) bool) []uint16 {
+	for len(s) > 0 {
+		r, size := utfconv.DecodeRuneUTF16(s)
+		if !f(r) {
+			break
+		}
+		s = s[size:]
+	}
+	for len(s) > 0 {
+		r, size := decodeLastRune(s)
+		if !f(r) {
+			break
+		}
+		s = s[:len(s)-size]
+	}
+	return s
+}
+
+// Replace returns a copy of s with the first n non-overlapping instances of
+// old replaced by new. If old is empty, it matches at the beginning of s and
+// after each rune. If n < 0, there is no limit on the number of replacements.
+func Replace(s, old, new []uint16, n int) []uint16 {
+	if n == 0 || equal(old, new) {
+		return append([]uint16(nil), s...)
+	}
+	if m := Count(s, old); m == 0 {
+		return append([]uint16(nil), s...)
+	} else if n < 0 || m < n {
+		n = m
+	}
+	a := make([]uint16, 0, len(s)+n*(len(new)-len(old)))
+	for i := 0; i < n; i++ {
+		j := len(s)
+		if len(old) == 0 {
+			if i > 0 {
+				_, size := utfconv.DecodeRuneUTF16(s)
+				a = append(a, s[:size]...)
+				s = s[size:]
+			}
+			j = 0
+		} else {
+			j = Index(s, old)
+		}
+		a = append(a, s[:j]...)
+		a = append(a, new...)
+		s = s[j+len(old):]
+	}
+	return append(a, s...)
+}
+
+// ReplaceString is like Replace but takes Go string arguments for old and
+// new.
+func ReplaceString(s []uint16, old, new string, n int) []uint16 {
+	return Replace(s, utfconv.StringToUTF16(old), utfconv.StringToUTF16(new), n)
+}
+
+// ReplaceAll returns a copy of s with all non-overlapping instances of old
+// replaced by new.
+func ReplaceAll(s, old, new []uint16) []uint16 { return Replace(s, old, new, -1) }
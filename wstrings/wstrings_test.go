@@ -0,0 +1,216 @@
+package wstrings
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func enc(s string) []uint16 { return utf16.Encode([]rune(s)) }
+
+func dec(s []uint16) string { return string(utf16.Decode(s)) }
+
+func decAll(a [][]uint16) []string {
+	if a == nil {
+		return nil
+	}
+	s := make([]string, len(a))
+	for i, u := range a {
+		s[i] = dec(u)
+	}
+	return s
+}
+
+var indexTests = []struct {
+	s, sep string
+}{
+	{"", ""},
+	{"", "a"},
+	{"abc", ""},
+	{"abc", "a"},
+	{"abc", "c"},
+	{"abc", "bc"},
+	{"abc", "abcd"},
+	{"abcabc", "ca"},
+	{"日本語日本語", "本語"},
+	{"a\U0001F600b\U0001F600", "\U0001F600"},
+	{"a\U0001F600b\U0001F600", "b\U0001F600"},
+	{"x\U0001F600", "\U0001F601"},
+}
+
+func TestIndex(t *testing.T) {
+	for _, x := range indexTests {
+		// strings.Index returns a byte index, compare UTF-16 indexes
+		exp := strings.Index(x.s, x.sep)
+		if exp >= 0 {
+			exp = len(enc(x.s[:exp]))
+		}
+		if i := Index(enc(x.s), enc(x.sep)); i != exp {
+			t.Errorf("Index(%q, %q) = %d; want: %d", x.s, x.sep, i, exp)
+		}
+		if i := IndexString(enc(x.s), x.sep); i != exp {
+			t.Errorf("IndexString(%q, %q) = %d; want: %d", x.s, x.sep, i, exp)
+		}
+		if c := Contains(enc(x.s), enc(x.sep)); c != (exp >= 0) {
+			t.Errorf("Contains(%q, %q) = %t; want: %t", x.s, x.sep, c, exp >= 0)
+		}
+		exp = strings.LastIndex(x.s, x.sep)
+		if exp >= 0 {
+			exp = len(enc(x.s[:exp]))
+		}
+		if i := LastIndex(enc(x.s), enc(x.sep)); i != exp {
+			t.Errorf("LastIndex(%q, %q) = %d; want: %d", x.s, x.sep, i, exp)
+		}
+	}
+}
+
+func TestIndexSurrogates(t *testing.T) {
+	s := enc("a\U0001F600b")
+	hi, lo := s[1:2], s[2:3]
+	if i := Index(s, hi); i != -1 {
+		t.Errorf("Index(%v, %v) = %d; want: -1", s, hi, i)
+	}
+	if i := Index(s, lo); i != -1 {
+		t.Errorf("Index(%v, %v) = %d; want: -1", s, lo, i)
+	}
+	if HasPrefix(s, s[:2]) {
+		t.Errorf("HasPrefix(%v, %v) = true; want: false", s, s[:2])
+	}
+	if HasSuffix(s, s[2:]) {
+		t.Errorf("HasSuffix(%v, %v) = true; want: false", s, s[2:])
+	}
+	// unpaired surrogates match
+	u := []uint16{'a', 0xd800, 'b'}
+	if i := Index(u, []uint16{0xd800}); i != 1 {
+		t.Errorf("Index(%v, [0xd800]) = %d; want: 1", u, i)
+	}
+}
+
+func TestIndexRune(t *testing.T) {
+	tests := []struct {
+		s   []uint16
+		r   rune
+		exp int
+	}{
+		{enc("abc"), 'c', 2},
+		{enc("abc"), 'd', -1},
+		{enc("日本語"), '語', 2},
+		{enc("a\U0001F600"), 0x1F600, 1},
+		{enc("a\U0001F600"), 0xd83d, -1},
+		{enc("a\U0001F600"), -1, -1},
+		{enc("a\uFFFD"), utf8.RuneError, 1},
+		{[]uint16{'a', 0xdc00}, utf8.RuneError, 1},
+		{enc("a\U0001F600"), utf8.RuneError, -1},
+	}
+	for _, x := range tests {
+		if i := IndexRune(x.s, x.r); i != x.exp {
+			t.Errorf("IndexRune(%q, %#x) = %d; want: %d", dec(x.s), x.r, i, x.exp)
+		}
+	}
+}
+
+func TestHasPrefixSuffix(t *testing.T) {
+	for _, x := range indexTests {
+		if b := HasPrefix(enc(x.s), enc(x.sep)); b != strings.HasPrefix(x.s, x.sep) {
+			t.Errorf("HasPrefix(%q, %q) = %t", x.s, x.sep, b)
+		}
+		if b := HasPrefixString(enc(x.s), x.sep); b != strings.HasPrefix(x.s, x.sep) {
+			t.Errorf("HasPrefixString(%q, %q) = %t", x.s, x.sep, b)
+		}
+		if b := HasSuffix(enc(x.s), enc(x.sep)); b != strings.HasSuffix(x.s, x.sep) {
+			t.Errorf("HasSuffix(%q, %q) = %t", x.s, x.sep, b)
+		}
+		if b := HasSuffixString(enc(x.s), x.sep); b != strings.HasSuffix(x.s, x.sep) {
+			t.Errorf("HasSuffixString(%q, %q) = %t", x.s, x.sep, b)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		s, sep string
+		n      int
+	}{
+		{"a,b,c", ",", -1},
+		{"a,b,c", ",", 0},
+		{"a,b,c", ",", 1},
+		{"a,b,c", ",", 2},
+		{"a,b,c", ",", 10},
+		{"a,b,c", ",", math.MaxInt},
+		{"a,b,c", ",", 1 << 40},
+		{",a,,b,", ",", -1},
+		{"abc", "", -1},
+		{"a\U0001F600b", "", -1},
+		{"a\U0001F600b", "", 2},
+		{"a\U0001F600b", "", math.MaxInt},
+		{"日,本,語", "本", -1},
+		{"\U0001F600x\U0001F600", "x", -1},
+		{"", ",", -1},
+	}
+	for _, x := range tests {
+		exp := strings.SplitN(x.s, x.sep, x.n)
+		got := decAll(SplitN(enc(x.s), enc(x.sep), x.n))
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("SplitN(%q, %q, %d) = %q; want: %q", x.s, x.sep, x.n, got, exp)
+		}
+		if x.n < 0 {
+			got = decAll(SplitString(enc(x.s), x.sep))
+			if !reflect.DeepEqual(got, exp) {
+				t.Errorf("SplitString(%q, %q) = %q; want: %q", x.s, x.sep, got, exp)
+			}
+			if n := Count(enc(x.s), enc(x.sep)); n != strings.Count(x.s, x.sep) {
+				t.Errorf("Count(%q, %q) = %d; want: %d", x.s, x.sep, n, strings.Count(x.s, x.sep))
+			}
+		}
+	}
+}
+
+func TestFieldsTrimSpace(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"   ",
+		"abc",
+		" a b\tc\n",
+		"　日本 語 ",
+		"\U0001F600 \U0001F601",
+	} {
+		if got, exp := decAll(Fields(enc(s))), strings.Fields(s); !reflect.DeepEqual(got, exp) &&
+			!(len(got) == 0 && len(exp) == 0) {
+			t.Errorf("Fields(%q) = %q; want: %q", s, got, exp)
+		}
+		if got, exp := dec(TrimSpace(enc(s))), strings.TrimSpace(s); got != exp {
+			t.Errorf("TrimSpace(%q) = %q; want: %q", s, got, exp)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		s, old, new string
+		n           int
+	}{
+		{"hello", "l", "L", 0},
+		{"hello", "l", "L", -1},
+		{"hello", "x", "L", -1},
+		{"hello", "", "<>", -1},
+		{"hello", "", "<>", 1},
+		{"hello", "l", "", -1},
+		{"banana", "a", "<>", 2},
+		{"banana", "ana", "<>", -1},
+		{"日本語", "", "-", -1},
+		{"a\U0001F600b\U0001F600", "\U0001F600", "\U0001F610", -1},
+		{"a\U0001F600b", "", ".", -1},
+	}
+	for _, x := range tests {
+		exp := strings.Replace(x.s, x.old, x.new, x.n)
+		if got := dec(Replace(enc(x.s), enc(x.old), enc(x.new), x.n)); got != exp {
+			t.Errorf("Replace(%q, %q, %q, %d) = %q; want: %q", x.s, x.old, x.new, x.n, got, exp)
+		}
+		if got := dec(ReplaceString(enc(x.s), x.old, x.new, x.n)); got != exp {
+			t.Errorf("ReplaceString(%q, %q, %q, %d) = %q; want: %q", x.s, x.old, x.new, x.n, got, exp)
+		}
+	}
+}